@@ -0,0 +1,317 @@
+package delivery
+
+import (
+	"errors"
+	"mygram-byferdiansyah/admin/utils"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type adminHandler struct {
+	adminUseCase domain.AdminUseCase
+}
+
+func NewAdminHandler(routers *gin.Engine, adminUseCase domain.AdminUseCase, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, rateLimiter *middleware.RateLimiter) {
+	handler := &adminHandler{adminUseCase}
+
+	router := routers.Group("/admin")
+	{
+		router.Use(rateLimiter.LimitAddress(domain.RateLimitDefault))
+		router.Use(middleware.Authentication(tokenManager, tokenRevocationUseCase))
+		router.Use(rateLimiter.Limit(domain.RateLimitDefault))
+
+		moderator := router.Group("", middleware.RequireRole(domain.RoleModerator, domain.RoleAdmin))
+		{
+			moderator.GET("/images/duplicates", handler.GetDuplicateImages)
+			moderator.DELETE("/images/:imageId", handler.DeleteImage)
+			moderator.DELETE("/comments/:commentId", handler.DeleteComment)
+		}
+
+		admin := router.Group("", middleware.RequireRole(domain.RoleAdmin))
+		{
+			admin.GET("/users", handler.GetUsers)
+			admin.PUT("/users/:userId/suspend", handler.SuspendUser)
+			admin.PUT("/users/:userId/restore", handler.RestoreUser)
+			admin.PUT("/users/:userId/role", handler.SetRole)
+			admin.GET("/audit-logs", handler.GetAuditLogs)
+		}
+	}
+}
+
+// DeleteImage godoc
+// @Summary			Delete any image
+// @Description	Delete an image of any user, available to moderators and admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id  path			string	true	"Image ID"
+// @Success     200 {object}	utils.ResponseMessageModerated
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/images/{id}	[delete]
+func (handler *adminHandler) DeleteImage(ctx *gin.Context) {
+	if err := handler.adminUseCase.DeleteImage(ctx.Request.Context(), ctx.Param("imageId")); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the image has been deleted",
+	})
+}
+
+// GetDuplicateImages godoc
+// @Summary			Get near-duplicate images
+// @Description	Get pairs of images across all users whose perceptual hashes differ by at most distance bits, available to moderators and admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       distance	query			int	false	"Maximum Hamming distance, from 0 to 3"	default(3)
+// @Success     200	{object}	utils.ResponseDataImageDuplicates
+// @Failure     400	{object}	utils.ResponseMessage
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/images/duplicates	[get]
+func (handler *adminHandler) GetDuplicateImages(ctx *gin.Context) {
+	var duplicates []domain.ImageDuplicate
+
+	distance, err := strconv.Atoi(ctx.DefaultQuery("distance", strconv.Itoa(domain.MaxDuplicateDistance)))
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: domain.ErrDistanceOutOfRange.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.adminUseCase.GetDuplicateImages(ctx.Request.Context(), &duplicates, distance); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	if duplicates == nil {
+		duplicates = []domain.ImageDuplicate{}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   duplicates,
+	})
+}
+
+// DeleteComment godoc
+// @Summary			Delete any comment
+// @Description	Delete a comment of any user, available to moderators and admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id  path			string	true	"Comment ID"
+// @Success     200 {object}	utils.ResponseMessageModerated
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/comments/{id}	[delete]
+func (handler *adminHandler) DeleteComment(ctx *gin.Context) {
+	if err := handler.adminUseCase.DeleteComment(ctx.Request.Context(), ctx.Param("commentId")); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the comment has been deleted",
+	})
+}
+
+// GetUsers godoc
+// @Summary			Get all users
+// @Description	Get every user account with its role and suspension, available to admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Success     200	{object}	utils.ResponseDataAdminUsers
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/users	[get]
+func (handler *adminHandler) GetUsers(ctx *gin.Context) {
+	var users []domain.User
+
+	if err := handler.adminUseCase.GetUsers(ctx.Request.Context(), &users); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	adminUsers := make([]utils.AdminUser, 0, len(users))
+
+	for _, user := range users {
+		adminUsers = append(adminUsers, utils.AdminUser{
+			ID:          user.ID,
+			Username:    user.Username,
+			Email:       user.Email,
+			Role:        user.Roles()[0],
+			SuspendedAt: user.SuspendedAt,
+			CreatedAt:   user.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   adminUsers,
+	})
+}
+
+// SuspendUser godoc
+// @Summary			Suspend a user
+// @Description	Block a user from signing in and sign it out everywhere, available to admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id  path			string	true	"User ID"
+// @Success     200 {object}	utils.ResponseMessageModerated
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/users/{id}/suspend	[put]
+func (handler *adminHandler) SuspendUser(ctx *gin.Context) {
+	if err := handler.adminUseCase.SuspendUser(ctx.Request.Context(), ctx.Param("userId")); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the user has been suspended",
+	})
+}
+
+// RestoreUser godoc
+// @Summary			Restore a user
+// @Description	Lift the suspension of a user, available to admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id  path			string	true	"User ID"
+// @Success     200 {object}	utils.ResponseMessageModerated
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/users/{id}/restore	[put]
+func (handler *adminHandler) RestoreUser(ctx *gin.Context) {
+	if err := handler.adminUseCase.RestoreUser(ctx.Request.Context(), ctx.Param("userId")); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the user has been restored",
+	})
+}
+
+// SetRole godoc
+// @Summary			Change the role of a user
+// @Description	Set the role of a user to user, moderator or admin, available to admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id		path			string	true	"User ID"
+// @Param       json	body			utils.SetRole	true	"Set Role"
+// @Success     200 	{object}	utils.ResponseMessageModerated
+// @Failure     400		{object}	utils.ResponseMessage
+// @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
+// @Failure     404		{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/users/{id}/role	[put]
+func (handler *adminHandler) SetRole(ctx *gin.Context) {
+	var (
+		setRole utils.SetRole
+		err     error
+	)
+
+	if err = ctx.ShouldBindJSON(&setRole); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.adminUseCase.SetRole(ctx.Request.Context(), ctx.Param("userId"), setRole.Role); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the role has been changed",
+	})
+}
+
+// GetAuditLogs godoc
+// @Summary			Get the audit log
+// @Description	Get every recorded moderation and administration action, newest first, available to admins
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Success     200	{object}	utils.ResponseDataAuditLogs
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /admin/audit-logs	[get]
+func (handler *adminHandler) GetAuditLogs(ctx *gin.Context) {
+	var auditLogs []domain.AuditLog
+
+	if err := handler.adminUseCase.GetAuditLogs(ctx.Request.Context(), &auditLogs); err != nil {
+		abortWithError(ctx, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   auditLogs,
+	})
+}
+
+func abortWithError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidRole), errors.Is(err, domain.ErrDistanceOutOfRange):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrSelfModeration):
+		status = http.StatusForbidden
+	}
+
+	ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+		Status:  "fail",
+		Message: err.Error(),
+	})
+}
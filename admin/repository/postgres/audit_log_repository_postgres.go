@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *auditLogRepository {
+	return &auditLogRepository{db}
+}
+
+func (auditLogRepository *auditLogRepository) Create(ctx context.Context, auditLog *domain.AuditLog) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	auditLog.ID = fmt.Sprintf("auditlog-%s", ID)
+
+	if err = auditLogRepository.db.WithContext(ctx).Create(&auditLog).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (auditLogRepository *auditLogRepository) Get(ctx context.Context, auditLogs *[]domain.AuditLog) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = auditLogRepository.db.WithContext(ctx).Order("created_at DESC").Find(&auditLogs).Error; err != nil {
+		return err
+	}
+
+	return
+}
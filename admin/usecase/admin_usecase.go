@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"time"
+)
+
+var errNoPrincipal = errors.New("sign in to proceed")
+
+type adminUseCase struct {
+	userRepository            domain.UserRepository
+	imageUseCase              domain.ImageUseCase
+	commentRepository         domain.CommentRepository
+	auditLogRepository        domain.AuditLogRepository
+	tokenRevocationRepository domain.TokenRevocationRepository
+	refreshTokenRepository    domain.RefreshTokenRepository
+}
+
+func NewAdminUseCase(userRepository domain.UserRepository, imageUseCase domain.ImageUseCase, commentRepository domain.CommentRepository, auditLogRepository domain.AuditLogRepository, tokenRevocationRepository domain.TokenRevocationRepository, refreshTokenRepository domain.RefreshTokenRepository) *adminUseCase {
+	return &adminUseCase{userRepository, imageUseCase, commentRepository, auditLogRepository, tokenRevocationRepository, refreshTokenRepository}
+}
+
+func (adminUseCase *adminUseCase) DeleteImage(ctx context.Context, id string) (err error) {
+	image := domain.Image{}
+
+	actor, ok := domain.PrincipalFrom(ctx)
+
+	if !ok {
+		return errNoPrincipal
+	}
+
+	if err = adminUseCase.imageUseCase.GetByID(ctx, &image, id); err != nil {
+		return err
+	}
+
+	if err = adminUseCase.imageUseCase.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return adminUseCase.record(ctx, actor, domain.AuditActionDeleteImage, "image", id, fmt.Sprintf("owned by %s", image.UserID))
+}
+
+func (adminUseCase *adminUseCase) DeleteComment(ctx context.Context, id string) (err error) {
+	comment := domain.Comment{}
+
+	actor, ok := domain.PrincipalFrom(ctx)
+
+	if !ok {
+		return errNoPrincipal
+	}
+
+	if err = adminUseCase.commentRepository.GetByID(ctx, &comment, id); err != nil {
+		return err
+	}
+
+	if err = adminUseCase.commentRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return adminUseCase.record(ctx, actor, domain.AuditActionDeleteComment, "comment", id, fmt.Sprintf("owned by %s", comment.UserID))
+}
+
+func (adminUseCase *adminUseCase) GetDuplicateImages(ctx context.Context, duplicates *[]domain.ImageDuplicate, distance int) (err error) {
+	if err = adminUseCase.imageUseCase.GetNearDuplicates(ctx, duplicates, distance); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (adminUseCase *adminUseCase) GetUsers(ctx context.Context, users *[]domain.User) (err error) {
+	if err = adminUseCase.userRepository.Get(ctx, users); err != nil {
+		return err
+	}
+
+	return
+}
+
+// SuspendUser blocks the user from signing in and revokes every access and
+// refresh token the user currently holds.
+func (adminUseCase *adminUseCase) SuspendUser(ctx context.Context, id string) (err error) {
+	actor, err := adminUseCase.otherThanActor(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if err = adminUseCase.userRepository.SetSuspended(ctx, id, &now); err != nil {
+		return err
+	}
+
+	if err = adminUseCase.tokenRevocationRepository.RevokeAll(ctx, id, now); err != nil {
+		return err
+	}
+
+	if err = adminUseCase.refreshTokenRepository.RevokeAll(ctx, id); err != nil {
+		return err
+	}
+
+	return adminUseCase.record(ctx, actor, domain.AuditActionSuspendUser, "user", id, "")
+}
+
+func (adminUseCase *adminUseCase) RestoreUser(ctx context.Context, id string) (err error) {
+	actor, ok := domain.PrincipalFrom(ctx)
+
+	if !ok {
+		return errNoPrincipal
+	}
+
+	if err = adminUseCase.userRepository.SetSuspended(ctx, id, nil); err != nil {
+		return err
+	}
+
+	return adminUseCase.record(ctx, actor, domain.AuditActionRestoreUser, "user", id, "")
+}
+
+// SetRole changes the role of the user. Tokens issued before the change carry
+// the old role, so they are revoked and the user has to sign in again.
+func (adminUseCase *adminUseCase) SetRole(ctx context.Context, id string, role string) (err error) {
+	if role != domain.RoleUser && role != domain.RoleModerator && role != domain.RoleAdmin {
+		return domain.ErrInvalidRole
+	}
+
+	actor, err := adminUseCase.otherThanActor(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = adminUseCase.userRepository.SetRole(ctx, id, role); err != nil {
+		return err
+	}
+
+	if err = adminUseCase.tokenRevocationRepository.RevokeAll(ctx, id, time.Now()); err != nil {
+		return err
+	}
+
+	return adminUseCase.record(ctx, actor, domain.AuditActionSetRole, "user", id, fmt.Sprintf("role set to %s", role))
+}
+
+func (adminUseCase *adminUseCase) GetAuditLogs(ctx context.Context, auditLogs *[]domain.AuditLog) (err error) {
+	if err = adminUseCase.auditLogRepository.Get(ctx, auditLogs); err != nil {
+		return err
+	}
+
+	return
+}
+
+// otherThanActor returns the acting principal, refusing to let it act on its
+// own account.
+func (adminUseCase *adminUseCase) otherThanActor(ctx context.Context, id string) (domain.Principal, error) {
+	actor, ok := domain.PrincipalFrom(ctx)
+
+	if !ok {
+		return actor, errNoPrincipal
+	}
+
+	if actor.UserID == id {
+		return actor, domain.ErrSelfModeration
+	}
+
+	return actor, nil
+}
+
+func (adminUseCase *adminUseCase) record(ctx context.Context, actor domain.Principal, action string, targetType string, targetID string, detail string) error {
+	return adminUseCase.auditLogRepository.Create(ctx, &domain.AuditLog{
+		ActorID:    actor.UserID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Detail:     detail,
+	})
+}
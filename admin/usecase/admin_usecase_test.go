@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"testing"
+
+	adminUseCase "mygram-byferdiansyah/admin/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type adminMocks struct {
+	userRepository            *mocks.UserRepository
+	imageUseCase              *mocks.ImageUseCase
+	commentRepository         *mocks.CommentRepository
+	auditLogRepository        *mocks.AuditLogRepository
+	tokenRevocationRepository *mocks.TokenRevocationRepository
+	refreshTokenRepository    *mocks.RefreshTokenRepository
+}
+
+func newAdminUseCase() (domain.AdminUseCase, adminMocks) {
+	m := adminMocks{
+		new(mocks.UserRepository),
+		new(mocks.ImageUseCase),
+		new(mocks.CommentRepository),
+		new(mocks.AuditLogRepository),
+		new(mocks.TokenRevocationRepository),
+		new(mocks.RefreshTokenRepository),
+	}
+
+	return adminUseCase.NewAdminUseCase(m.userRepository, m.imageUseCase, m.commentRepository, m.auditLogRepository, m.tokenRevocationRepository, m.refreshTokenRepository), m
+}
+
+func asAdmin() context.Context {
+	return domain.WithPrincipal(context.Background(), domain.Principal{UserID: "user-admin", Roles: []string{domain.RoleAdmin}})
+}
+
+func TestDeleteImage(t *testing.T) {
+	t.Run("delete image of another user and record it", func(t *testing.T) {
+		adminUseCase, m := newAdminUseCase()
+
+		m.imageUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Image) = domain.Image{ID: "image-123", UserID: "user-123"}
+		}).Return(nil).Once()
+		m.imageUseCase.On("Delete", mock.Anything, "image-123").Return(nil).Once()
+		m.auditLogRepository.On("Create", mock.Anything, mock.MatchedBy(func(auditLog *domain.AuditLog) bool {
+			return auditLog.ActorID == "user-admin" && auditLog.Action == domain.AuditActionDeleteImage && auditLog.TargetID == "image-123"
+		})).Return(nil).Once()
+
+		err := adminUseCase.DeleteImage(asAdmin(), "image-123")
+
+		assert.NoError(t, err)
+		m.imageUseCase.AssertExpectations(t)
+		m.auditLogRepository.AssertExpectations(t)
+	})
+
+	t.Run("delete image that doesn't exist", func(t *testing.T) {
+		adminUseCase, m := newAdminUseCase()
+
+		m.imageUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-404").Return(gorm.ErrRecordNotFound).Once()
+
+		err := adminUseCase.DeleteImage(asAdmin(), "image-404")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		m.imageUseCase.AssertExpectations(t)
+		m.auditLogRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSuspendUser(t *testing.T) {
+	t.Run("suspend user and revoke every token", func(t *testing.T) {
+		adminUseCase, m := newAdminUseCase()
+
+		m.userRepository.On("SetSuspended", mock.Anything, "user-123", mock.AnythingOfType("*time.Time")).Return(nil).Once()
+		m.tokenRevocationRepository.On("RevokeAll", mock.Anything, "user-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		m.refreshTokenRepository.On("RevokeAll", mock.Anything, "user-123").Return(nil).Once()
+		m.auditLogRepository.On("Create", mock.Anything, mock.MatchedBy(func(auditLog *domain.AuditLog) bool {
+			return auditLog.Action == domain.AuditActionSuspendUser && auditLog.TargetID == "user-123"
+		})).Return(nil).Once()
+
+		err := adminUseCase.SuspendUser(asAdmin(), "user-123")
+
+		assert.NoError(t, err)
+		m.userRepository.AssertExpectations(t)
+		m.tokenRevocationRepository.AssertExpectations(t)
+		m.refreshTokenRepository.AssertExpectations(t)
+		m.auditLogRepository.AssertExpectations(t)
+	})
+
+	t.Run("suspend own account", func(t *testing.T) {
+		adminUseCase, m := newAdminUseCase()
+
+		err := adminUseCase.SuspendUser(asAdmin(), "user-admin")
+
+		assert.ErrorIs(t, err, domain.ErrSelfModeration)
+		m.userRepository.AssertNotCalled(t, "SetSuspended", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSetRole(t *testing.T) {
+	t.Run("set role and revoke access tokens", func(t *testing.T) {
+		adminUseCase, m := newAdminUseCase()
+
+		m.userRepository.On("SetRole", mock.Anything, "user-123", domain.RoleModerator).Return(nil).Once()
+		m.tokenRevocationRepository.On("RevokeAll", mock.Anything, "user-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		m.auditLogRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.AuditLog")).Return(nil).Once()
+
+		err := adminUseCase.SetRole(asAdmin(), "user-123", domain.RoleModerator)
+
+		assert.NoError(t, err)
+		m.userRepository.AssertExpectations(t)
+		m.tokenRevocationRepository.AssertExpectations(t)
+		m.auditLogRepository.AssertExpectations(t)
+	})
+
+	t.Run("set unknown role", func(t *testing.T) {
+		adminUseCase, m := newAdminUseCase()
+
+		err := adminUseCase.SetRole(asAdmin(), "user-123", "superuser")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRole)
+		m.userRepository.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetDuplicateImages(t *testing.T) {
+	t.Run("get duplicate images correctly", func(t *testing.T) {
+		adminUseCase, m := newAdminUseCase()
+
+		m.imageUseCase.On("GetNearDuplicates", mock.Anything, mock.AnythingOfType("*[]domain.ImageDuplicate"), 3).Return(nil).Once()
+
+		err := adminUseCase.GetDuplicateImages(asAdmin(), &[]domain.ImageDuplicate{}, 3)
+
+		assert.NoError(t, err)
+		m.imageUseCase.AssertExpectations(t)
+	})
+}
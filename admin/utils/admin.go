@@ -0,0 +1,59 @@
+package utils
+
+import "time"
+
+type AdminUser struct {
+	ID          string     `json:"id" example:"user-123"`
+	Username    string     `json:"username" example:"johndoe"`
+	Email       string     `json:"email" example:"johndoe@example.com"`
+	Role        string     `json:"role" example:"user"`
+	SuspendedAt *time.Time `json:"suspended_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+type ResponseDataAdminUsers struct {
+	Status string      `json:"status" example:"success"`
+	Data   []AdminUser `json:"data"`
+}
+
+type SetRole struct {
+	Role string `json:"role" binding:"required" example:"moderator"`
+}
+
+type AuditLog struct {
+	ID         string     `json:"id" example:"auditlog-123"`
+	ActorID    string     `json:"actor_id" example:"user-123"`
+	Action     string     `json:"action" example:"user.suspend"`
+	TargetType string     `json:"target_type" example:"user"`
+	TargetID   string     `json:"target_id" example:"user-456"`
+	Detail     string     `json:"detail,omitempty"`
+	CreatedAt  *time.Time `json:"created_at"`
+}
+
+type ResponseDataAuditLogs struct {
+	Status string     `json:"status" example:"success"`
+	Data   []AuditLog `json:"data"`
+}
+
+type ImageDuplicate struct {
+	ImageID         string `json:"image_id" example:"image-123"`
+	UserID          string `json:"user_id" example:"user-123"`
+	DuplicateID     string `json:"duplicate_id" example:"image-456"`
+	DuplicateUserID string `json:"duplicate_user_id" example:"user-456"`
+	Distance        int    `json:"distance" example:"1"`
+}
+
+type ResponseDataImageDuplicates struct {
+	Status string           `json:"status" example:"success"`
+	Data   []ImageDuplicate `json:"data"`
+}
+
+type ResponseMessageModerated struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"the action has been recorded"`
+}
+
+type ResponseMessage struct {
+	Status string `json:"status" example:"fail"`
+	Data   string `json:"data" example:"the error explained here"`
+}
@@ -0,0 +1,301 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/config"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+	"os"
+
+	adminDelivery "mygram-byferdiansyah/admin/delivery/http"
+	adminRepositories "mygram-byferdiansyah/admin/repository/postgres"
+	adminUseCases "mygram-byferdiansyah/admin/usecase"
+	commentDelivery "mygram-byferdiansyah/comment/delivery/http"
+	commentRepositories "mygram-byferdiansyah/comment/repository/postgres"
+	commentUseCases "mygram-byferdiansyah/comment/usecase"
+	readFeed "mygram-byferdiansyah/feed/read"
+	writeFeed "mygram-byferdiansyah/feed/write"
+	fileDelivery "mygram-byferdiansyah/file/delivery/http"
+	followDelivery "mygram-byferdiansyah/follow/delivery/http"
+	followRepositories "mygram-byferdiansyah/follow/repository/postgres"
+	followUseCases "mygram-byferdiansyah/follow/usecase"
+	imageDelivery "mygram-byferdiansyah/image/delivery/http"
+	imageRepositories "mygram-byferdiansyah/image/repository/postgres"
+	imageUseCases "mygram-byferdiansyah/image/usecase"
+	imageWorkers "mygram-byferdiansyah/image/worker"
+	likeDelivery "mygram-byferdiansyah/like/delivery/http"
+	likeRepositories "mygram-byferdiansyah/like/repository/postgres"
+	likeUseCases "mygram-byferdiansyah/like/usecase"
+	outboxMail "mygram-byferdiansyah/mail/outbox"
+	smtpMail "mygram-byferdiansyah/mail/smtp"
+	memoryRateLimit "mygram-byferdiansyah/ratelimit/memory"
+	postgresRateLimit "mygram-byferdiansyah/ratelimit/postgres"
+	socialMediaDelivery "mygram-byferdiansyah/socialmedia/delivery/http"
+	socialMediaRepositories "mygram-byferdiansyah/socialmedia/repository/postgres"
+	socialMediaUseCases "mygram-byferdiansyah/socialmedia/usecase"
+	localStorage "mygram-byferdiansyah/storage/local"
+	s3Storage "mygram-byferdiansyah/storage/s3"
+	userDelivery "mygram-byferdiansyah/user/delivery/http"
+	userMemoryRepositories "mygram-byferdiansyah/user/repository/memory"
+	userRepositories "mygram-byferdiansyah/user/repository/postgres"
+	userUseCases "mygram-byferdiansyah/user/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
+)
+
+// New builds every repository, usecase and handler on top of db and returns
+// the router with all routes registered. Tests can pass their own *gorm.DB and
+// drive the returned router in-process. The background workers run until ctx
+// is done, so cancelling it stops them along with the server.
+func New(ctx context.Context, config *config.Config, db *gorm.DB) (*gin.Engine, error) {
+	routers, err := newEngine(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	urlSigner := helpers.NewURLSigner(config.Storage.URLKey, config.Storage.URLTTL)
+
+	tokenManager, err := newTokenManager(config.Token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blobStore, err := newBlobStore(config.Storage)
+
+	if err != nil {
+		return nil, err
+	}
+
+	routers.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	userRepository := userRepositories.NewUserRepository(db)
+	refreshTokenRepository := userRepositories.NewRefreshTokenRepository(db)
+	passwordResetRepository := userRepositories.NewPasswordResetRepository(db)
+	emailVerificationRepository := userRepositories.NewEmailVerificationRepository(db)
+	twoFactorRepository := userRepositories.NewTwoFactorRepository(db)
+	tokenRevocationRepository := userRepositories.NewTokenRevocationRepository(db)
+	apiKeyRepository := userRepositories.NewAPIKeyRepository(db)
+	imageRepository := imageRepositories.NewImageRepository(db)
+	commentRepository := commentRepositories.NewCommentRepository(db)
+	socialMediaRepository := socialMediaRepositories.NewSocialMediaRepository(db)
+	auditLogRepository := adminRepositories.NewAuditLogRepository(db)
+	likeRepository := likeRepositories.NewLikeRepository(db)
+	followRepository := followRepositories.NewFollowRepository(db)
+	loginAttemptRepository := newLoginAttemptRepository(config.Login, db)
+	feedStore := newFeedStore(config.Feed, db)
+	rateLimiter := newRateLimiter(config.RateLimit, db)
+	mailer := newMailer(config.Mail)
+
+	// The worker generates the variants of uploaded images in the background.
+	variantWorker := imageWorkers.NewVariantWorker(imageRepository, blobStore, 100)
+
+	go variantWorker.Run(ctx)
+
+	userUseCase := userUseCases.NewUserUseCase(userRepository, blobStore, config.Storage.MaxUploadSize)
+	loginAttemptUseCase := userUseCases.NewLoginAttemptUseCase(userRepository, loginAttemptRepository, config.Login.MaxAttempts, config.Login.IPMaxAttempts, config.Login.Lockout)
+	refreshTokenUseCase := userUseCases.NewRefreshTokenUseCase(refreshTokenRepository, config.Token.RefreshTTL)
+	tokenRevocationUseCase := userUseCases.NewTokenRevocationUseCase(tokenRevocationRepository)
+	passwordUseCase := userUseCases.NewPasswordUseCase(userRepository, passwordResetRepository, refreshTokenRepository, tokenRevocationRepository, mailer, config.Token.ResetTTL, config.Mail.ResetURL)
+	emailVerificationUseCase := userUseCases.NewEmailVerificationUseCase(userRepository, emailVerificationRepository, mailer, config.Verification.TTL, config.Verification.URL, config.Verification.ResendLimit)
+	twoFactorUseCase := userUseCases.NewTwoFactorUseCase(userRepository, twoFactorRepository, loginAttemptRepository, config.Login.MaxAttempts, config.Login.Lockout)
+	apiKeyUseCase := userUseCases.NewAPIKeyUseCase(userRepository, apiKeyRepository)
+	imageUseCase := imageUseCases.NewImageUseCase(imageRepository, blobStore, variantWorker, feedStore, config.Storage.MaxUploadSize, config.Images.DuplicatePolicy)
+	commentUseCase := commentUseCases.NewCommentUseCase(commentRepository, config.Comments.MaxDepth)
+	socialMediaUseCase := socialMediaUseCases.NewSocialMediaUseCase(socialMediaRepository)
+	likeUseCase := likeUseCases.NewLikeUseCase(likeRepository)
+	followUseCase := followUseCases.NewFollowUseCase(followRepository, userRepository, feedStore)
+	adminUseCase := adminUseCases.NewAdminUseCase(userRepository, imageUseCase, commentRepository, auditLogRepository, tokenRevocationRepository, refreshTokenRepository)
+
+	userDelivery.NewUserHandler(routers, userUseCase, loginAttemptUseCase, refreshTokenUseCase, passwordUseCase, emailVerificationUseCase, twoFactorUseCase, followUseCase, urlSigner, config.Storage.MaxUploadSize, tokenManager, tokenRevocationUseCase, rateLimiter)
+	imageDelivery.NewImageHandler(routers, imageUseCase, likeUseCase, urlSigner, config.Storage.MaxUploadSize, tokenManager, tokenRevocationUseCase, apiKeyUseCase, rateLimiter, verifiedEmail(config.Verification, "images", userUseCase))
+	commentDelivery.NewCommentHandler(routers, commentUseCase, imageUseCase, likeUseCase, urlSigner, tokenManager, tokenRevocationUseCase, apiKeyUseCase, rateLimiter, verifiedEmail(config.Verification, "comments", userUseCase))
+	socialMediaDelivery.NewSocialMediaHandler(routers, socialMediaUseCase, tokenManager, tokenRevocationUseCase, rateLimiter)
+	likeDelivery.NewLikeHandler(routers, likeUseCase, tokenManager, tokenRevocationUseCase, rateLimiter)
+	followDelivery.NewFollowHandler(routers, followUseCase, userUseCase, urlSigner, tokenManager, tokenRevocationUseCase, rateLimiter)
+	adminDelivery.NewAdminHandler(routers, adminUseCase, tokenManager, tokenRevocationUseCase, rateLimiter)
+	fileDelivery.NewFileHandler(routers, blobStore, urlSigner, rateLimiter)
+	userDelivery.NewJWKSHandler(routers, tokenManager, rateLimiter)
+	userDelivery.NewAPIKeyHandler(routers, apiKeyUseCase, tokenManager, tokenRevocationUseCase, rateLimiter)
+
+	return routers, nil
+}
+
+// Run serves the router built by New on the configured port, with the
+// background workers running until ctx is done.
+func Run(ctx context.Context, config *config.Config, db *gorm.DB) error {
+	routers, err := New(ctx, config, db)
+
+	if err != nil {
+		return err
+	}
+
+	return routers.Run(":" + config.HTTP.Port)
+}
+
+// newEngine returns the engine every route is registered on, with the
+// middleware shared by all of them.
+func newEngine(config *config.Config) (*gin.Engine, error) {
+	if config.LogLevel == "debug" {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	routers := gin.Default()
+
+	// Without trusted proxies gin believes X-Forwarded-For from anyone, which
+	// would let clients pick the address they are rate limited by.
+	var trustedProxies []string
+
+	if len(config.HTTP.TrustedProxies) > 0 {
+		trustedProxies = config.HTTP.TrustedProxies
+	}
+
+	if err := routers.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
+	routers.Use(cors(config.CORS))
+
+	return routers, nil
+}
+
+// newTokenManager reads the configured signing keys, if any, into the token
+// manager.
+func newTokenManager(token config.Token) (*helpers.TokenManager, error) {
+	signingKeys := make([]helpers.SigningKey, 0, len(token.SigningKeys))
+
+	for _, configured := range token.SigningKeys {
+		data, err := os.ReadFile(configured.Path)
+
+		if err != nil {
+			return nil, fmt.Errorf("reading signing key %s: %w", configured.ID, err)
+		}
+
+		signingKey, err := helpers.ParseSigningKey(configured.ID, data, configured.ActiveFrom)
+
+		if err != nil {
+			return nil, err
+		}
+
+		signingKeys = append(signingKeys, signingKey)
+	}
+
+	return helpers.NewTokenManager(token.Key, token.AccessTTL, signingKeys...), nil
+}
+
+func newBlobStore(storage config.Storage) (domain.BlobStore, error) {
+	if storage.Driver == "s3" {
+		return s3Storage.NewBlobStore(storage.S3.Endpoint, storage.S3.Region, storage.S3.Bucket, storage.S3.AccessKey, storage.S3.SecretKey)
+	}
+
+	return localStorage.NewBlobStore(storage.LocalDir), nil
+}
+
+func newLoginAttemptRepository(login config.Login, db *gorm.DB) domain.LoginAttemptRepository {
+	if login.Store == "memory" {
+		return userMemoryRepositories.NewLoginAttemptRepository()
+	}
+
+	return userRepositories.NewLoginAttemptRepository(db)
+}
+
+// newRateLimiter builds the rate limiter with the configured policies on top
+// of the configured store.
+func newRateLimiter(rateLimit config.RateLimit, db *gorm.DB) *middleware.RateLimiter {
+	var store domain.RateLimitStore = memoryRateLimit.NewRateLimitStore()
+
+	if rateLimit.Store == "postgres" {
+		store = postgresRateLimit.NewRateLimitStore(db)
+	}
+
+	policies := map[string]domain.RateLimitPolicy{}
+
+	for name, policy := range rateLimit.Policies {
+		policies[name] = domain.RateLimitPolicy{Limit: policy.Limit, Period: policy.Period}
+	}
+
+	return middleware.NewRateLimiter(store, policies)
+}
+
+func newFeedStore(feed config.Feed, db *gorm.DB) domain.FeedStore {
+	if feed.Strategy == domain.FeedStrategyWrite {
+		return writeFeed.NewFeedStore(db)
+	}
+
+	return readFeed.NewFeedStore(db)
+}
+
+func newMailer(mail config.Mail) domain.Mailer {
+	if mail.Driver == "smtp" {
+		return smtpMail.NewMailer(mail.SMTP.Host, mail.SMTP.Port, mail.SMTP.Username, mail.SMTP.Password, mail.From)
+	}
+
+	return outboxMail.NewMailer()
+}
+
+// verifiedEmail restricts action to users with a verified email when the
+// verification policy lists it, and lets everyone through otherwise.
+func verifiedEmail(verification config.Verification, action string, users middleware.UserLoader) gin.HandlerFunc {
+	for _, restricted := range verification.Restrict {
+		if restricted == action {
+			return middleware.RequireVerifiedEmail(users)
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		ctx.Next()
+	}
+}
+
+// cors lets browsers call the API from the allowed origins. With "*" any
+// origin may call it, but without credentials, which the bearer tokens the
+// API takes don't need. Only origins listed explicitly are sent credentials.
+func cors(cors config.CORS) gin.HandlerFunc {
+	allowAll := false
+	allowed := map[string]bool{}
+
+	for _, origin := range cors.AllowOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+
+		allowed[origin] = true
+	}
+
+	return func(ctx *gin.Context) {
+		origin := ctx.Request.Header.Get("Origin")
+
+		ctx.Writer.Header().Set("Content-Type", "application/json")
+
+		if allowAll {
+			ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else if allowed[origin] {
+			ctx.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			ctx.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !allowAll {
+			ctx.Writer.Header().Add("Vary", "Origin")
+		}
+
+		ctx.Writer.Header().Set("Access-Control-Max-Age", "86400")
+		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, PUT, DELETE, UPDATE")
+		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Max")
+		ctx.Writer.Header().Set("Access-Control-Expose-Headers", "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After")
+
+		if ctx.Request.Method == http.MethodOptions {
+			ctx.AbortWithStatus(http.StatusOK)
+		} else {
+			ctx.Next()
+		}
+	}
+}
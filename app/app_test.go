@@ -0,0 +1,111 @@
+package app
+
+import (
+	"mygram-byferdiansyah/config"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ratelimit "mygram-byferdiansyah/ratelimit/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEngine(t *testing.T) {
+	newRouter := func(trustedProxies []string) *gin.Engine {
+		routers, err := newEngine(&config.Config{
+			HTTP:     config.HTTP{TrustedProxies: trustedProxies},
+			CORS:     config.CORS{AllowOrigins: []string{"*"}},
+			LogLevel: "silent",
+		})
+
+		assert.NoError(t, err)
+
+		rateLimiter := middleware.NewRateLimiter(ratelimit.NewRateLimitStore(), map[string]domain.RateLimitPolicy{
+			domain.RateLimitDefault: {Limit: 1, Period: time.Hour},
+		})
+
+		routers.GET("/ping", rateLimiter.Limit(domain.RateLimitDefault), func(ctx *gin.Context) {
+			ctx.String(http.StatusOK, ctx.ClientIP())
+		})
+
+		return routers
+	}
+
+	request := func(routers *gin.Engine, forwardedFor string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+
+		routers.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	t.Run("forged X-Forwarded-For doesn't change the bucket without trusted proxies", func(t *testing.T) {
+		routers := newRouter(nil)
+
+		recorder := request(routers, "198.51.100.1")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "192.0.2.1", recorder.Body.String())
+		assert.Equal(t, http.StatusTooManyRequests, request(routers, "198.51.100.2").Code)
+	})
+
+	t.Run("X-Forwarded-For is believed from a trusted proxy", func(t *testing.T) {
+		routers := newRouter([]string{"192.0.2.0/24"})
+
+		recorder := request(routers, "198.51.100.1")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "198.51.100.1", recorder.Body.String())
+		assert.Equal(t, http.StatusOK, request(routers, "198.51.100.2").Code)
+	})
+}
+
+func TestCORS(t *testing.T) {
+	request := func(allowOrigins []string, origin string) http.Header {
+		routers := gin.New()
+		routers.Use(cors(config.CORS{AllowOrigins: allowOrigins}))
+		routers.GET("/ping", func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+
+		req.Header.Set("Origin", origin)
+
+		routers.ServeHTTP(recorder, req)
+
+		return recorder.Header()
+	}
+
+	t.Run("wildcard allows any origin without credentials", func(t *testing.T) {
+		header := request([]string{"*"}, "https://evil.example.com")
+
+		assert.Equal(t, "*", header.Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, header.Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("listed origin is allowed with credentials", func(t *testing.T) {
+		header := request([]string{"https://mygram.example.com"}, "https://mygram.example.com")
+
+		assert.Equal(t, "https://mygram.example.com", header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", header.Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "Origin", header.Get("Vary"))
+	})
+
+	t.Run("unlisted origin isn't allowed", func(t *testing.T) {
+		header := request([]string{"https://mygram.example.com"}, "https://evil.example.com")
+
+		assert.Empty(t, header.Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, header.Get("Access-Control-Allow-Credentials"))
+	})
+}
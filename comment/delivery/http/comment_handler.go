@@ -1,44 +1,61 @@
 package delivery
 
 import (
+	"errors"
 	"fmt"
-	"mygram-byferdiansyah/comment/delivery/http/middleware"
 	"mygram-byferdiansyah/comment/utils"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"mygram-byferdiansyah/pagination"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type commentHandler struct {
 	commentUseCase domain.CommentUseCase
 	imageUseCase   domain.ImageUseCase
+	likeUseCase    domain.LikeUseCase
+	urlSigner      *helpers.URLSigner
 }
 
-func NewCommentHandler(routers *gin.Engine, commentUseCase domain.CommentUseCase, imageUseCase domain.ImageUseCase) {
-	handler := &commentHandler{commentUseCase, imageUseCase}
+func NewCommentHandler(routers *gin.Engine, commentUseCase domain.CommentUseCase, imageUseCase domain.ImageUseCase, likeUseCase domain.LikeUseCase, urlSigner *helpers.URLSigner, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, apiKeyUseCase domain.APIKeyUseCase, rateLimiter *middleware.RateLimiter, verifiedEmail gin.HandlerFunc) {
+	handler := &commentHandler{commentUseCase, imageUseCase, likeUseCase, urlSigner}
 
 	router := routers.Group("/comments")
 	{
-		router.Use(middleware.Authentication())
-		router.GET("", handler.Get)
-		router.POST("", handler.Create)
-		router.PUT("/:commentId", middleware.Authorization(handler.commentUseCase), handler.Edit)
-		router.DELETE("/:commentId", middleware.Authorization(handler.commentUseCase), handler.Delete)
+		router.Use(rateLimiter.LimitAddress(domain.RateLimitDefault))
+		router.Use(middleware.AuthenticationWithAPIKeys(tokenManager, tokenRevocationUseCase, apiKeyUseCase))
+		router.Use(rateLimiter.Limit(domain.RateLimitDefault))
+		router.GET("", middleware.RequireScope(domain.ScopeCommentsRead), handler.Get)
+		router.POST("", middleware.RequireScope(domain.ScopeCommentsWrite), verifiedEmail, rateLimiter.Limit(domain.RateLimitCreateComment), handler.Create)
+		router.GET("/:commentId", middleware.RequireScope(domain.ScopeCommentsRead), handler.GetByID)
+		router.GET("/:commentId/thread", middleware.RequireScope(domain.ScopeCommentsRead), handler.GetThread)
+		router.PUT("/:commentId", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.RequireOwner[domain.Comment](handler.commentUseCase, "comment", "commentId"), handler.Edit)
+		router.DELETE("/:commentId", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.RequireOwner[domain.Comment](handler.commentUseCase, "comment", "commentId"), handler.Delete)
 	}
+
+	routers.GET("/images/:imageId/comments", rateLimiter.LimitAddress(domain.RateLimitDefault), middleware.AuthenticationWithAPIKeys(tokenManager, tokenRevocationUseCase, apiKeyUseCase), rateLimiter.Limit(domain.RateLimitDefault), middleware.RequireScope(domain.ScopeCommentsRead), handler.GetByImage)
 }
 
 // Get godoc
 // @Summary			Get all comments
-// @Description	Get all comments with authentication user
+// @Description	Get all comments with authentication user. Comments on the images of a private account are only shown to the account and its followers
 // @Tags        comments
 // @Accept      json
 // @Produce     json
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       sort						query			string	false	"Order by creation time"	Enums(asc, desc)	default(desc)
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
+// @Param       image_id				query			string	false	"Only comments on this image"
 // @Success     200	{object}	utils.ResponseDataGetedComment
 // @Failure     400	{object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /comments     [get]
 func (handler *commentHandler) Get(ctx *gin.Context) {
@@ -48,10 +65,20 @@ func (handler *commentHandler) Get(ctx *gin.Context) {
 		err error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	page, err := pagination.Parse(ctx, "image_id")
 
-	if err = handler.commentUseCase.Get(ctx.Request.Context(), &comments, userID); err != nil {
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = handler.commentUseCase.Get(ctx.Request.Context(), &comments, principal.UserID, page); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail please try again",
 			Message: err.Error(),
@@ -60,9 +87,244 @@ func (handler *commentHandler) Get(ctx *gin.Context) {
 		return
 	}
 
+	if err = handler.markLiked(ctx, comments); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
 	ctx.JSON(http.StatusOK, helpers.ResponseData{
-		Status: "congratulation its success",
-		Data:   comments,
+		Status:     "congratulation its success",
+		Data:       comments,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+// GetByID godoc
+// @Summary			Get a comment
+// @Description	Get a comment by id with authentication user. Comments on the images of a private account are only shown to the account and its followers
+// @Tags        comments
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path			string	true	"Comment ID"
+// @Success     200				{object}	utils.ResponseDataGetedCommentByID
+// @Failure     401				{object}	utils.ResponseMessage
+// @Failure     403				{object}	utils.ResponseMessage
+// @Failure     404				{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /comments/{commentId}	[get]
+func (handler *commentHandler) GetByID(ctx *gin.Context) {
+	var (
+		comment domain.Comment
+		err     error
+	)
+
+	commentID := ctx.Param("commentId")
+
+	if err = handler.commentUseCase.GetByID(ctx.Request.Context(), &comment, commentID); err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("comment with id %s doesn't exist", commentID)
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	geted := utils.GetedComment{
+		ID:         comment.ID,
+		UserID:     comment.UserID,
+		ImageID:    comment.ImageID,
+		Message:    comment.Message,
+		ParentID:   comment.ParentID,
+		Depth:      comment.Depth,
+		ReplyCount: comment.ReplyCount,
+		LikeCount:  comment.LikeCount,
+		DeletedAt:  comment.DeletedAt,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+
+	if comment.DeletedAt == nil {
+		geted.User = handler.publicUser(comment.User)
+	}
+
+	if comment.Image != nil {
+		geted.Image = &utils.Image{
+			ID:       comment.Image.ID,
+			Title:    comment.Image.Title,
+			Caption:  comment.Image.Caption,
+			ImageUrl: comment.Image.ImageUrl,
+			UserID:   comment.Image.UserID,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   geted,
+	})
+}
+
+// GetByImage godoc
+// @Summary			Get the comments on an image
+// @Description	Get the top-level comments on an image, each with the public profile of its author and its number of replies
+// @Tags        comments
+// @Accept      json
+// @Produce     json
+// @Param       imageId					path			string	true	"Image ID"
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       sort						query			string	false	"Order by creation time"	Enums(asc, desc)	default(desc)
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
+// @Success     200	{object}	utils.ResponseDataImageComments
+// @Failure     400	{object}	utils.ResponseMessage
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /images/{imageId}/comments	[get]
+func (handler *commentHandler) GetByImage(ctx *gin.Context) {
+	var (
+		comments []domain.Comment
+		image    domain.Image
+
+		err error
+	)
+
+	page, err := pagination.Parse(ctx)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	imageID := ctx.Param("imageId")
+
+	if err = handler.imageUseCase.GetByID(ctx.Request.Context(), &image, imageID); err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("image with id %s doesn't exist", imageID)
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.commentUseCase.GetByImage(ctx.Request.Context(), &comments, imageID, page); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.markLiked(ctx, comments); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	imageComments := []utils.ImageComment{}
+
+	for _, comment := range comments {
+		imageComments = append(imageComments, handler.imageComment(comment))
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status:     "success",
+		Data:       imageComments,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+// GetThread godoc
+// @Summary			Get a comment thread
+// @Description	Get a comment with all of its replies nested below it
+// @Tags        comments
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path			string	true	"Comment ID"
+// @Success     200				{object}	utils.ResponseDataCommentThread
+// @Failure     401				{object}	utils.ResponseMessage
+// @Failure     403				{object}	utils.ResponseMessage
+// @Failure     404				{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /comments/{commentId}/thread	[get]
+func (handler *commentHandler) GetThread(ctx *gin.Context) {
+	var (
+		comments []domain.Comment
+		err      error
+	)
+
+	commentID := ctx.Param("commentId")
+
+	if err = handler.commentUseCase.GetThread(ctx.Request.Context(), &comments, commentID); err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("comment with id %s doesn't exist", commentID)
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.markLiked(ctx, comments); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	threads := make(map[string]*utils.CommentThread, len(comments))
+
+	for _, comment := range comments {
+		thread := &utils.CommentThread{ImageComment: handler.imageComment(comment), Replies: []*utils.CommentThread{}}
+		threads[comment.ID] = thread
+
+		if comment.ParentID != nil && comment.ID != commentID {
+			if parent, ok := threads[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, thread)
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   threads[commentID],
 	})
 }
 
@@ -76,6 +338,10 @@ func (handler *commentHandler) Get(ctx *gin.Context) {
 // @Success     201		{object}  utils.ResponseDataAddedComment
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
+// @Failure     404		{object}	utils.ResponseMessage
+// @Failure     409		{object}	utils.ResponseMessage
+// @Failure     429		{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /comments	[post]
 func (handler *commentHandler) Create(ctx *gin.Context) {
@@ -85,8 +351,7 @@ func (handler *commentHandler) Create(ctx *gin.Context) {
 		err     error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	principal, _ := middleware.GetPrincipal(ctx)
 
 	if err = ctx.ShouldBindJSON(&comment); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -99,19 +364,33 @@ func (handler *commentHandler) Create(ctx *gin.Context) {
 
 	imageID := comment.ImageID
 
-	if err = handler.imageUseCase.GetByID(ctx.Request.Context(), &image, imageID); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
-			Status:  "fail no image found",
-			Message: fmt.Sprintf("image with id %s doesn't exist", imageID),
-		})
-
-		return
+	// A reply takes the image of the comment it replies to, which the
+	// usecase checks.
+	if comment.ParentID == nil || *comment.ParentID == "" {
+		if err = handler.imageUseCase.GetByID(ctx.Request.Context(), &image, imageID); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail no image found",
+				Message: fmt.Sprintf("image with id %s doesn't exist", imageID),
+			})
+
+			return
+		}
 	}
 
-	comment.UserID = userID
+	comment.UserID = principal.UserID
 
 	if err = handler.commentUseCase.Create(ctx.Request.Context(), &comment); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+		status := http.StatusBadRequest
+
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusNotFound
+			err = fmt.Errorf("comment with id %s doesn't exist", *comment.ParentID)
+		case errors.Is(err, domain.ErrCommentDeleted):
+			status = http.StatusConflict
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
 			Status:  "fail doesnt found the comment",
 			Message: err.Error(),
 		})
@@ -126,6 +405,8 @@ func (handler *commentHandler) Create(ctx *gin.Context) {
 			UserID:    comment.UserID,
 			ImageID:   comment.ImageID,
 			Message:   comment.Message,
+			ParentID:  comment.ParentID,
+			Depth:     comment.Depth,
 			CreatedAt: comment.CreatedAt,
 		},
 	})
@@ -142,7 +423,9 @@ func (handler *commentHandler) Create(ctx *gin.Context) {
 // @Success     200		{object}  utils.ResponseDataEditedComment
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
 // @Failure     404		{object}	utils.ResponseMessage
+// @Failure     409		{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /comments/{id}	[put]
 func (handler *commentHandler) Edit(ctx *gin.Context) {
@@ -153,8 +436,7 @@ func (handler *commentHandler) Edit(ctx *gin.Context) {
 	)
 
 	commentID := ctx.Param("commentId")
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	principal, _ := middleware.GetPrincipal(ctx)
 
 	if err = ctx.ShouldBindJSON(&comment); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -165,8 +447,20 @@ func (handler *commentHandler) Edit(ctx *gin.Context) {
 		return
 	}
 
+	ownedComment, _ := middleware.Resource[domain.Comment](ctx)
+
+	if ownedComment.DeletedAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+			Status:  "fail to edit your data",
+			Message: domain.ErrCommentDeleted.Error(),
+		})
+
+		return
+	}
+
 	editedComment := domain.Comment{
-		UserID:  userID,
+		UserID:  principal.UserID,
+		ImageID: ownedComment.ImageID,
 		Message: comment.Message,
 	}
 
@@ -194,7 +488,7 @@ func (handler *commentHandler) Edit(ctx *gin.Context) {
 
 // Delete godoc
 // @Summary			Delete a comment
-// @Description	Delete a comment by id with authentication user
+// @Description	Delete a comment by id with authentication user. A comment that has replies is replaced by "[deleted]" so the replies stay
 // @Tags        comments
 // @Accept      json
 // @Produce     json
@@ -202,6 +496,7 @@ func (handler *commentHandler) Edit(ctx *gin.Context) {
 // @Success     200 {object}	utils.ResponseMessageDeletedComment
 // @Failure     400 {object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
 // @Failure     404	{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /comments/{id}	[delete]
@@ -221,3 +516,71 @@ func (handler *commentHandler) Delete(ctx *gin.Context) {
 		"message": "your comment has been successfully deleted",
 	})
 }
+
+// markLiked sets LikedByMe on the comments the caller has liked.
+func (handler *commentHandler) markLiked(ctx *gin.Context, comments []domain.Comment) error {
+	principal, _ := middleware.GetPrincipal(ctx)
+	ids := make([]string, 0, len(comments))
+
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	liked, err := handler.likeUseCase.GetLiked(ctx.Request.Context(), principal.UserID, domain.LikeTargetComment, ids)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].LikedByMe = liked[comments[i].ID]
+	}
+
+	return nil
+}
+
+// publicUser keeps the parts of a comment author's profile anyone may see,
+// with an uploaded avatar behind a signed link.
+func (handler *commentHandler) publicUser(user *domain.User) *utils.User {
+	if user == nil {
+		return nil
+	}
+
+	profileImageUrl := user.ProfileImageUrl
+
+	if _, ok := user.AvatarKey(); ok {
+		profileImageUrl = handler.urlSigner.Sign(profileImageUrl)
+	} else if _, ok := domain.FileKey(profileImageUrl); ok {
+		profileImageUrl = ""
+	}
+
+	return &utils.User{
+		ID:              user.ID,
+		Username:        user.Username,
+		ProfileImageUrl: profileImageUrl,
+	}
+}
+
+// imageComment maps a comment to the response shape, hiding the author of a
+// deleted comment.
+func (handler *commentHandler) imageComment(comment domain.Comment) utils.ImageComment {
+	mapped := utils.ImageComment{
+		ID:         comment.ID,
+		Message:    comment.Message,
+		ParentID:   comment.ParentID,
+		Depth:      comment.Depth,
+		ReplyCount: comment.ReplyCount,
+		LikeCount:  comment.LikeCount,
+		LikedByMe:  comment.LikedByMe,
+		DeletedAt:  comment.DeletedAt,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+
+	if comment.DeletedAt == nil {
+		mapped.UserID = comment.UserID
+		mapped.User = handler.publicUser(comment.User)
+	}
+
+	return mapped
+}
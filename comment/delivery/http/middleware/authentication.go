@@ -1,26 +0,0 @@
-package middleware
-
-import (
-	"mygram-byferdiansyah/helpers"
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-)
-
-func Authentication() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		verifyToken, err := helpers.VerifyToken(ctx)
-
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
-				Status:  "unauthenticated please use another account",
-				Message: err.Error(),
-			})
-
-			return
-		}
-
-		ctx.Set("userData", verifyToken)
-		ctx.Next()
-	}
-}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+	"mygram-byferdiansyah/privacy"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+const maxThreadSize = 500
+
 type commentRepository struct {
 	db *gorm.DB
 }
@@ -18,12 +23,12 @@ func NewCommentRepository(db *gorm.DB) *commentRepository {
 	return &commentRepository{db}
 }
 
-func (commentRepository *commentRepository) Get(ctx context.Context, comments *[]domain.Comment, userID string) (err error) {
+func (commentRepository *commentRepository) Get(ctx context.Context, comments *[]domain.Comment, userID string, page *pagination.Page) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = commentRepository.db.WithContext(ctx).Where("user_id = ?", userID).Preload("User", func(db *gorm.DB) *gorm.DB {
+	if err = page.Query(commentRepository.db.WithContext(ctx).Where("user_id = ?", userID).Scopes(privacy.VisibleImages(ctx, "image_id"))).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "email", "username", "profile_image_url")
 	}).Preload("Image", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "user_id", "title", "image_url", "caption")
@@ -31,6 +36,55 @@ func (commentRepository *commentRepository) Get(ctx context.Context, comments *[
 		return err
 	}
 
+	pagination.Finish(page, comments, func(comment domain.Comment) (*time.Time, string) {
+		return comment.CreatedAt, comment.ID
+	})
+
+	return
+}
+
+func (commentRepository *commentRepository) GetByImage(ctx context.Context, comments *[]domain.Comment, imageID string, page *pagination.Page) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = page.Query(commentRepository.db.WithContext(ctx).Where("image_id = ? AND parent_id IS NULL", imageID).Scopes(privacy.VisibleImages(ctx, "image_id"))).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "profile_image_url")
+	}).Find(&comments).Error; err != nil {
+		return err
+	}
+
+	pagination.Finish(page, comments, func(comment domain.Comment) (*time.Time, string) {
+		return comment.CreatedAt, comment.ID
+	})
+
+	return
+}
+
+// GetThread loads a comment and every reply below it, parents before their
+// replies. Threads larger than maxThreadSize are cut off at the deepest
+// level.
+func (commentRepository *commentRepository) GetThread(ctx context.Context, comments *[]domain.Comment, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	thread := commentRepository.db.Raw(`WITH RECURSIVE thread AS (
+		SELECT id FROM comments WHERE id = ?
+		UNION ALL
+		SELECT comments.id FROM comments JOIN thread ON comments.parent_id = thread.id
+	) SELECT id FROM thread`, id)
+
+	if err = commentRepository.db.WithContext(ctx).Where("id IN (?)", thread).Scopes(privacy.VisibleImages(ctx, "image_id")).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "profile_image_url")
+	}).Order("depth, created_at, id").Limit(maxThreadSize).Find(&comments).Error; err != nil {
+		return err
+	}
+
+	if len(*comments) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
 	return
 }
 
@@ -43,7 +97,17 @@ func (commentRepository *commentRepository) Create(ctx context.Context, comment
 
 	comment.ID = fmt.Sprintf("your comment-%s", ID)
 
-	if err = commentRepository.db.WithContext(ctx).Create(&comment).Error; err != nil {
+	if err = commentRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+
+		if comment.ParentID == nil {
+			return nil
+		}
+
+		return tx.Model(&domain.Comment{}).Where("id = ?", *comment.ParentID).UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+	}); err != nil {
 		return err
 	}
 
@@ -55,7 +119,11 @@ func (commentRepository *commentRepository) GetByID(ctx context.Context, comment
 
 	defer cancel()
 
-	if err = commentRepository.db.WithContext(ctx).First(&comment, &id).Error; err != nil {
+	if err = commentRepository.db.WithContext(ctx).Scopes(privacy.VisibleImages(ctx, "image_id")).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "profile_image_url")
+	}).Preload("Image", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "user_id", "title", "image_url", "caption")
+	}).First(&comment, &id).Error; err != nil {
 		return err
 	}
 
@@ -86,18 +154,52 @@ func (commentRepository *commentRepository) Edit(ctx context.Context, comment do
 	return image, nil
 }
 
+// Delete removes a comment without replies, along with any deleted ancestors
+// it was the last reply to. A comment that still has replies is kept as a
+// tombstone so the thread below it stays intact.
 func (commentRepository *commentRepository) Delete(ctx context.Context, id string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = commentRepository.db.WithContext(ctx).First(&domain.Comment{}, &id).Error; err != nil {
-		return err
-	}
+	return commentRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		comment := domain.Comment{}
 
-	if err = commentRepository.db.WithContext(ctx).Delete(&domain.Comment{}, &id).Error; err != nil {
-		return err
-	}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, &id).Error; err != nil {
+			return err
+		}
 
-	return
+		if comment.ReplyCount > 0 {
+			return tx.Model(&comment).Updates(map[string]interface{}{
+				"message":    domain.DeletedCommentMessage,
+				"deleted_at": time.Now(),
+			}).Error
+		}
+
+		for {
+			if err := tx.Delete(&domain.Comment{}, "id = ?", comment.ID).Error; err != nil {
+				return err
+			}
+
+			if comment.ParentID == nil {
+				return nil
+			}
+
+			parent := domain.Comment{}
+
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, "id = ?", *comment.ParentID).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&parent).UpdateColumn("reply_count", gorm.Expr("reply_count - 1")).Error; err != nil {
+				return err
+			}
+
+			if parent.DeletedAt == nil || parent.ReplyCount > 1 {
+				return nil
+			}
+
+			comment = parent
+		}
+	})
 }
@@ -3,25 +3,77 @@ package usecase
 import (
 	"context"
 	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
 )
 
 type commentUseCase struct {
 	commentRepository domain.CommentRepository
+	maxDepth          int
 }
 
-func NewCommentUseCase(commentRepository domain.CommentRepository) *commentUseCase {
-	return &commentUseCase{commentRepository}
+func NewCommentUseCase(commentRepository domain.CommentRepository, maxDepth int) *commentUseCase {
+	return &commentUseCase{commentRepository, maxDepth}
 }
 
-func (commentUseCase *commentUseCase) Get(ctx context.Context, comments *[]domain.Comment, userID string) (err error) {
-	if err = commentUseCase.commentRepository.Get(ctx, comments, userID); err != nil {
+func (commentUseCase *commentUseCase) Get(ctx context.Context, comments *[]domain.Comment, userID string, page *pagination.Page) (err error) {
+	if err = commentUseCase.commentRepository.Get(ctx, comments, userID, page); err != nil {
 		return err
 	}
 
 	return
 }
 
+func (commentUseCase *commentUseCase) GetByImage(ctx context.Context, comments *[]domain.Comment, imageID string, page *pagination.Page) (err error) {
+	if err = commentUseCase.commentRepository.GetByImage(ctx, comments, imageID, page); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (commentUseCase *commentUseCase) GetThread(ctx context.Context, comments *[]domain.Comment, id string) (err error) {
+	if err = commentUseCase.commentRepository.GetThread(ctx, comments, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Create adds a comment, or a reply when ParentID is set. A reply lands on
+// the image of its parent, one level deeper.
 func (commentUseCase *commentUseCase) Create(ctx context.Context, comment *domain.Comment) (err error) {
+	comment.Depth, comment.ReplyCount, comment.LikeCount, comment.DeletedAt = 0, 0, 0, nil
+
+	if comment.ParentID != nil && *comment.ParentID == "" {
+		comment.ParentID = nil
+	}
+
+	if comment.ParentID != nil {
+		parent := domain.Comment{}
+
+		if err = commentUseCase.commentRepository.GetByID(ctx, &parent, *comment.ParentID); err != nil {
+			return err
+		}
+
+		if parent.DeletedAt != nil {
+			return domain.ErrCommentDeleted
+		}
+
+		if comment.ImageID == "" {
+			comment.ImageID = parent.ImageID
+		}
+
+		if comment.ImageID != parent.ImageID {
+			return domain.ErrReplyOnOtherImage
+		}
+
+		if parent.Depth >= commentUseCase.maxDepth {
+			return domain.ErrCommentTooDeep
+		}
+
+		comment.Depth = parent.Depth + 1
+	}
+
 	if err = commentUseCase.commentRepository.Create(ctx, comment); err != nil {
 		return err
 	}
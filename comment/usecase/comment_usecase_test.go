@@ -5,6 +5,7 @@ import (
 	"errors"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/pagination"
 	"testing"
 	"time"
 
@@ -31,17 +32,52 @@ func TestGet(t *testing.T) {
 	mockComments = append(mockComments, mockComment)
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
 
 	t.Run("get all comments correctly", func(t *testing.T) {
-		mockCommentRepository.On("Get", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockCommentRepository.On("Get", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string"), mock.AnythingOfType("*pagination.Page")).Return(nil).Once()
 
-		err := commentUseCase.Get(context.Background(), &mockComments, mockComment.UserID)
+		err := commentUseCase.Get(context.Background(), &mockComments, mockComment.UserID, &pagination.Page{Limit: pagination.DefaultLimit})
 
 		assert.NoError(t, err)
 	})
 }
 
+func TestGetByImage(t *testing.T) {
+	now := time.Now()
+	mockComments := []domain.Comment{{
+		ID:        "comment-123",
+		UserID:    "user-123",
+		ImageID:   "image-123",
+		Message:   "A message",
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}}
+
+	mockCommentRepository := new(mocks.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
+
+	t.Run("get the comments on an image correctly", func(t *testing.T) {
+		mockCommentRepository.On("GetByImage", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "image-123", mock.AnythingOfType("*pagination.Page")).Return(nil).Once()
+
+		err := commentUseCase.GetByImage(context.Background(), &mockComments, "image-123", &pagination.Page{Limit: pagination.DefaultLimit})
+
+		assert.NoError(t, err)
+
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("get the comments on an image with error", func(t *testing.T) {
+		mockCommentRepository.On("GetByImage", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "image-123", mock.AnythingOfType("*pagination.Page")).Return(errors.New("fail")).Once()
+
+		err := commentUseCase.GetByImage(context.Background(), &mockComments, "image-123", &pagination.Page{Limit: pagination.DefaultLimit})
+
+		assert.Error(t, err)
+
+		mockCommentRepository.AssertExpectations(t)
+	})
+}
+
 func TestCreate(t *testing.T) {
 	now := time.Now()
 	mockAddedComment := domain.Comment{
@@ -53,12 +89,12 @@ func TestCreate(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
 
 	t.Run("add comment correctly", func(t *testing.T) {
 		tempMockAddComment := domain.Comment{
-			Message: "test comment",
-			ImageID: "testimg-123",
+			Message: "A comment",
+			ImageID: "image-123",
 		}
 
 		tempMockAddComment.ID = "comment-123"
@@ -147,6 +183,88 @@ func TestCreate(t *testing.T) {
 	})
 }
 
+func TestCreateReply(t *testing.T) {
+	now := time.Now()
+	parentID := "comment-123"
+
+	mockCommentRepository := new(mocks.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
+
+	parentReturns := func(parent domain.Comment) {
+		mockCommentRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Comment) = parent
+		}).Return(nil).Once()
+	}
+
+	t.Run("add reply correctly", func(t *testing.T) {
+		parentReturns(domain.Comment{ID: parentID, ImageID: "image-123", Depth: 1})
+
+		mockCommentRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
+
+		reply := domain.Comment{Message: "A reply", ParentID: &parentID, Depth: 7, ReplyCount: 3}
+
+		err := commentUseCase.Create(context.Background(), &reply)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image-123", reply.ImageID)
+		assert.Equal(t, 2, reply.Depth)
+		assert.Equal(t, 0, reply.ReplyCount)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("add reply deeper than the maximum depth", func(t *testing.T) {
+		parentReturns(domain.Comment{ID: parentID, ImageID: "image-123", Depth: 2})
+
+		err := commentUseCase.Create(context.Background(), &domain.Comment{Message: "A reply", ParentID: &parentID})
+
+		assert.ErrorIs(t, err, domain.ErrCommentTooDeep)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("add reply to a deleted comment", func(t *testing.T) {
+		parentReturns(domain.Comment{ID: parentID, ImageID: "image-123", DeletedAt: &now})
+
+		err := commentUseCase.Create(context.Background(), &domain.Comment{Message: "A reply", ParentID: &parentID})
+
+		assert.ErrorIs(t, err, domain.ErrCommentDeleted)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("add reply on another image", func(t *testing.T) {
+		parentReturns(domain.Comment{ID: parentID, ImageID: "image-123"})
+
+		err := commentUseCase.Create(context.Background(), &domain.Comment{Message: "A reply", ImageID: "image-234", ParentID: &parentID})
+
+		assert.ErrorIs(t, err, domain.ErrReplyOnOtherImage)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("add reply to a comment that doesn't exist", func(t *testing.T) {
+		mockCommentRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Return(errors.New("record not found")).Once()
+
+		err := commentUseCase.Create(context.Background(), &domain.Comment{Message: "A reply", ParentID: &parentID})
+
+		assert.Error(t, err)
+		mockCommentRepository.AssertExpectations(t)
+	})
+}
+
+func TestGetThread(t *testing.T) {
+	mockComments := []domain.Comment{}
+
+	mockCommentRepository := new(mocks.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
+
+	t.Run("get a thread correctly", func(t *testing.T) {
+		mockCommentRepository.On("GetThread", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "comment-123").Return(nil).Once()
+
+		err := commentUseCase.GetThread(context.Background(), &mockComments, "comment-123")
+
+		assert.NoError(t, err)
+		mockCommentRepository.AssertExpectations(t)
+	})
+}
+
 func TestGetBy(t *testing.T) {
 	var mockComment *domain.Comment
 
@@ -162,7 +280,7 @@ func TestGetBy(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
 
 	t.Run("get by id correctly", func(t *testing.T) {
 		mockCommentID := "comment-123"
@@ -201,7 +319,7 @@ func TestEdit(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
 
 	t.Run("edit comment correctly", func(t *testing.T) {
 		tempMockCommentID := "comment-123"
@@ -316,7 +434,7 @@ func TestDelete(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, 2)
 
 	t.Run("delete comment correctly", func(t *testing.T) {
 		mockCommentRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
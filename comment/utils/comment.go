@@ -3,9 +3,10 @@ package utils
 import "time"
 
 type User struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	ID              string `json:"id"`
+	Username        string `json:"username"`
+	Email           string `json:"email,omitempty"`
+	ProfileImageUrl string `json:"profile_image_url,omitempty"`
 }
 
 type Image struct {
@@ -17,24 +18,69 @@ type Image struct {
 }
 
 type GetedComment struct {
-	ID        string     `json:"id"`
-	UserID    string     `json:"user_id"`
-	ImageID   string     `json:"image_id"`
-	Message   string     `json:"message"`
-	CreatedAt *time.Time `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
-	User      *User      `json:"user"`
-	Image     *Image     `json:"image"`
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	ImageID    string     `json:"image_id"`
+	Message    string     `json:"message"`
+	ParentID   *string    `json:"parent_id,omitempty"`
+	Depth      int        `json:"depth"`
+	ReplyCount int        `json:"reply_count"`
+	LikeCount  int        `json:"like_count"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+	User       *User      `json:"user"`
+	Image      *Image     `json:"image"`
 }
 
 type ResponseDataGetedComment struct {
-	Status string         `json:"status" example:"success"`
-	Data   []GetedComment `json:"data"`
+	Status     string         `json:"status" example:"success"`
+	Data       []GetedComment `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
+}
+
+type ResponseDataGetedCommentByID struct {
+	Status string       `json:"status" example:"success"`
+	Data   GetedComment `json:"data"`
+}
+
+type ImageComment struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id,omitempty"`
+	Message    string     `json:"message"`
+	ParentID   *string    `json:"parent_id,omitempty"`
+	Depth      int        `json:"depth"`
+	ReplyCount int        `json:"reply_count"`
+	LikeCount  int        `json:"like_count"`
+	LikedByMe  bool       `json:"liked_by_me"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+	User       *User      `json:"user"`
+}
+
+type ResponseDataImageComments struct {
+	Status     string         `json:"status" example:"success"`
+	Data       []ImageComment `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
+}
+
+type CommentThread struct {
+	ImageComment
+	Replies []*CommentThread `json:"replies"`
+}
+
+type ResponseDataCommentThread struct {
+	Status string        `json:"status" example:"success"`
+	Data   CommentThread `json:"data"`
 }
 
 type AddComment struct {
-	Message string `json:"message" example:"A comment"`
-	ImageID string `json:"image_id" example:"image-123"`
+	Message  string `json:"message" example:"A comment"`
+	ImageID  string `json:"image_id" example:"image-123"`
+	ParentID string `json:"parent_id,omitempty" example:"comment-123"`
 }
 
 type AddedComment struct {
@@ -42,6 +88,8 @@ type AddedComment struct {
 	UserID    string     `json:"user_id" example:"here is the generated user id"`
 	ImageID   string     `json:"image_id" example:"here is the generated image id"`
 	Message   string     `json:"message" example:"A comment"`
+	ParentID  *string    `json:"parent_id,omitempty" example:"comment-123"`
+	Depth     int        `json:"depth" example:"1"`
 	CreatedAt *time.Time `json:"created_at" example:"the created at generated here"`
 }
 
@@ -0,0 +1,756 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the validated application configuration. It is built once at
+// startup by Load and handed to the packages that need it.
+type Config struct {
+	HTTP         HTTP         `yaml:"http"`
+	Database     Database     `yaml:"database"`
+	Token        Token        `yaml:"token"`
+	CORS         CORS         `yaml:"cors"`
+	Storage      Storage      `yaml:"storage"`
+	Images       Images       `yaml:"images"`
+	Comments     Comments     `yaml:"comments"`
+	Feed         Feed         `yaml:"feed"`
+	Mail         Mail         `yaml:"mail"`
+	Verification Verification `yaml:"verification"`
+	Login        Login        `yaml:"login"`
+	RateLimit    RateLimit    `yaml:"rate_limit"`
+	LogLevel     string       `yaml:"log_level"`
+}
+
+// HTTP holds how the server listens. TrustedProxies lists the addresses and
+// CIDR ranges of the proxies in front of it, whose X-Forwarded-For header is
+// believed for the client address. Without any, the client address is the
+// address of the peer.
+type HTTP struct {
+	Port           string   `yaml:"port"`
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+type Database struct {
+	Host     string `yaml:"host"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	Port     string `yaml:"port"`
+	SSLMode  string `yaml:"ssl_mode"`
+	TimeZone string `yaml:"time_zone"`
+}
+
+// Token holds how tokens are issued. Access tokens are signed with Key using
+// HS256 unless SigningKeys are set, in which case each signs them from its
+// ActiveFrom on until the next one is active.
+type Token struct {
+	Key         string        `yaml:"key"`
+	AccessTTL   time.Duration `yaml:"access_ttl"`
+	RefreshTTL  time.Duration `yaml:"refresh_ttl"`
+	ResetTTL    time.Duration `yaml:"reset_ttl"`
+	SigningKeys []SigningKey  `yaml:"signing_keys"`
+}
+
+// SigningKey is a PEM encoded RSA or Ed25519 private key at Path, published
+// as ID in the kid header of the tokens it signs.
+type SigningKey struct {
+	ID         string    `yaml:"kid"`
+	Path       string    `yaml:"path"`
+	ActiveFrom time.Time `yaml:"active_from"`
+}
+
+type CORS struct {
+	AllowOrigins []string `yaml:"allow_origins"`
+}
+
+// Storage selects where uploaded files are kept. URLKey signs the links the
+// files are served through and falls back to the token key when unset.
+type Storage struct {
+	Driver        string        `yaml:"driver"`
+	LocalDir      string        `yaml:"local_dir"`
+	S3            S3            `yaml:"s3"`
+	URLKey        string        `yaml:"url_key"`
+	URLTTL        time.Duration `yaml:"url_ttl"`
+	MaxUploadSize int64         `yaml:"max_upload_size"`
+}
+
+// Images holds how uploads are checked. DuplicatePolicy is reject to refuse
+// an image the user has already uploaded, or flag to accept it marked as a
+// duplicate.
+type Images struct {
+	DuplicatePolicy string `yaml:"duplicate_policy"`
+}
+
+// Comments holds how deep replies may nest. Top-level comments have depth
+// 0, so a MaxDepth of 0 turns replies off.
+type Comments struct {
+	MaxDepth int `yaml:"max_depth"`
+}
+
+// Feed selects how home feeds are built. Strategy is read to query the
+// images of the followed accounts on every request, or write to copy every
+// image into the feeds of the followers when it is posted.
+type Feed struct {
+	Strategy string `yaml:"strategy"`
+}
+
+// Mail selects how emails are sent. Driver is smtp to deliver them through
+// the SMTP server, or outbox to keep them in memory without delivering them.
+// ResetURL is the page password reset emails link to, and may be empty to
+// send the bare token.
+type Mail struct {
+	Driver   string `yaml:"driver"`
+	From     string `yaml:"from"`
+	SMTP     SMTP   `yaml:"smtp"`
+	ResetURL string `yaml:"reset_url"`
+}
+
+// Verification holds how emails are verified. Emails link to URL with the
+// token in the query, or carry the bare token when it is empty. A user is
+// sent at most ResendLimit verification emails an hour, and can't do the
+// actions listed in Restrict until the email is verified.
+type Verification struct {
+	TTL         time.Duration `yaml:"ttl"`
+	URL         string        `yaml:"url"`
+	ResendLimit int           `yaml:"resend_limit"`
+	Restrict    []string      `yaml:"restrict"`
+}
+
+// Login holds how password guessing is slowed down. Failed logins make the
+// account and the client address wait twice as long as the time before, and
+// MaxAttempts failures in a row for an account, or IPMaxAttempts from an
+// address, lock it out for Lockout. Store is postgres to count the failures
+// in the database, or memory to count them in every instance on its own.
+type Login struct {
+	Store         string        `yaml:"store"`
+	MaxAttempts   int           `yaml:"max_attempts"`
+	IPMaxAttempts int           `yaml:"ip_max_attempts"`
+	Lockout       time.Duration `yaml:"lockout"`
+}
+
+// RateLimit holds the token buckets requests are limited with, by the name
+// of the policy. A policy lets a client send a burst of Limit requests and
+// then Limit requests every Period. Store is memory to keep the buckets in
+// every instance on its own, or postgres to share them between instances.
+type RateLimit struct {
+	Store    string                     `yaml:"store"`
+	Policies map[string]RateLimitPolicy `yaml:"policies"`
+}
+
+type RateLimitPolicy struct {
+	Limit  int           `yaml:"limit"`
+	Period time.Duration `yaml:"period"`
+}
+
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type S3 struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// DSN returns the Postgres connection string for the database settings.
+func (database Database) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s", database.Host, database.User, database.Password, database.Name, database.Port, database.SSLMode, database.TimeZone)
+}
+
+// ValidationError lists every configuration key that is missing or invalid.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+var (
+	sslModes  = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logLevels = []string{"debug", "info", "warn", "error", "silent"}
+	drivers   = []string{"local", "s3"}
+	policies  = []string{"reject", "flag"}
+	feeds     = []string{"read", "write"}
+	mailers   = []string{"smtp", "outbox"}
+	actions   = []string{"images", "comments"}
+	stores    = []string{"postgres", "memory"}
+
+	rateLimitPolicies = []string{"default", "images.create", "comments.create", "users.login_2fa", "users.verify_email", "users.resend_verification", "users.forgot_password"}
+)
+
+// Load reads the configuration from, in increasing order of precedence, the
+// built-in defaults, the YAML file named by CONFIG_FILE (if any), the .env
+// file in the working directory (if any) and the process environment.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("loading .env file: %w", err)
+	}
+
+	config := defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+
+		if err = yaml.Unmarshal(file, config); err != nil {
+			return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	}
+
+	problems := config.fromEnv()
+
+	if config.Storage.URLKey == "" {
+		config.Storage.URLKey = config.Token.Key
+	}
+
+	problems = append(problems, config.validate()...)
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{problems}
+	}
+
+	return config, nil
+}
+
+func defaults() *Config {
+	sslMode := "disable"
+
+	if os.Getenv("ENV") == "production" {
+		sslMode = "require"
+	}
+
+	return &Config{
+		HTTP: HTTP{
+			Port: "8080",
+		},
+		Database: Database{
+			Port:     "5432",
+			SSLMode:  sslMode,
+			TimeZone: "UTC",
+		},
+		Token: Token{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 30 * 24 * time.Hour,
+			ResetTTL:   time.Hour,
+		},
+		CORS: CORS{
+			AllowOrigins: []string{"*"},
+		},
+		Storage: Storage{
+			Driver:        "local",
+			LocalDir:      "uploads",
+			S3:            S3{Region: "us-east-1"},
+			URLTTL:        15 * time.Minute,
+			MaxUploadSize: 10 << 20,
+		},
+		Images: Images{
+			DuplicatePolicy: "reject",
+		},
+		Comments: Comments{
+			MaxDepth: 5,
+		},
+		Feed: Feed{
+			Strategy: "read",
+		},
+		Mail: Mail{
+			Driver: "outbox",
+			SMTP:   SMTP{Port: "587"},
+		},
+		Verification: Verification{
+			TTL:         24 * time.Hour,
+			ResendLimit: 3,
+			Restrict:    []string{"images", "comments"},
+		},
+		Login: Login{
+			Store:         "postgres",
+			MaxAttempts:   5,
+			IPMaxAttempts: 20,
+			Lockout:       15 * time.Minute,
+		},
+		RateLimit: RateLimit{
+			Store: "memory",
+			Policies: map[string]RateLimitPolicy{
+				"default":                   {Limit: 300, Period: time.Minute},
+				"images.create":             {Limit: 10, Period: time.Minute},
+				"comments.create":           {Limit: 30, Period: time.Minute},
+				"users.login_2fa":           {Limit: 10, Period: time.Minute},
+				"users.verify_email":        {Limit: 10, Period: time.Minute},
+				"users.resend_verification": {Limit: 5, Period: time.Minute},
+				"users.forgot_password":     {Limit: 3, Period: time.Hour},
+			},
+		},
+		LogLevel: "info",
+	}
+}
+
+func (config *Config) fromEnv() (problems []string) {
+	setString(&config.HTTP.Port, "PORT")
+	setString(&config.Database.Host, "PGHOST")
+	setString(&config.Database.User, "PGUSER")
+	setString(&config.Database.Password, "PGPASSWORD")
+	setString(&config.Database.Name, "PGDBNAME")
+	setString(&config.Database.Port, "PGPORT")
+	setString(&config.Database.SSLMode, "PGSSLMODE")
+	setString(&config.Database.TimeZone, "TIMEZONE")
+	setString(&config.Token.Key, "TOKEN_KEY")
+	setString(&config.LogLevel, "LOG_LEVEL")
+	setString(&config.Storage.Driver, "STORAGE_DRIVER")
+	setString(&config.Storage.LocalDir, "STORAGE_LOCAL_DIR")
+	setString(&config.Storage.URLKey, "STORAGE_URL_KEY")
+	setString(&config.Storage.S3.Endpoint, "S3_ENDPOINT")
+	setString(&config.Storage.S3.Region, "S3_REGION")
+	setString(&config.Storage.S3.Bucket, "S3_BUCKET")
+	setString(&config.Storage.S3.AccessKey, "S3_ACCESS_KEY")
+	setString(&config.Storage.S3.SecretKey, "S3_SECRET_KEY")
+	setString(&config.Images.DuplicatePolicy, "DUPLICATE_IMAGE_POLICY")
+	setString(&config.Feed.Strategy, "FEED_STRATEGY")
+	setString(&config.Mail.Driver, "MAIL_DRIVER")
+	setString(&config.Mail.From, "MAIL_FROM")
+	setString(&config.Mail.ResetURL, "PASSWORD_RESET_URL")
+	setString(&config.Mail.SMTP.Host, "SMTP_HOST")
+	setString(&config.Mail.SMTP.Port, "SMTP_PORT")
+	setString(&config.Mail.SMTP.Username, "SMTP_USERNAME")
+	setString(&config.Mail.SMTP.Password, "SMTP_PASSWORD")
+	setString(&config.Verification.URL, "EMAIL_VERIFICATION_URL")
+	setString(&config.Login.Store, "LOGIN_ATTEMPT_STORE")
+	setString(&config.RateLimit.Store, "RATE_LIMIT_STORE")
+
+	setList(&config.HTTP.TrustedProxies, "TRUSTED_PROXIES")
+	setList(&config.CORS.AllowOrigins, "CORS_ALLOW_ORIGINS")
+	setList(&config.Verification.Restrict, "UNVERIFIED_RESTRICTIONS")
+
+	if err := setDuration(&config.Token.AccessTTL, "ACCESS_TOKEN_TTL"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setDuration(&config.Token.RefreshTTL, "REFRESH_TOKEN_TTL"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setDuration(&config.Token.ResetTTL, "PASSWORD_RESET_TTL"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setDuration(&config.Storage.URLTTL, "STORAGE_URL_TTL"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setInt64(&config.Storage.MaxUploadSize, "MAX_UPLOAD_SIZE"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setInt(&config.Comments.MaxDepth, "COMMENT_MAX_DEPTH"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setDuration(&config.Verification.TTL, "EMAIL_VERIFICATION_TTL"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setInt(&config.Verification.ResendLimit, "EMAIL_VERIFICATION_RESEND_LIMIT"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setInt(&config.Login.MaxAttempts, "LOGIN_MAX_ATTEMPTS"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setInt(&config.Login.IPMaxAttempts, "LOGIN_IP_MAX_ATTEMPTS"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := setDuration(&config.Login.Lockout, "LOGIN_LOCKOUT"); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if config.RateLimit.Policies == nil {
+		config.RateLimit.Policies = map[string]RateLimitPolicy{}
+	}
+
+	problems = append(problems, setRateLimits(config.RateLimit.Policies, "RATE_LIMITS")...)
+	problems = append(problems, setSigningKeys(&config.Token.SigningKeys, "TOKEN_SIGNING_KEYS")...)
+
+	return problems
+}
+
+func (config *Config) validate() (problems []string) {
+	required := map[string]string{
+		"PORT":      config.HTTP.Port,
+		"PGHOST":    config.Database.Host,
+		"PGUSER":    config.Database.User,
+		"PGDBNAME":  config.Database.Name,
+		"PGPORT":    config.Database.Port,
+		"TOKEN_KEY": config.Token.Key,
+	}
+
+	for _, key := range []string{"PORT", "PGHOST", "PGUSER", "PGDBNAME", "PGPORT", "TOKEN_KEY"} {
+		if required[key] == "" {
+			problems = append(problems, fmt.Sprintf("%s is required", key))
+		}
+	}
+
+	if !isPort(config.HTTP.Port) && config.HTTP.Port != "" {
+		problems = append(problems, fmt.Sprintf("PORT must be a port number, got %q", config.HTTP.Port))
+	}
+
+	for _, proxy := range config.HTTP.TrustedProxies {
+		if !isAddress(proxy) {
+			problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES must only list IP addresses and CIDR ranges, got %q", proxy))
+		}
+	}
+
+	if !isPort(config.Database.Port) && config.Database.Port != "" {
+		problems = append(problems, fmt.Sprintf("PGPORT must be a port number, got %q", config.Database.Port))
+	}
+
+	if !contains(sslModes, config.Database.SSLMode) {
+		problems = append(problems, fmt.Sprintf("PGSSLMODE must be one of %s, got %q", strings.Join(sslModes, ", "), config.Database.SSLMode))
+	}
+
+	if config.Token.AccessTTL <= 0 {
+		problems = append(problems, "ACCESS_TOKEN_TTL must be positive")
+	}
+
+	if config.Token.RefreshTTL <= config.Token.AccessTTL {
+		problems = append(problems, "REFRESH_TOKEN_TTL must be longer than ACCESS_TOKEN_TTL")
+	}
+
+	kids := map[string]bool{}
+	active := false
+
+	for _, signingKey := range config.Token.SigningKeys {
+		if signingKey.ID == "" || signingKey.Path == "" {
+			problems = append(problems, "TOKEN_SIGNING_KEYS must give every key a kid and a path")
+		}
+
+		if kids[signingKey.ID] {
+			problems = append(problems, fmt.Sprintf("TOKEN_SIGNING_KEYS has more than one key with kid %q", signingKey.ID))
+		}
+
+		kids[signingKey.ID] = true
+		active = active || !signingKey.ActiveFrom.After(time.Now())
+	}
+
+	if len(config.Token.SigningKeys) > 0 && !active {
+		problems = append(problems, "TOKEN_SIGNING_KEYS must have a key that is already active")
+	}
+
+	if config.Token.ResetTTL <= 0 {
+		problems = append(problems, "PASSWORD_RESET_TTL must be positive")
+	}
+
+	if len(config.CORS.AllowOrigins) == 0 {
+		problems = append(problems, "CORS_ALLOW_ORIGINS must list at least one origin")
+	} else if len(config.CORS.AllowOrigins) > 1 && contains(config.CORS.AllowOrigins, "*") {
+		problems = append(problems, "CORS_ALLOW_ORIGINS must either be * or list the origins, not both")
+	}
+
+	if !contains(drivers, config.Storage.Driver) {
+		problems = append(problems, fmt.Sprintf("STORAGE_DRIVER must be one of %s, got %q", strings.Join(drivers, ", "), config.Storage.Driver))
+	}
+
+	if config.Storage.Driver == "local" && config.Storage.LocalDir == "" {
+		problems = append(problems, "STORAGE_LOCAL_DIR is required when STORAGE_DRIVER is local")
+	}
+
+	if config.Storage.Driver == "s3" {
+		s3 := map[string]string{
+			"S3_ENDPOINT":   config.Storage.S3.Endpoint,
+			"S3_REGION":     config.Storage.S3.Region,
+			"S3_BUCKET":     config.Storage.S3.Bucket,
+			"S3_ACCESS_KEY": config.Storage.S3.AccessKey,
+			"S3_SECRET_KEY": config.Storage.S3.SecretKey,
+		}
+
+		for _, key := range []string{"S3_ENDPOINT", "S3_REGION", "S3_BUCKET", "S3_ACCESS_KEY", "S3_SECRET_KEY"} {
+			if s3[key] == "" {
+				problems = append(problems, fmt.Sprintf("%s is required when STORAGE_DRIVER is s3", key))
+			}
+		}
+	}
+
+	if config.Storage.URLTTL <= 0 {
+		problems = append(problems, "STORAGE_URL_TTL must be positive")
+	}
+
+	if config.Storage.MaxUploadSize <= 0 {
+		problems = append(problems, "MAX_UPLOAD_SIZE must be positive")
+	}
+
+	if !contains(policies, config.Images.DuplicatePolicy) {
+		problems = append(problems, fmt.Sprintf("DUPLICATE_IMAGE_POLICY must be one of %s, got %q", strings.Join(policies, ", "), config.Images.DuplicatePolicy))
+	}
+
+	if config.Comments.MaxDepth < 0 {
+		problems = append(problems, "COMMENT_MAX_DEPTH must not be negative")
+	}
+
+	if !contains(feeds, config.Feed.Strategy) {
+		problems = append(problems, fmt.Sprintf("FEED_STRATEGY must be one of %s, got %q", strings.Join(feeds, ", "), config.Feed.Strategy))
+	}
+
+	if !contains(mailers, config.Mail.Driver) {
+		problems = append(problems, fmt.Sprintf("MAIL_DRIVER must be one of %s, got %q", strings.Join(mailers, ", "), config.Mail.Driver))
+	}
+
+	if config.Mail.Driver == "smtp" {
+		if config.Mail.From == "" {
+			problems = append(problems, "MAIL_FROM is required when MAIL_DRIVER is smtp")
+		}
+
+		if config.Mail.SMTP.Host == "" {
+			problems = append(problems, "SMTP_HOST is required when MAIL_DRIVER is smtp")
+		}
+
+		if !isPort(config.Mail.SMTP.Port) {
+			problems = append(problems, fmt.Sprintf("SMTP_PORT must be a port number, got %q", config.Mail.SMTP.Port))
+		}
+	}
+
+	if config.Verification.TTL < time.Hour {
+		problems = append(problems, "EMAIL_VERIFICATION_TTL must be at least 1h")
+	}
+
+	if config.Verification.ResendLimit < 1 {
+		problems = append(problems, "EMAIL_VERIFICATION_RESEND_LIMIT must be at least 1")
+	}
+
+	for _, action := range config.Verification.Restrict {
+		if !contains(actions, action) {
+			problems = append(problems, fmt.Sprintf("UNVERIFIED_RESTRICTIONS must only list %s, got %q", strings.Join(actions, ", "), action))
+		}
+	}
+
+	if !contains(stores, config.Login.Store) {
+		problems = append(problems, fmt.Sprintf("LOGIN_ATTEMPT_STORE must be one of %s, got %q", strings.Join(stores, ", "), config.Login.Store))
+	}
+
+	if config.Login.MaxAttempts < 1 {
+		problems = append(problems, "LOGIN_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if config.Login.IPMaxAttempts < 1 {
+		problems = append(problems, "LOGIN_IP_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if config.Login.Lockout <= 0 {
+		problems = append(problems, "LOGIN_LOCKOUT must be positive")
+	}
+
+	if !contains(stores, config.RateLimit.Store) {
+		problems = append(problems, fmt.Sprintf("RATE_LIMIT_STORE must be one of %s, got %q", strings.Join(stores, ", "), config.RateLimit.Store))
+	}
+
+	for name := range config.RateLimit.Policies {
+		if !contains(rateLimitPolicies, name) {
+			problems = append(problems, fmt.Sprintf("RATE_LIMITS must only set %s, got %q", strings.Join(rateLimitPolicies, ", "), name))
+		}
+	}
+
+	for _, name := range rateLimitPolicies {
+		if policy := config.RateLimit.Policies[name]; policy.Limit < 1 || policy.Period <= 0 {
+			problems = append(problems, fmt.Sprintf("RATE_LIMITS policy %s must let at least 1 request through every positive period", name))
+		}
+	}
+
+	if !contains(logLevels, config.LogLevel) {
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be one of %s, got %q", strings.Join(logLevels, ", "), config.LogLevel))
+	}
+
+	return problems
+}
+
+func setString(field *string, key string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*field = value
+	}
+}
+
+// setList splits the comma separated value of key into field. An empty value
+// empties the list.
+func setList(field *[]string, key string) {
+	value, ok := os.LookupEnv(key)
+
+	if !ok {
+		return
+	}
+
+	*field = nil
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*field = append(*field, item)
+		}
+	}
+}
+
+// setRateLimits overrides policies with the comma separated value of key,
+// such as "images.create=10/1m,comments.create=30/1m".
+func setRateLimits(policies map[string]RateLimitPolicy, key string) (problems []string) {
+	value, ok := os.LookupEnv(key)
+
+	if !ok {
+		return nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		name, rate, _ := strings.Cut(item, "=")
+		limit, period, _ := strings.Cut(rate, "/")
+		number, limitErr := strconv.Atoi(limit)
+		duration, periodErr := time.ParseDuration(period)
+
+		if limitErr != nil || periodErr != nil {
+			problems = append(problems, fmt.Sprintf("%s must list policies such as images.create=10/1m, got %q", key, item))
+
+			continue
+		}
+
+		policies[strings.TrimSpace(name)] = RateLimitPolicy{Limit: number, Period: duration}
+	}
+
+	return problems
+}
+
+// setSigningKeys replaces field with the comma separated value of key, such
+// as "2026-10=keys/2026-10.pem,2026-11=keys/2026-11.pem@2026-11-01T00:00:00Z".
+// A key without a time after @ is active from the start.
+func setSigningKeys(field *[]SigningKey, key string) (problems []string) {
+	value, ok := os.LookupEnv(key)
+
+	if !ok {
+		return nil
+	}
+
+	*field = nil
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		id, path, _ := strings.Cut(item, "=")
+		path, from, scheduled := strings.Cut(path, "@")
+		signingKey := SigningKey{ID: strings.TrimSpace(id), Path: strings.TrimSpace(path)}
+
+		if scheduled {
+			activeFrom, err := time.Parse(time.RFC3339, strings.TrimSpace(from))
+
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s must list keys such as kid=path@2006-01-02T15:04:05Z, got %q", key, item))
+
+				continue
+			}
+
+			signingKey.ActiveFrom = activeFrom
+		}
+
+		*field = append(*field, signingKey)
+	}
+
+	return problems
+}
+
+func setDuration(field *time.Duration, key string) error {
+	value, ok := os.LookupEnv(key)
+
+	if !ok {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(value)
+
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as 15m or 720h, got %q", key, value)
+	}
+
+	*field = duration
+
+	return nil
+}
+
+func setInt(field *int, key string) error {
+	value, ok := os.LookupEnv(key)
+
+	if !ok {
+		return nil
+	}
+
+	number, err := strconv.Atoi(value)
+
+	if err != nil {
+		return fmt.Errorf("%s must be a number, got %q", key, value)
+	}
+
+	*field = number
+
+	return nil
+}
+
+func setInt64(field *int64, key string) error {
+	value, ok := os.LookupEnv(key)
+
+	if !ok {
+		return nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("%s must be a number of bytes, got %q", key, value)
+	}
+
+	*field = number
+
+	return nil
+}
+
+func isPort(value string) bool {
+	port, err := strconv.Atoi(value)
+
+	return err == nil && port > 0 && port <= 65535
+}
+
+// isAddress reports whether value is an IP address or a CIDR range.
+func isAddress(value string) bool {
+	if net.ParseIP(value) != nil {
+		return true
+	}
+
+	_, _, err := net.ParseCIDR(value)
+
+	return err == nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,226 @@
+package config_test
+
+import (
+	"errors"
+	"mygram-byferdiansyah/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("PGHOST", "localhost")
+	t.Setenv("PGUSER", "postgres")
+	t.Setenv("PGDBNAME", "mygram")
+	t.Setenv("TOKEN_KEY", "secret")
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("load config from env with defaults", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("CORS_ALLOW_ORIGINS", "https://mygram.example.com, https://admin.example.com")
+
+		cfg, err := config.Load()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "8080", cfg.HTTP.Port)
+		assert.Equal(t, "disable", cfg.Database.SSLMode)
+		assert.Equal(t, 15*time.Minute, cfg.Token.AccessTTL)
+		assert.Equal(t, []string{"https://mygram.example.com", "https://admin.example.com"}, cfg.CORS.AllowOrigins)
+		assert.Contains(t, cfg.Database.DSN(), "host=localhost user=postgres")
+	})
+
+	t.Run("load config from yaml file overridden by env", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		yaml := "http:\n  port: \"9090\"\ndatabase:\n  host: db\n  user: mygram\n  name: mygram\ntoken:\n  key: from-file\n  access_ttl: 5m\n"
+
+		assert.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+
+		t.Setenv("CONFIG_FILE", path)
+		t.Setenv("TOKEN_KEY", "from-env")
+
+		cfg, err := config.Load()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "9090", cfg.HTTP.Port)
+		assert.Equal(t, "db", cfg.Database.Host)
+		assert.Equal(t, "from-env", cfg.Token.Key)
+		assert.Equal(t, 5*time.Minute, cfg.Token.AccessTTL)
+	})
+
+	t.Run("load config lists every invalid key", func(t *testing.T) {
+		t.Setenv("PGHOST", "localhost")
+		t.Setenv("PORT", "http")
+		t.Setenv("ACCESS_TOKEN_TTL", "forever")
+		t.Setenv("PGSSLMODE", "sometimes")
+		t.Setenv("COMMENT_MAX_DEPTH", "-1")
+		t.Setenv("FEED_STRATEGY", "push")
+		t.Setenv("UNVERIFIED_RESTRICTIONS", "images,likes")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Contains(t, validationError.Problems, "PGUSER is required")
+		assert.Contains(t, validationError.Problems, "PGDBNAME is required")
+		assert.Contains(t, validationError.Problems, "TOKEN_KEY is required")
+		assert.Contains(t, err.Error(), "PORT must be a port number")
+		assert.Contains(t, err.Error(), "ACCESS_TOKEN_TTL must be a duration")
+		assert.Contains(t, err.Error(), "PGSSLMODE must be one of")
+		assert.Contains(t, validationError.Problems, "COMMENT_MAX_DEPTH must not be negative")
+		assert.Contains(t, err.Error(), "FEED_STRATEGY must be one of")
+		assert.Contains(t, err.Error(), `UNVERIFIED_RESTRICTIONS must only list images, comments, got "likes"`)
+	})
+
+	t.Run("load config requires the s3 settings for the s3 driver", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("STORAGE_DRIVER", "s3")
+		t.Setenv("S3_ENDPOINT", "http://localhost:9000")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Contains(t, validationError.Problems, "S3_BUCKET is required when STORAGE_DRIVER is s3")
+		assert.NotContains(t, validationError.Problems, "S3_ENDPOINT is required when STORAGE_DRIVER is s3")
+	})
+
+	t.Run("load config requires the smtp settings for the smtp driver", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("MAIL_DRIVER", "smtp")
+		t.Setenv("SMTP_HOST", "smtp.example.com")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Contains(t, validationError.Problems, "MAIL_FROM is required when MAIL_DRIVER is smtp")
+		assert.NotContains(t, validationError.Problems, "SMTP_HOST is required when MAIL_DRIVER is smtp")
+	})
+
+	t.Run("load config signs file urls with the token key by default", func(t *testing.T) {
+		setRequiredEnv(t)
+
+		cfg, err := config.Load()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", cfg.Storage.URLKey)
+		assert.Equal(t, int64(10<<20), cfg.Storage.MaxUploadSize)
+		assert.Equal(t, 5, cfg.Comments.MaxDepth)
+		assert.Equal(t, "read", cfg.Feed.Strategy)
+		assert.Equal(t, "outbox", cfg.Mail.Driver)
+		assert.Equal(t, time.Hour, cfg.Token.ResetTTL)
+		assert.Equal(t, []string{"images", "comments"}, cfg.Verification.Restrict)
+		assert.Equal(t, "postgres", cfg.Login.Store)
+		assert.Equal(t, 15*time.Minute, cfg.Login.Lockout)
+	})
+
+	t.Run("load config with scheduled signing keys", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("TOKEN_SIGNING_KEYS", "2026-10=keys/2026-10.pem, 2026-11=keys/2026-11.pem@2026-11-01T00:00:00Z")
+
+		cfg, err := config.Load()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []config.SigningKey{
+			{ID: "2026-10", Path: "keys/2026-10.pem"},
+			{ID: "2026-11", Path: "keys/2026-11.pem", ActiveFrom: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		}, cfg.Token.SigningKeys)
+	})
+
+	t.Run("load config with invalid signing keys", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("TOKEN_SIGNING_KEYS", "next=keys/next.pem@2999-01-01T00:00:00Z,next=keys/other.pem@2999-02-01T00:00:00Z,later=keys/later.pem@soon")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Contains(t, validationError.Problems, `TOKEN_SIGNING_KEYS must list keys such as kid=path@2006-01-02T15:04:05Z, got "later=keys/later.pem@soon"`)
+		assert.Contains(t, validationError.Problems, `TOKEN_SIGNING_KEYS has more than one key with kid "next"`)
+		assert.Contains(t, validationError.Problems, "TOKEN_SIGNING_KEYS must have a key that is already active")
+	})
+
+	t.Run("load config with rate limits overridden by env", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("RATE_LIMITS", "images.create=3/1h, comments.create=20/30s")
+
+		cfg, err := config.Load()
+
+		assert.NoError(t, err)
+		assert.Equal(t, config.RateLimitPolicy{Limit: 3, Period: time.Hour}, cfg.RateLimit.Policies["images.create"])
+		assert.Equal(t, config.RateLimitPolicy{Limit: 20, Period: 30 * time.Second}, cfg.RateLimit.Policies["comments.create"])
+		assert.Equal(t, config.RateLimitPolicy{Limit: 300, Period: time.Minute}, cfg.RateLimit.Policies["default"])
+	})
+
+	t.Run("load config with invalid rate limits", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("RATE_LIMITS", "images.create=0/1m,uploads=5/1m,comments.create=fast")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Contains(t, validationError.Problems, `RATE_LIMITS must list policies such as images.create=10/1m, got "comments.create=fast"`)
+		assert.Contains(t, validationError.Problems, "RATE_LIMITS policy images.create must let at least 1 request through every positive period")
+		assert.Contains(t, validationError.Problems, `RATE_LIMITS must only set default, images.create, comments.create, users.login_2fa, users.verify_email, users.resend_verification, users.forgot_password, got "uploads"`)
+	})
+
+	t.Run("load config with an invalid login policy", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("LOGIN_ATTEMPT_STORE", "redis")
+		t.Setenv("LOGIN_MAX_ATTEMPTS", "0")
+		t.Setenv("LOGIN_LOCKOUT", "forever")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Contains(t, validationError.Problems, `LOGIN_ATTEMPT_STORE must be one of postgres, memory, got "redis"`)
+		assert.Contains(t, validationError.Problems, "LOGIN_MAX_ATTEMPTS must be at least 1")
+		assert.Contains(t, validationError.Problems, `LOGIN_LOCKOUT must be a duration such as 15m or 720h, got "forever"`)
+	})
+
+	t.Run("load config without restrictions for unverified users", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("UNVERIFIED_RESTRICTIONS", "")
+
+		cfg, err := config.Load()
+
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.Verification.Restrict)
+	})
+
+	t.Run("load config with trusted proxies", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.0.2.1, proxy.example.com")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Equal(t, []string{`TRUSTED_PROXIES must only list IP addresses and CIDR ranges, got "proxy.example.com"`}, validationError.Problems)
+	})
+
+	t.Run("load config with a wildcard among the cors origins", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("CORS_ALLOW_ORIGINS", "*, https://mygram.example.com")
+
+		_, err := config.Load()
+
+		var validationError *config.ValidationError
+
+		assert.True(t, errors.As(err, &validationError))
+		assert.Equal(t, []string{"CORS_ALLOW_ORIGINS must either be * or list the origins, not both"}, validationError.Problems)
+	})
+}
@@ -130,42 +130,116 @@ package database
 // uncomment ini kalau butuh buat di rail way
 
 import (
-	"fmt"
 	"log"
+	"mygram-byferdiansyah/config"
 	"mygram-byferdiansyah/domain"
-	"os"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
 )
 
-func StartDB() *gorm.DB {
+var logLevels = map[string]logger.LogLevel{
+	"debug":  logger.Info,
+	"info":   logger.Warn,
+	"warn":   logger.Warn,
+	"error":  logger.Error,
+	"silent": logger.Silent,
+}
+
+func StartDB(config *config.Config) *gorm.DB {
 	var (
-		env      = os.Getenv("ENV")
-		host     = os.Getenv("PGHOST")
-		user     = os.Getenv("PGUSER")
-		password = os.Getenv("PGPASSWORD")
-		dbname   = os.Getenv("PGDBNAME")
-		port     = os.Getenv("PGPORT")
-		timeZone = os.Getenv("TIMEZONE")
-		dsn      = ""
-		db       *gorm.DB
-		err      error
+		db  *gorm.DB
+		err error
 	)
 
-	if env == "production" {
-		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=require TimeZone=%s", host, user, password, dbname, port, timeZone)
-	} else {
-		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=%s", host, user, password, dbname, port, timeZone)
+	gormConfig := &gorm.Config{
+		FullSaveAssociations: true,
+		Logger:               logger.Default.LogMode(logLevels[config.LogLevel]),
 	}
 
-	if db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{FullSaveAssociations: true}); err != nil {
+	if db, err = gorm.Open(postgres.Open(config.Database.DSN()), gormConfig); err != nil {
 		log.Fatal("Error connecting to database: ", err)
 	}
 
-	if err = db.AutoMigrate(&domain.User{}, &domain.Image{}, &domain.Comment{}, &domain.SocialMedia{}); err != nil {
+	if err = Migrate(db); err != nil {
 		log.Fatal("Error migrating database: ", err.Error())
 	}
 
 	return db
 }
+
+// schemaMigration records a data migration that has run, so it only runs
+// once however many times the app starts.
+type schemaMigration struct {
+	Name      string     `gorm:"primaryKey;type:VARCHAR(100)"`
+	AppliedAt *time.Time `gorm:"not null;autoCreateTime"`
+}
+
+// Migrate creates or updates the tables of every model and runs the data
+// migrations that haven't run yet. Tests against a real database call it on
+// their own connection.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&schemaMigration{}, &domain.User{}, &domain.Image{}, &domain.Comment{}, &domain.SocialMedia{}, &domain.RefreshToken{}, &domain.RevokedToken{}, &domain.TokenCutoff{}, &domain.AuditLog{}, &domain.ImageVariant{}, &domain.Like{}, &domain.Follow{}, &domain.FeedItem{}, &domain.PasswordReset{}, &domain.EmailVerification{}, &domain.RecoveryCode{}, &domain.LoginChallenge{}, &domain.LoginAttempt{}, &domain.RateLimitBucket{}, &domain.APIKey{}); err != nil {
+		return err
+	}
+
+	if err := runOnce(db, "verify_existing_emails", verifyExistingEmails); err != nil {
+		return err
+	}
+
+	return runOnce(db, "keep_comment_replies", keepCommentReplies)
+}
+
+// keepCommentReplies stops deleting a comment from deleting its replies,
+// which the foreign key to the parent used to cascade to, and lets a
+// tombstone outlive its author.
+func keepCommentReplies(tx *gorm.DB) error {
+	if tx.Migrator().HasConstraint(&domain.Comment{}, "Parent") {
+		if err := tx.Migrator().DropConstraint(&domain.Comment{}, "Parent"); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Migrator().CreateConstraint(&domain.Comment{}, "Parent"); err != nil {
+		return err
+	}
+
+	return tx.Exec("ALTER TABLE comments ALTER COLUMN user_id DROP NOT NULL").Error
+}
+
+// verifyExistingEmails marks the accounts created before emails were
+// verified as verified, so they keep doing what unverified users can't.
+// Those are the accounts older than the first verification token, or every
+// account when none has been sent yet.
+func verifyExistingEmails(tx *gorm.DB) error {
+	var cutoff *time.Time
+
+	if err := tx.Model(&domain.EmailVerification{}).Select("MIN(created_at)").Scan(&cutoff).Error; err != nil {
+		return err
+	}
+
+	query := tx.Model(&domain.User{}).Where("email_verified_at IS NULL")
+
+	if cutoff != nil {
+		query = query.Where("created_at < ?", cutoff)
+	}
+
+	return query.Update("email_verified_at", gorm.Expr("created_at")).Error
+}
+
+// runOnce runs migrate in a transaction along with recording name, unless
+// a migration by that name has already run.
+func runOnce(db *gorm.DB, name string, migrate func(*gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&schemaMigration{Name: name})
+
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		return migrate(tx)
+	})
+}
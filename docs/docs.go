@@ -23,14 +23,34 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/comments": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "List the public keys access tokens are signed with as a JSON Web Key Set, matched to tokens by the kid header. Keys scheduled to sign tokens later are listed ahead of time, and the list is empty while tokens are signed with the shared HS256 key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the token verification keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/audit-logs": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get all comments with authentication user",
+                "description": "Get every recorded moderation and administration action, newest first, available to admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -38,37 +58,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Get all comments",
+                "summary": "Get the audit log",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataGetedComment"
+                            "$ref": "#/definitions/utils.ResponseDataAuditLogs"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/comments/{id}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "create and create a comment with authentication user",
+                "description": "Delete a comment of any user, available to moderators and admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -76,50 +98,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Add a comment",
+                "summary": "Delete any comment",
                 "parameters": [
                     {
-                        "description": "Add Comment",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.AddComment"
-                        }
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataAddedComment"
+                            "$ref": "#/definitions/utils.ResponseMessageModerated"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/comments/{id}": {
-            "put": {
+        "/admin/images/duplicates": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Edit a comment by id with authentication user",
+                "description": "Get pairs of images across all users whose perceptual hashes differ by at most distance bits, available to moderators and admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,61 +153,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Edit a comment",
+                "summary": "Get near-duplicate images",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Comment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Edit Comment",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.EditComment"
-                        }
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Maximum Hamming distance, from 0 to 3",
+                        "name": "distance",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataEditedComment"
+                            "$ref": "#/definitions/utils.ResponseDataImageDuplicates"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/images/{id}": {
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a comment by id with authentication user",
+                "description": "Delete an image of any user, available to moderators and admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -189,13 +208,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Delete a comment",
+                "summary": "Delete any image",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Comment ID",
+                        "description": "Image ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -205,38 +224,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedComment"
+                            "$ref": "#/definitions/utils.ResponseMessageModerated"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/images": {
+        "/admin/users": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get all images with authentication user",
+                "description": "Get every user account with its role and suspension, available to admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -244,37 +263,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "images"
+                    "admin"
                 ],
-                "summary": "Get all images",
+                "summary": "Get all users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataGetedImage"
+                            "$ref": "#/definitions/utils.ResponseDataAdminUsers"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/users/{id}/restore": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create and create a image with authentication user",
+                "description": "Lift the suspension of a user, available to admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -282,50 +303,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "images"
+                    "admin"
                 ],
-                "summary": "Create a image",
+                "summary": "Restore a user",
                 "parameters": [
                     {
-                        "description": "Add Image",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.AddImage"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataAddedImage"
+                            "$ref": "#/definitions/utils.ResponseMessageModerated"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/images/{id}": {
+        "/admin/users/{id}/role": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Edit a image by id with authentication user",
+                "description": "Set the role of a user to user, moderator or admin, available to admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -333,24 +358,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "images"
+                    "admin"
                 ],
-                "summary": "Edit a image",
+                "summary": "Change the role of a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Image ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Image",
+                        "description": "Set Role",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/utils.EditImage"
+                            "$ref": "#/definitions/utils.SetRole"
                         }
                     }
                 ],
@@ -358,36 +383,44 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataEditedImage"
+                            "$ref": "#/definitions/utils.ResponseMessageModerated"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/users/{id}/suspend": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a image by id with authentication user",
+                "description": "Block a user from signing in and sign it out everywhere, available to admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -395,13 +428,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "images"
+                    "admin"
                 ],
-                "summary": "Delete a image",
+                "summary": "Suspend a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Image ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -411,38 +444,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedImage"
+                            "$ref": "#/definitions/utils.ResponseMessageModerated"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_admin_utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/socialmedias": {
+        "/comments": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get all social media with authentication user",
+                "description": "Get all comments with authentication user. Comments on the images of a private account are only shown to the account and its followers",
                 "consumes": [
                     "application/json"
                 ],
@@ -450,26 +483,76 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "comments"
+                ],
+                "summary": "Get all comments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Order by creation time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only comments on this image",
+                        "name": "image_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all social media",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataGetedSocialMedia"
+                            "$ref": "#/definitions/utils.ResponseDataGetedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     }
                 }
@@ -480,7 +563,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create and create a social media with authentication user",
+                "description": "create and create a comment with authentication user",
                 "consumes": [
                     "application/json"
                 ],
@@ -488,17 +571,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "comments"
                 ],
-                "summary": "Add a social media",
+                "summary": "Add a comment",
                 "parameters": [
                     {
-                        "description": "Add Social Media",
+                        "description": "Add Comment",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/utils.AddSocialMedia"
+                            "$ref": "#/definitions/utils.AddComment"
                         }
                     }
                 ],
@@ -506,94 +589,111 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataAddedSocialMedia"
+                            "$ref": "#/definitions/utils.ResponseDataAddedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
-                    }
-                }
-            }
-        },
-        "/socialmedias/{id}": {
-            "put": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Edit a social media by id with authentication user",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{commentId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a comment by id with authentication user. Comments on the images of a private account are only shown to the account and its followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "comments"
                 ],
-                "summary": "Edit a social media",
+                "summary": "Get a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SocialMedia ID",
-                        "name": "id",
+                        "description": "Comment ID",
+                        "name": "commentId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Edit Social Media",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.EditSocialMedia"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataEditedSocialMedia"
+                            "$ref": "#/definitions/utils.ResponseDataGetedCommentByID"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/comments/{commentId}/like": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a social media by id with authentication user",
+                "description": "Like a comment with authentication user. Liking a comment twice changes nothing. Comments on the images of a private account can only be liked by the account and its followers",
                 "consumes": [
                     "application/json"
                 ],
@@ -601,14 +701,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "likes"
                 ],
-                "summary": "Delete a social media",
+                "summary": "Like a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SocialMedia ID",
-                        "name": "id",
+                        "description": "Comment ID",
+                        "name": "commentId",
                         "in": "path",
                         "required": true
                     }
@@ -617,38 +717,30 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedSocialMedia"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseDataLike"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
                         }
                     }
                 }
-            }
-        },
-        "/users": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Edit a user with authentication user",
+                "description": "Take back the like of a comment with authentication user. Unliking a comment that isn't liked changes nothing",
                 "consumes": [
                     "application/json"
                 ],
@@ -656,54 +748,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "likes"
                 ],
-                "summary": "Edit a user",
+                "summary": "Unlike a comment",
                 "parameters": [
                     {
-                        "description": "Edit User",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.EditUser"
-                        }
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataEditedUser"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseDataLike"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/comments/{commentId}/thread": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a user with authentication user",
+                "description": "Get a comment with all of its replies nested below it",
                 "consumes": [
                     "application/json"
                 ],
@@ -711,40 +797,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "comments"
+                ],
+                "summary": "Get a comment thread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Delete a user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedUser"
+                            "$ref": "#/definitions/utils.ResponseDataCommentThread"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/users/login": {
-            "post": {
-                "description": "Authentication a user and retrieve a token",
+        "/comments/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Edit a comment by id with authentication user",
                 "consumes": [
                     "application/json"
                 ],
@@ -752,17 +852,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "comments"
                 ],
-                "summary": "Login a user",
+                "summary": "Edit a comment",
                 "parameters": [
                     {
-                        "description": "Login User",
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Edit Comment",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/utils.LoginUser"
+                            "$ref": "#/definitions/utils.EditComment"
                         }
                     }
                 ],
@@ -770,27 +877,48 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                            "$ref": "#/definitions/utils.ResponseDataEditedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     }
                 }
-            }
-        },
-        "/users/register": {
-            "post": {
-                "description": "create and create a user",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a comment by id with authentication user. A comment that has replies is replaced by \"[deleted]\" so the replies stay",
                 "consumes": [
                     "application/json"
                 ],
@@ -798,215 +926,3526 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "comments"
                 ],
-                "summary": "Register a user",
+                "summary": "Delete a comment",
                 "parameters": [
                     {
-                        "description": "Register User",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.RegisterUser"
-                        }
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataRegisteredUser"
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "mygram-byferdiansyah_comment_utils.ResponseMessage": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "string",
-                    "example": "the error explained here"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "fail"
-                }
+        },
+        "/files/{key}": {
+            "get": {
+                "description": "Serve an uploaded file through the signed link returned with the resource it belongs to",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "image/webp"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get a stored file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiry of the link",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the link",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/images": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all images with authentication user. The images of a private account are only shown to the account and its followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Get all images",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Order by creation time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only images posted by this user",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataGetedImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upload and create a image with authentication user. A JSON body with an image_url is still accepted for images hosted elsewhere",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Create a image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption",
+                        "name": "caption",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "JPEG, PNG, GIF or WebP image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataAddedImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/feed": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the images of the accounts authentication user follows, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Get the home feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataGetedImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Edit a image by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Edit a image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.EditImage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataEditedImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a image by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Delete a image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{imageId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get an image by id with authentication user. The images of a private account are only shown to the account and its followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Get an image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataGetedImageByID"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_image_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{imageId}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the top-level comments on an image, each with the public profile of its author and its number of replies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Get the comments on an image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Order by creation time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataImageComments"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{imageId}/like": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Like an image with authentication user. Liking an image twice changes nothing. The images of a private account can only be liked by the account and its followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "likes"
+                ],
+                "summary": "Like an image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLike"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Take back the like of an image with authentication user. Unliking an image that isn't liked changes nothing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "likes"
+                ],
+                "summary": "Unlike an image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLike"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_like_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/socialmedias": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all social media with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Get all social media",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Order by creation time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataGetedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create and create a social media with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Add a social media",
+                "parameters": [
+                    {
+                        "description": "Add Social Media",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.AddSocialMedia"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataAddedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/socialmedias/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Edit a social media by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Edit a social media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SocialMedia ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Edit Social Media",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.EditSocialMedia"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataEditedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a social media by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Delete a social media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SocialMedia ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/socialmedias/{socialMediaId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a social media by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Get a social media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Social Media ID",
+                        "name": "socialMediaId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataGetedSocialMediaByID"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Edit a user with authentication user. Empty fields are left unchanged. A multipart form can also carry a new avatar, which is stored once the other fields are. Changing the email makes it unverified and emails a verification token to the new one",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Edit a user",
+                "parameters": [
+                    {
+                        "description": "Edit User",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.EditUser"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "JPEG, PNG, GIF or WebP image",
+                        "name": "avatar",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataEditedUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a user with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Generate a TOTP secret and recovery codes for the authentication user. Add the otpauth URI to an authenticator app and confirm a code from it to turn two-factor authentication on. The recovery codes are only shown once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Enroll in two-factor authentication",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataTwoFactorEnrollment"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Turn two-factor authentication on for the authentication user with a code from the authenticator app it was enrolled in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Confirm two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Confirm Two-Factor",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.ConfirmTwoFactor"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageTwoFactor"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Turn two-factor authentication off for the authentication user, who has to enter the password and a code again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Disable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Reauthenticate",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.Reauthenticate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageTwoFactor"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replace every recovery code of the authentication user, who has to enter the password and a code again. The new codes are only shown once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Regenerate the recovery codes",
+                "parameters": [
+                    {
+                        "description": "Reauthenticate",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.Reauthenticate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataRecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the API keys of the authentication user, newest first, including the revoked ones. The keys themselves are never shown again after they are created, only their prefix",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataAPIKeys"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create an API key for the authentication user to call the API from scripts, sent as \"Bearer \u003ckey\u003e\" in the Authorization header. A key can only call the routes one of its scopes (images:read, images:write, comments:read, comments:write) allows, and works until it is revoked or expires_at passes. The key is only shown once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "Create API Key",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.CreateAPIKey"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataCreatedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/api-keys/{apiKeyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke an API key of the authentication user, which stops working right away",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "apiKeyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageAPIKey"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/follow-requests": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the pending follow requests to authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Get follow requests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Order by request time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataFollows"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/follow-requests/{username}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Approve the follow request of a user to authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Approve a follow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username of the follower",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Reject the follow request of a user to authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Reject a follow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username of the follower",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "Authentication a user and retrieve an access token and a refresh token. When the user has two-factor authentication enabled, the data is a utils.LoginChallenge to exchange at /users/login/2fa instead. Every failed login makes the next attempt for the account and from the address wait longer, and too many of them lock it out for a while",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Login a user",
+                "parameters": [
+                    {
+                        "description": "Login User",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.LoginUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login/2fa": {
+            "post": {
+                "description": "Exchange the challenge token returned by /users/login and a code from the authenticator app, or an unused recovery code, for an access token and a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Finish a two-factor login",
+                "parameters": [
+                    {
+                        "description": "Login Two-Factor",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.LoginTwoFactor"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke the refresh token and every token rotated from the same login. When a valid access token is sent as well, it stops working right away instead of when it expires, and an expired or invalid one doesn't stop the logout",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Logout a user",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.RefreshUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageLoggedoutUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Change the password of the authentication user after checking the current one. Every session of the user is logged out and a new one is returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change the password",
+                "parameters": [
+                    {
+                        "description": "Change Password",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.ChangePassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/forgot": {
+            "post": {
+                "description": "Email a single-use password reset token to the user with the email. Resets asked for an email are limited, and the response is the same whether or not the email belongs to an account or was limited",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Forgot Password",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.ForgotPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessagePassword"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/reset": {
+            "post": {
+                "description": "Set a new password with a reset token sent by email. The token can only be used once and every session of the user is logged out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reset the password",
+                "parameters": [
+                    {
+                        "description": "Reset Password",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResetPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessagePassword"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/privacy": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Make the account of authentication user private or public. Making it public approves every pending follow request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Make the account private or public",
+                "parameters": [
+                    {
+                        "description": "Privacy",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.SetPrivacy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a new refresh token. A refresh token can only be used once; reusing it logs the whole session out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Refresh an access token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.RefreshUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "description": "create and create a user. The account starts unverified and a verification token is emailed to it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a user",
+                "parameters": [
+                    {
+                        "description": "Register User",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.RegisterUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataRegisteredUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/verify-email": {
+            "post": {
+                "description": "Mark the email a verification token was sent to as verified. The token stops working once it is used or the email is changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verify an email",
+                "parameters": [
+                    {
+                        "description": "Verify Email",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.VerifyEmail"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageVerification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/verify-email/resend": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Email a new verification token to the authentication user. Only a few are sent every hour",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Resend the verification email",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageVerification"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the public profile of a user by username with authentication user. The email and age of the user are never shown, and the bio and social medias of a private account are only shown to the account and its followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the profile of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataProfile"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_user_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/follow": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Follow a user with authentication user. Following a private account sends a follow request the account has to approve",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Follow a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataFollowState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Unfollow a user, or withdraw the follow request, with authentication user. Unfollowing a user that isn't followed changes nothing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Unfollow a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/followers": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the accepted followers of a user with authentication user. The followers of a private account are only shown to the account and its followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Get the followers of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Order by follow time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataFollows"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/following": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the users a user follows with authentication user. The follows of a private account are only shown to the account and its followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Get the users a user follows",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor or prev_cursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Order by follow time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataFollows"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "helpers.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "helpers.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "mygram-byferdiansyah_admin_utils.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "the error explained here"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "fail"
+                }
+            }
+        },
+        "mygram-byferdiansyah_comment_utils.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "the error explained here"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "fail"
+                }
+            }
+        },
+        "mygram-byferdiansyah_comment_utils.User": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "profile_image_url": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "mygram-byferdiansyah_follow_utils.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "the error explained here"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "fail"
+                }
+            }
+        },
+        "mygram-byferdiansyah_follow_utils.User": {
+            "type": "object",
+            "properties": {
+                "follower_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "following_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "string",
+                    "example": "user-123"
+                },
+                "private": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "mygram-byferdiansyah_image_utils.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "the error explained here"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "fail"
+                }
+            }
+        },
+        "mygram-byferdiansyah_image_utils.User": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "mygram-byferdiansyah_like_utils.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "the error explained here"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "fail"
+                }
+            }
+        },
+        "mygram-byferdiansyah_socialmedia_utils.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "the error explained here"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "fail"
+                }
+            }
+        },
+        "mygram-byferdiansyah_socialmedia_utils.SocialMedia": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "here is the generated created at"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated social media id"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/johndoe"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "here is the generated edited at"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.User"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                }
+            }
+        },
+        "mygram-byferdiansyah_socialmedia_utils.User": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "mygram-byferdiansyah_user_utils.ResponseMessage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "the error explained here"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "fail"
+                }
+            }
+        },
+        "mygram-byferdiansyah_user_utils.SocialMedia": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "socialmedia-123"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Instagram"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.instagram.com/johndoe"
+                }
+            }
+        },
+        "utils.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "the API key id generated here"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "backup script"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "mgk_Ab3dE6gH"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "images:read"
+                    ]
+                }
+            }
+        },
+        "utils.AddComment": {
+            "type": "object",
+            "properties": {
+                "image_id": {
+                    "type": "string",
+                    "example": "image-123"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "A comment"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "comment-123"
+                }
+            }
+        },
+        "utils.AddSocialMedia": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/johndoe"
+                }
+            }
+        },
+        "utils.AddedComment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "depth": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated comment id"
+                },
+                "image_id": {
+                    "type": "string",
+                    "example": "here is the generated image id"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "A comment"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "comment-123"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                }
+            }
+        },
+        "utils.AddedImage": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "type": "string",
+                    "example": "image-123"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.AddedSocialMedia": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "the social media id generated here"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/johndoe"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                }
+            }
+        },
+        "utils.AdminUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "user-123"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "suspended_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "utils.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "user.suspend"
+                },
+                "actor_id": {
+                    "type": "string",
+                    "example": "user-123"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "auditlog-123"
+                },
+                "target_id": {
+                    "type": "string",
+                    "example": "user-456"
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "utils.ChangePassword": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "secret"
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "newsecret"
+                }
+            }
+        },
+        "utils.CommentThread": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer"
+                },
+                "liked_by_me": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.CommentThread"
+                    }
+                },
+                "reply_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.User"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.ConfirmTwoFactor": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "utils.CreateAPIKey": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "backup script"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "images:read"
+                    ]
+                }
+            }
+        },
+        "utils.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "the API key id generated here"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "the API key generated here"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "backup script"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "mgk_Ab3dE6gH"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "images:read"
+                    ]
+                }
+            }
+        },
+        "utils.EditComment": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "A new comment"
+                }
+            }
+        },
+        "utils.EditImage": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "example": "A new caption"
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/new-image.jpg"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "A new title"
+                }
+            }
+        },
+        "utils.EditSocialMedia": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "New Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.newexample.com/johndoe"
+                }
             }
         },
-        "mygram-byferdiansyah_comment_utils.User": {
+        "utils.EditUser": {
             "type": "object",
             "properties": {
+                "bio": {
+                    "type": "string",
+                    "example": "Taking pictures of cats"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "newjohndoe@example.com"
                 },
-                "id": {
-                    "type": "string"
+                "private": {
+                    "type": "boolean",
+                    "example": false
                 },
                 "username": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "newjohndoe"
                 }
             }
         },
-        "mygram-byferdiansyah_image_utils.ResponseMessage": {
+        "utils.EditedComment": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "string",
-                    "example": "the error explained here"
+                "caption": {
+                    "type": "string"
                 },
-                "status": {
-                    "type": "string",
-                    "example": "fail"
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "mygram-byferdiansyah_image_utils.User": {
+        "utils.EditedImage": {
             "type": "object",
             "properties": {
-                "email": {
+                "caption": {
                     "type": "string"
                 },
-                "username": {
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "mygram-byferdiansyah_socialmedia_utils.ResponseMessage": {
+        "utils.EditedSocialMedia": {
             "type": "object",
             "properties": {
-                "data": {
+                "id": {
                     "type": "string",
-                    "example": "the error explained here"
+                    "example": "here is the generated social media id"
                 },
-                "status": {
+                "name": {
                     "type": "string",
-                    "example": "fail"
+                    "example": "New Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.newexample.com/johndoe"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "the edited at generated here"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
                 }
             }
         },
-        "mygram-byferdiansyah_socialmedia_utils.User": {
+        "utils.EditedUser": {
             "type": "object",
             "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "bio": {
+                    "type": "string",
+                    "example": "Taking pictures of cats"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
                 "email": {
                     "type": "string",
-                    "example": "johndoe@example.com"
+                    "example": "newjohndoe@example.com"
+                },
+                "email_verified": {
+                    "type": "boolean",
+                    "example": false
                 },
                 "id": {
                     "type": "string",
                     "example": "here is the generated user id"
                 },
-                "username": {
+                "private": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "profile_image_url": {
                     "type": "string",
-                    "example": "johndoe"
-                }
-            }
-        },
-        "mygram-byferdiansyah_user_utils.ResponseMessage": {
-            "type": "object",
-            "properties": {
-                "data": {
+                    "example": "the signed link of the avatar"
+                },
+                "updated_at": {
                     "type": "string",
-                    "example": "the error explained here"
+                    "example": "the edited at generated here"
                 },
-                "status": {
+                "username": {
                     "type": "string",
-                    "example": "fail"
+                    "example": "newjohndoe"
                 }
             }
         },
-        "utils.AddComment": {
+        "utils.Follow": {
             "type": "object",
             "properties": {
-                "image_id": {
+                "created_at": {
                     "type": "string",
-                    "example": "image-123"
+                    "example": "the created at generated here"
                 },
-                "message": {
+                "status": {
                     "type": "string",
-                    "example": "A comment"
+                    "example": "accepted"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-byferdiansyah_follow_utils.User"
                 }
             }
         },
-        "utils.AddImage": {
+        "utils.FollowState": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string",
-                    "example": "A caption"
-                },
-                "image_url": {
-                    "type": "string",
-                    "example": "https://www.example.com/image.jpg"
-                },
-                "title": {
+                "status": {
                     "type": "string",
-                    "example": "A Title"
+                    "example": "pending"
                 }
             }
         },
-        "utils.AddSocialMedia": {
+        "utils.ForgotPassword": {
             "type": "object",
+            "required": [
+                "email"
+            ],
             "properties": {
-                "name": {
-                    "type": "string",
-                    "example": "Example"
-                },
-                "social_media_url": {
+                "email": {
                     "type": "string",
-                    "example": "https://www.example.com/johndoe"
+                    "example": "johndoe@example.com"
                 }
             }
         },
-        "utils.AddedComment": {
+        "utils.GetedComment": {
             "type": "object",
             "properties": {
                 "created_at": {
-                    "type": "string",
-                    "example": "the created at generated here"
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
                 },
                 "id": {
-                    "type": "string",
-                    "example": "here is the generated comment id"
+                    "type": "string"
+                },
+                "image": {
+                    "$ref": "#/definitions/utils.Image"
                 },
                 "image_id": {
-                    "type": "string",
-                    "example": "here is the generated image id"
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer"
                 },
                 "message": {
-                    "type": "string",
-                    "example": "A comment"
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "reply_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.User"
                 },
                 "user_id": {
-                    "type": "string",
-                    "example": "here is the generated user id"
+                    "type": "string"
                 }
             }
         },
-        "utils.AddedImage": {
+        "utils.GetedImage": {
             "type": "object",
             "properties": {
+                "blur_hash": {
+                    "type": "string",
+                    "example": "LKO2?U%2Tw=w]~RBVZRi};RPxuwH"
+                },
                 "caption": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "height": {
+                    "type": "integer",
+                    "example": 720
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "liked_by_me": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-byferdiansyah_image_utils.User"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1080
+                }
+            }
+        },
+        "utils.Image": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -1021,155 +4460,259 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.AddedSocialMedia": {
+        "utils.ImageComment": {
             "type": "object",
             "properties": {
                 "created_at": {
-                    "type": "string",
-                    "example": "the created at generated here"
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
                 },
                 "id": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer"
+                },
+                "liked_by_me": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "reply_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.User"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.ImageDuplicate": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "duplicate_id": {
                     "type": "string",
-                    "example": "the social media id generated here"
+                    "example": "image-456"
                 },
-                "name": {
+                "duplicate_user_id": {
                     "type": "string",
-                    "example": "Example"
+                    "example": "user-456"
                 },
-                "social_media_url": {
+                "image_id": {
                     "type": "string",
-                    "example": "https://www.example.com/johndoe"
+                    "example": "image-123"
                 },
                 "user_id": {
                     "type": "string",
-                    "example": "here is the generated user id"
+                    "example": "user-123"
+                }
+            }
+        },
+        "utils.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/helpers.JWK"
+                    }
                 }
             }
         },
-        "utils.EditComment": {
+        "utils.Like": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "A new comment"
+                "like_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "liked": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "utils.EditImage": {
+        "utils.LoggedinUser": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string",
-                    "example": "A new caption"
+                "expires_in": {
+                    "type": "integer",
+                    "example": 900
                 },
-                "image_url": {
+                "refresh_token": {
                     "type": "string",
-                    "example": "https://www.example.com/new-image.jpg"
+                    "example": "the refresh token generated here"
                 },
-                "title": {
+                "token": {
                     "type": "string",
-                    "example": "A new title"
+                    "example": "the access token generated here"
                 }
             }
         },
-        "utils.EditSocialMedia": {
+        "utils.LoginTwoFactor": {
             "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
             "properties": {
-                "name": {
+                "challenge_token": {
                     "type": "string",
-                    "example": "New Example"
+                    "example": "the challenge token generated here"
                 },
-                "social_media_url": {
+                "code": {
                     "type": "string",
-                    "example": "https://www.newexample.com/johndoe"
+                    "example": "123456"
                 }
             }
         },
-        "utils.EditUser": {
+        "utils.LoginUser": {
             "type": "object",
             "properties": {
                 "email": {
                     "type": "string",
-                    "example": "newjohndoe@example.com"
+                    "example": "johndoe@example.com"
                 },
-                "username": {
+                "password": {
                     "type": "string",
-                    "example": "newjohndoe"
+                    "example": "secret"
                 }
             }
         },
-        "utils.EditedComment": {
+        "utils.Profile": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "bio": {
+                    "type": "string",
+                    "example": "Taking pictures of cats"
                 },
-                "image_url": {
-                    "type": "string"
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
                 },
-                "title": {
-                    "type": "string"
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
                 },
-                "updated_at": {
-                    "type": "string"
+                "follower_count": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "utils.EditedImage": {
-            "type": "object",
-            "properties": {
-                "caption": {
-                    "type": "string"
+                "following_count": {
+                    "type": "integer",
+                    "example": 1
                 },
                 "id": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "user-123"
                 },
-                "image_url": {
-                    "type": "string"
+                "image_count": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "title": {
-                    "type": "string"
+                "private": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "updated_at": {
-                    "type": "string"
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "the signed link of the avatar"
                 },
-                "user_id": {
-                    "type": "string"
+                "social_medias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mygram-byferdiansyah_user_utils.SocialMedia"
+                    }
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
                 }
             }
         },
-        "utils.EditedSocialMedia": {
+        "utils.Reauthenticate": {
             "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
             "properties": {
-                "id": {
+                "code": {
                     "type": "string",
-                    "example": "here is the generated social media id"
+                    "example": "123456"
                 },
-                "name": {
+                "password": {
                     "type": "string",
-                    "example": "New Example"
+                    "example": "secret"
+                }
+            }
+        },
+        "utils.RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k3m9p-x7q2r"
+                    ]
+                }
+            }
+        },
+        "utils.RefreshUser": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "the refresh token generated here"
+                }
+            }
+        },
+        "utils.RegisterUser": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 8
                 },
-                "social_media_url": {
+                "email": {
                     "type": "string",
-                    "example": "https://www.newexample.com/johndoe"
+                    "example": "johndoe@example.com"
                 },
-                "updated_at": {
+                "password": {
                     "type": "string",
-                    "example": "the edited at generated here"
+                    "example": "secret"
                 },
-                "user_id": {
+                "username": {
                     "type": "string",
-                    "example": "here is the generated user id"
+                    "example": "johndoe"
                 }
             }
         },
-        "utils.EditedUser": {
+        "utils.RegisteredUser": {
             "type": "object",
             "properties": {
                 "age": {
@@ -1178,169 +4721,214 @@ const docTemplate = `{
                 },
                 "email": {
                     "type": "string",
-                    "example": "newjohndoe@example.com"
+                    "example": "johndoe@example.com"
                 },
                 "id": {
                     "type": "string",
-                    "example": "here is the generated user id"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "the edited at generated here"
+                    "example": "the user id generated here"
                 },
                 "username": {
                     "type": "string",
-                    "example": "newjohndoe"
+                    "example": "johndoe"
                 }
             }
         },
-        "utils.GetedComment": {
+        "utils.ResetPassword": {
             "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "image": {
-                    "$ref": "#/definitions/utils.Image"
-                },
-                "image_id": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/mygram-byferdiansyah_comment_utils.User"
+                "password": {
+                    "type": "string",
+                    "example": "newsecret"
                 },
-                "user_id": {
-                    "type": "string"
+                "token": {
+                    "type": "string",
+                    "example": "the reset token sent by email"
                 }
             }
         },
-        "utils.GetedImage": {
+        "utils.ResponseDataAPIKeys": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "image_url": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.APIKey"
+                    }
                 },
-                "user": {
-                    "$ref": "#/definitions/mygram-byferdiansyah_image_utils.User"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAddedComment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.AddedComment"
                 },
-                "user_id": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
-        "utils.Image": {
+        "utils.ResponseDataAddedImage": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/utils.AddedImage"
                 },
-                "id": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAddedSocialMedia": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.AddedSocialMedia"
                 },
-                "image_url": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAdminUsers": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.AdminUser"
+                    }
                 },
-                "title": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAuditLogs": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.AuditLog"
+                    }
                 },
-                "user_id": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
-        "utils.LoggedinUser": {
+        "utils.ResponseDataCommentThread": {
             "type": "object",
             "properties": {
-                "token": {
+                "data": {
+                    "$ref": "#/definitions/utils.CommentThread"
+                },
+                "status": {
                     "type": "string",
-                    "example": "the token generated here"
+                    "example": "success"
                 }
             }
         },
-        "utils.LoginUser": {
+        "utils.ResponseDataCreatedAPIKey": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "johndoe@example.com"
+                "data": {
+                    "$ref": "#/definitions/utils.CreatedAPIKey"
                 },
-                "password": {
+                "status": {
                     "type": "string",
-                    "example": "secret"
+                    "example": "success"
                 }
             }
         },
-        "utils.RegisterUser": {
+        "utils.ResponseDataEditedComment": {
             "type": "object",
             "properties": {
-                "age": {
-                    "type": "integer",
-                    "example": 8
-                },
-                "email": {
-                    "type": "string",
-                    "example": "johndoe@example.com"
+                "data": {
+                    "$ref": "#/definitions/utils.EditedComment"
                 },
-                "password": {
+                "status": {
                     "type": "string",
-                    "example": "secret"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataEditedImage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.EditedImage"
                 },
-                "username": {
+                "status": {
                     "type": "string",
-                    "example": "johndoe"
+                    "example": "success"
                 }
             }
         },
-        "utils.RegisteredUser": {
+        "utils.ResponseDataEditedSocialMedia": {
             "type": "object",
             "properties": {
-                "age": {
-                    "type": "integer",
-                    "example": 8
+                "data": {
+                    "$ref": "#/definitions/utils.EditedSocialMedia"
                 },
-                "email": {
+                "status": {
                     "type": "string",
-                    "example": "johndoe@example.com"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataEditedUser": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.EditedUser"
                 },
-                "id": {
+                "status": {
                     "type": "string",
-                    "example": "the user id generated here"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataFollowState": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.FollowState"
                 },
-                "username": {
+                "status": {
                     "type": "string",
-                    "example": "johndoe"
+                    "example": "success"
                 }
             }
         },
-        "utils.ResponseDataAddedComment": {
+        "utils.ResponseDataFollows": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.AddedComment"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.Follow"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
                 },
                 "status": {
                     "type": "string",
@@ -1348,11 +4936,20 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataAddedImage": {
+        "utils.ResponseDataGetedComment": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.AddedImage"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.GetedComment"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
                 },
                 "status": {
                     "type": "string",
@@ -1360,11 +4957,11 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataAddedSocialMedia": {
+        "utils.ResponseDataGetedCommentByID": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.AddedSocialMedia"
+                    "$ref": "#/definitions/utils.GetedComment"
                 },
                 "status": {
                     "type": "string",
@@ -1372,11 +4969,20 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataEditedComment": {
+        "utils.ResponseDataGetedImage": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.EditedComment"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.GetedImage"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
                 },
                 "status": {
                     "type": "string",
@@ -1384,11 +4990,11 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataEditedImage": {
+        "utils.ResponseDataGetedImageByID": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.EditedImage"
+                    "$ref": "#/definitions/utils.GetedImage"
                 },
                 "status": {
                     "type": "string",
@@ -1396,11 +5002,17 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataEditedSocialMedia": {
+        "utils.ResponseDataGetedSocialMedia": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.EditedSocialMedia"
+                    "$ref": "#/definitions/utils.SocialMedias"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
                 },
                 "status": {
                     "type": "string",
@@ -1408,11 +5020,11 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataEditedUser": {
+        "utils.ResponseDataGetedSocialMediaByID": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.EditedUser"
+                    "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.SocialMedia"
                 },
                 "status": {
                     "type": "string",
@@ -1420,28 +5032,34 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataGetedComment": {
+        "utils.ResponseDataImageComments": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/utils.GetedComment"
+                        "$ref": "#/definitions/utils.ImageComment"
                     }
                 },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string",
                     "example": "success"
                 }
             }
         },
-        "utils.ResponseDataGetedImage": {
+        "utils.ResponseDataImageDuplicates": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/utils.GetedImage"
+                        "$ref": "#/definitions/utils.ImageDuplicate"
                     }
                 },
                 "status": {
@@ -1450,11 +5068,11 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataGetedSocialMedia": {
+        "utils.ResponseDataLike": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/utils.SocialMedias"
+                    "$ref": "#/definitions/utils.Like"
                 },
                 "status": {
                     "type": "string",
@@ -1474,6 +5092,30 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.ResponseDataProfile": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.Profile"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataRecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.RecoveryCodes"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
         "utils.ResponseDataRegisteredUser": {
             "type": "object",
             "properties": {
@@ -1486,6 +5128,31 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.ResponseDataTwoFactorEnrollment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.TwoFactorEnrollment"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseMessageAPIKey": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "the API key has been successfully revoked"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
         "utils.ResponseMessageDeletedComment": {
             "type": "object",
             "properties": {
@@ -1538,35 +5205,92 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.SocialMedia": {
+        "utils.ResponseMessageLoggedoutUser": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "message": {
                     "type": "string",
-                    "example": "here is the generated created at"
+                    "example": "you have been successfully logged out"
                 },
-                "id": {
+                "status": {
                     "type": "string",
-                    "example": "here is the generated social media id"
-                },
-                "name": {
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseMessageModerated": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string",
-                    "example": "Example"
+                    "example": "the action has been recorded"
                 },
-                "social_media_url": {
+                "status": {
                     "type": "string",
-                    "example": "https://www.example.com/johndoe"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseMessagePassword": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "your password has been successfully reset"
                 },
-                "updated_at": {
+                "status": {
                     "type": "string",
-                    "example": "here is the generated edited at"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseMessageTwoFactor": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "two-factor authentication has been successfully enabled"
                 },
-                "user": {
-                    "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.User"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseMessageVerification": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "your email has been successfully verified"
                 },
-                "user_id": {
+                "status": {
                     "type": "string",
-                    "example": "here is the generated user id"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.SetPrivacy": {
+            "type": "object",
+            "required": [
+                "private"
+            ],
+            "properties": {
+                "private": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "utils.SetRole": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "moderator"
                 }
             }
         },
@@ -1576,15 +5300,49 @@ const docTemplate = `{
                 "social_medias": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/utils.SocialMedia"
+                        "$ref": "#/definitions/mygram-byferdiansyah_socialmedia_utils.SocialMedia"
                     }
                 }
             }
+        },
+        "utils.TwoFactorEnrollment": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/MyGram:johndoe@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=MyGram"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k3m9p-x7q2r"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "utils.VerifyEmail": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "the verification token sent by email"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "Bearer": {
-            "description": "Description for what is this security definition being used",
+            "description": "Type \"Bearer\" followed by an access token, or by an API key on the routes its scopes allow",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
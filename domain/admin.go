@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	AuditActionDeleteImage   = "image.delete"
+	AuditActionDeleteComment = "comment.delete"
+	AuditActionSuspendUser   = "user.suspend"
+	AuditActionRestoreUser   = "user.restore"
+	AuditActionSetRole       = "user.role"
+)
+
+var (
+	ErrInvalidRole    = errors.New("the role must be one of user, moderator or admin")
+	ErrSelfModeration = errors.New("you cannot suspend or change the role of your own account")
+)
+
+// AuditLog records a moderation or administration action. It has no foreign
+// keys so the record survives the deletion of the actor or the target.
+type AuditLog struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	ActorID    string     `gorm:"type:VARCHAR(50);not null;index" json:"actor_id"`
+	Action     string     `gorm:"type:VARCHAR(50);not null" json:"action"`
+	TargetType string     `gorm:"type:VARCHAR(50);not null" json:"target_type"`
+	TargetID   string     `gorm:"type:VARCHAR(50);not null;index" json:"target_id"`
+	Detail     string     `json:"detail,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime;index" json:"created_at,omitempty"`
+}
+
+type AdminUseCase interface {
+	DeleteImage(context.Context, string) error
+	DeleteComment(context.Context, string) error
+	GetDuplicateImages(context.Context, *[]ImageDuplicate, int) error
+	GetUsers(context.Context, *[]User) error
+	SuspendUser(context.Context, string) error
+	RestoreUser(context.Context, string) error
+	SetRole(context.Context, string, string) error
+	GetAuditLogs(context.Context, *[]AuditLog) error
+}
+
+type AuditLogRepository interface {
+	Create(context.Context, *AuditLog) error
+	Get(context.Context, *[]AuditLog) error
+}
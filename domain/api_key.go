@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, which tells them apart from access
+// tokens in the Authorization header.
+const APIKeyPrefix = "mgk_"
+
+// The scopes an API key can be granted. A key can only call the routes that
+// require one of its scopes, while signed in users can call every route.
+const (
+	ScopeImagesRead    = "images:read"
+	ScopeImagesWrite   = "images:write"
+	ScopeCommentsRead  = "comments:read"
+	ScopeCommentsWrite = "comments:write"
+)
+
+var Scopes = []string{ScopeImagesRead, ScopeImagesWrite, ScopeCommentsRead, ScopeCommentsWrite}
+
+var (
+	ErrAPIKeyInvalid   = errors.New("the API key is invalid, expired or revoked")
+	ErrAPIKeyScopes    = errors.New("the API key must have at least one scope")
+	ErrAPIKeyExpiry    = errors.New("the API key must expire in the future")
+	ErrUnknownScope    = errors.New("the scope is unknown")
+	ErrAPIKeyForbidden = errors.New("API keys can't be used here, sign in to proceed")
+)
+
+// APIKey lets a script act as a user on the routes its scopes allow. Only
+// the SHA-256 hash of the key is stored; Key holds the plain value right
+// after it has been created, and Prefix the start of it so users can tell
+// their keys apart. A key without ExpiresAt works until it is revoked.
+type APIKey struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	Name       string     `gorm:"type:VARCHAR(50);not null" json:"name"`
+	Prefix     string     `gorm:"type:VARCHAR(12);not null" json:"prefix"`
+	KeyHash    string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	Key        string     `gorm:"-" json:"-"`
+	Scopes     []string   `gorm:"type:VARCHAR(200);serializer:json;not null" json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type APIKeyUseCase interface {
+	Create(context.Context, *APIKey) error
+	GetByUser(context.Context, *[]APIKey, string) error
+	Revoke(context.Context, string, string) error
+	Authenticate(context.Context, *APIKey, string) error
+}
+
+type APIKeyRepository interface {
+	Create(context.Context, *APIKey) error
+	GetByUser(context.Context, *[]APIKey, string) error
+	GetByHash(context.Context, *APIKey, string) error
+	Revoke(context.Context, string, string) error
+	Use(context.Context, string, time.Time) error
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrBlobNotFound = errors.New("the file doesn't exist")
+
+// FileURLPath is the path a stored file is served from, before it is signed.
+func FileURLPath(key string) string {
+	return "/files/" + key
+}
+
+// FileKey returns the key of the stored file url points at, or false when
+// url doesn't point at a stored file.
+func FileKey(url string) (string, bool) {
+	if !strings.HasPrefix(url, FileURLPath("")) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(url, FileURLPath("")), true
+}
+
+// BlobStore keeps uploaded files under a slash separated key such as
+// "images/user-123/abc.jpg".
+type BlobStore interface {
+	Put(context.Context, string, io.Reader, int64, string) error
+	Get(context.Context, string) (io.ReadCloser, error)
+	Delete(context.Context, string) error
+}
@@ -2,21 +2,47 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"mygram-byferdiansyah/pagination"
 	"time"
 
 	"github.com/asaskevich/govalidator"
 	"gorm.io/gorm"
 )
 
+// DeletedCommentMessage replaces the message of a deleted comment that still
+// has replies.
+const DeletedCommentMessage = "[deleted]"
+
+var (
+	ErrCommentTooDeep    = errors.New("replies can't be nested any deeper")
+	ErrCommentDeleted    = errors.New("the comment has been deleted")
+	ErrReplyOnOtherImage = errors.New("a reply must be on the same image as the comment it replies to")
+)
+
+// Comment is a comment on an image, or a reply to another comment on it. A
+// deleted comment that still has replies is kept as a tombstone, which loses
+// its author once the account is deleted.
 type Comment struct {
-	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	UserID    string     `gorm:"type:VARCHAR(50);not null" json:"user_id"`
-	ImageID   string     `gorm:"type:VARCHAR(50);not null" form:"image_id" json:"image_id"`
-	Message   string     `gorm:"not null" valid:"required" form:"message" json:"message" example:"i am so betifull"`
-	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
-	User      *User      `gorm:"foreignKey:UserID;constraint:opEdit:CASCADE,onDelete:CASCADE" json:"user"`
-	Image     *Image     `gorm:"foreignKey:ImageID;constraint:opEdit:CASCADE,onDelete:CASCADE" json:"image"`
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50)" json:"user_id"`
+	ImageID    string     `gorm:"type:VARCHAR(50);not null" form:"image_id" json:"image_id"`
+	Message    string     `gorm:"not null" valid:"required" form:"message" json:"message" example:"i am so betifull"`
+	ParentID   *string    `gorm:"type:VARCHAR(50);index" form:"parent_id" json:"parent_id,omitempty"`
+	Depth      int        `gorm:"not null;default:0" json:"depth"`
+	ReplyCount int        `gorm:"not null;default:0" json:"reply_count"`
+	LikeCount  int        `gorm:"not null;default:0" json:"like_count"`
+	LikedByMe  bool       `gorm:"-" json:"liked_by_me"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:opEdit:CASCADE,onDelete:CASCADE" json:"user"`
+	Image      *Image     `gorm:"foreignKey:ImageID;constraint:opEdit:CASCADE,onDelete:CASCADE" json:"image"`
+	Parent     *Comment   `gorm:"foreignKey:ParentID;constraint:onDelete:NO ACTION" json:"-"`
+}
+
+func (c *Comment) OwnerID() string {
+	return c.UserID
 }
 
 func (c *Comment) BeforeCreate(db *gorm.DB) (err error) {
@@ -35,7 +61,9 @@ func (c *Comment) BeforeEdit(db *gorm.DB) (err error) {
 }
 
 type CommentUseCase interface {
-	Get(context.Context, *[]Comment, string) error
+	Get(context.Context, *[]Comment, string, *pagination.Page) error
+	GetByImage(context.Context, *[]Comment, string, *pagination.Page) error
+	GetThread(context.Context, *[]Comment, string) error
 	Create(context.Context, *Comment) error
 	GetByID(context.Context, *Comment, string) error
 	Edit(context.Context, Comment, string) (Image, error)
@@ -43,7 +71,9 @@ type CommentUseCase interface {
 }
 
 type CommentRepository interface {
-	Get(context.Context, *[]Comment, string) error
+	Get(context.Context, *[]Comment, string, *pagination.Page) error
+	GetByImage(context.Context, *[]Comment, string, *pagination.Page) error
+	GetThread(context.Context, *[]Comment, string) error
 	Create(context.Context, *Comment) error
 	GetByID(context.Context, *Comment, string) error
 	Edit(context.Context, Comment, string) (Image, error)
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrEmailVerificationInvalid = errors.New("the verification token is invalid or expired")
+	ErrEmailAlreadyVerified     = errors.New("your email has already been verified")
+	ErrVerificationRateLimited  = errors.New("too many verification emails have been sent, try again later")
+	ErrEmailNotVerified         = errors.New("verify your email to proceed")
+)
+
+// EmailVerification proves that its user received the email sent to Email.
+// Only the SHA-256 hash of the token is stored, and the token stops working
+// once it is used or the user changes the email.
+type EmailVerification struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index:idx_email_verifications_user_created" json:"user_id"`
+	Email     string     `gorm:"type:VARCHAR(50);not null" json:"email"`
+	TokenHash string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	Token     string     `gorm:"-" json:"-"`
+	ExpiresAt *time.Time `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime;index:idx_email_verifications_user_created" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type EmailVerificationUseCase interface {
+	Send(context.Context, string) error
+	Verify(context.Context, string) error
+}
+
+type EmailVerificationRepository interface {
+	Create(context.Context, *EmailVerification) error
+	GetByHash(context.Context, *EmailVerification, string) error
+	CountSince(context.Context, string, time.Time) (int64, error)
+	Use(context.Context, EmailVerification) error
+}
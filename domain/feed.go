@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"mygram-byferdiansyah/pagination"
+	"time"
+)
+
+const (
+	FeedStrategyRead  = "read"
+	FeedStrategyWrite = "write"
+)
+
+// FeedItem is an image in the precomputed home feed of a user. Only the
+// fan-out-on-write feed store keeps them. CreatedAt is copied from the image
+// so the feed can be paged without joining the images.
+type FeedItem struct {
+	UserID    string     `gorm:"primaryKey;type:VARCHAR(50);index:idx_feed_items_user_created,priority:1"`
+	ImageID   string     `gorm:"primaryKey;type:VARCHAR(50);index:idx_feed_items_user_created,priority:3"`
+	CreatedAt *time.Time `gorm:"not null;index:idx_feed_items_user_created,priority:2"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onDelete:CASCADE"`
+	Image     *Image     `gorm:"foreignKey:ImageID;constraint:onDelete:CASCADE"`
+}
+
+// FeedStore builds the home feed of a user out of the images of the
+// accounts the user follows, newest first. A store that answers Get with a
+// query ignores the rest, while a store that precomputes the feed keeps it
+// up to date through them.
+type FeedStore interface {
+	Get(context.Context, *[]Image, string, *pagination.Page) error
+	Add(context.Context, *Image) error
+	Follow(context.Context, string, string) error
+	Unfollow(context.Context, string, string) error
+}
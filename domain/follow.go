@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/pagination"
+	"time"
+)
+
+const (
+	FollowStatusPending  = "pending"
+	FollowStatusAccepted = "accepted"
+)
+
+var (
+	ErrSelfFollow     = errors.New("you can't follow yourself")
+	ErrPrivateAccount = errors.New("this account is private, follow it to see who it follows and who follows it")
+)
+
+// Follow is a user following another. Following a private account starts
+// out pending until the followee accepts it. Only accepted follows count
+// towards FollowerCount and FollowingCount of the users.
+type Follow struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	FollowerID string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_follows_follower_followee;check:chk_follows_not_self,follower_id <> followee_id" json:"follower_id"`
+	FolloweeID string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_follows_follower_followee;index:idx_follows_followee_status" json:"followee_id"`
+	Status     string     `gorm:"type:VARCHAR(20);not null;index:idx_follows_followee_status" json:"status"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	Follower   *User      `gorm:"foreignKey:FollowerID;constraint:onDelete:CASCADE" json:"-"`
+	Followee   *User      `gorm:"foreignKey:FolloweeID;constraint:onDelete:CASCADE" json:"-"`
+}
+
+type FollowUseCase interface {
+	Follow(context.Context, string, string) (string, error)
+	Unfollow(context.Context, string, string) error
+	GetFollowers(context.Context, *[]Follow, string, string, *pagination.Page) error
+	GetFollowing(context.Context, *[]Follow, string, string, *pagination.Page) error
+	GetRequests(context.Context, *[]Follow, string, *pagination.Page) error
+	Approve(context.Context, string, string) error
+	Reject(context.Context, string, string) error
+	SetPrivate(context.Context, string, bool) error
+}
+
+type FollowRepository interface {
+	Create(context.Context, *Follow) error
+	GetByUsers(context.Context, *Follow, string, string) error
+	GetFollowers(context.Context, *[]Follow, string, *pagination.Page) error
+	GetFollowing(context.Context, *[]Follow, string, *pagination.Page) error
+	GetRequests(context.Context, *[]Follow, string, *pagination.Page) error
+	Accept(context.Context, string, string) error
+	Delete(context.Context, string, string) error
+	SetPrivate(context.Context, string, bool) ([]string, error)
+}
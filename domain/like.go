@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	LikeTargetImage   = "image"
+	LikeTargetComment = "comment"
+)
+
+var ErrInvalidLikeTarget = errors.New("only images and comments can be liked")
+
+// Like is a user liking an image or a comment. Exactly one of ImageID and
+// CommentID is set, so the like goes away with its target.
+type Like struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_likes_user_image;uniqueIndex:idx_likes_user_comment" json:"user_id"`
+	ImageID   *string    `gorm:"type:VARCHAR(50);uniqueIndex:idx_likes_user_image" json:"image_id,omitempty"`
+	CommentID *string    `gorm:"type:VARCHAR(50);uniqueIndex:idx_likes_user_comment" json:"comment_id,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onDelete:CASCADE" json:"-"`
+	Image     *Image     `gorm:"foreignKey:ImageID;constraint:onDelete:CASCADE" json:"-"`
+	Comment   *Comment   `gorm:"foreignKey:CommentID;constraint:onDelete:CASCADE" json:"-"`
+}
+
+// LikeUseCase likes and unlikes images and comments, addressed by a
+// LikeTarget constant and an id. Like and Unlike are idempotent and return
+// the like count of the target.
+type LikeUseCase interface {
+	Like(context.Context, string, string, string) (int, error)
+	Unlike(context.Context, string, string, string) (int, error)
+	GetLiked(context.Context, string, string, []string) (map[string]bool, error)
+}
+
+type LikeRepository interface {
+	Like(context.Context, string, string, string) (int, error)
+	Unlike(context.Context, string, string, string) (int, error)
+	GetLiked(context.Context, string, string, []string) (map[string]bool, error)
+}
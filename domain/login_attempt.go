@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCredentials is the only error a login with an unknown email or a
+// wrong password fails with, so the response doesn't tell which accounts
+// exist.
+var ErrInvalidCredentials = errors.New("the email or password you entered is wrong")
+
+// LoginLockedError is returned while too many failed logins for the account
+// or from the address block signing in. RetryAfter is how long until the
+// next attempt is let through.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+// LoginAttempt counts the failed logins for an account or a client address,
+// told apart by the prefix of Key. The count starts over once the last
+// failure is older than the lockout.
+type LoginAttempt struct {
+	Key          string     `gorm:"primaryKey;type:VARCHAR(100)" json:"key"`
+	Failures     int        `gorm:"not null" json:"failures"`
+	LastFailedAt *time.Time `gorm:"not null;index" json:"last_failed_at"`
+}
+
+// loginBackoff is how long a client waits after the first failed login.
+// The wait doubles with every further failure until the limit is reached.
+const loginBackoff = time.Second
+
+// LoginAttemptPolicy is how failed logins hold off the next attempt. Every
+// failure doubles the wait, and MaxAttempts of them in a row lock out for
+// Lockout.
+type LoginAttemptPolicy struct {
+	MaxAttempts int
+	Lockout     time.Duration
+}
+
+// Reserve counts an attempt in loginAttempt ahead of checking the password,
+// unless the failures counted so far still hold it off. It returns how long
+// until the attempt would be let through, leaving loginAttempt as it was,
+// or 0 once it is counted.
+func (policy LoginAttemptPolicy) Reserve(loginAttempt *LoginAttempt, now time.Time) time.Duration {
+	if loginAttempt.LastFailedAt != nil && loginAttempt.LastFailedAt.Add(policy.Lockout).Before(now) {
+		loginAttempt.Failures = 0
+	}
+
+	if loginAttempt.Failures > 0 {
+		if retryAfter := loginAttempt.LastFailedAt.Add(policy.Wait(loginAttempt.Failures)).Sub(now); retryAfter > 0 {
+			return retryAfter
+		}
+	}
+
+	loginAttempt.Failures++
+	loginAttempt.LastFailedAt = &now
+
+	return 0
+}
+
+// Wait returns how long to wait after the last of failures failed logins.
+func (policy LoginAttemptPolicy) Wait(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	if failures >= policy.MaxAttempts || failures > 30 {
+		return policy.Lockout
+	}
+
+	if wait := loginBackoff << (failures - 1); wait < policy.Lockout {
+		return wait
+	}
+
+	return policy.Lockout
+}
+
+type LoginAttemptUseCase interface {
+	Login(context.Context, *User, string) error
+}
+
+// LoginAttemptRepository counts login attempts by key. Reserve counts one
+// atomically with checking the ones before it, so parallel attempts can't
+// all get in under the limit, and Release takes back one that turned out to
+// succeed.
+type LoginAttemptRepository interface {
+	Reserve(context.Context, string, LoginAttemptPolicy) (time.Duration, error)
+	Release(context.Context, string) error
+	Reset(context.Context, string) error
+}
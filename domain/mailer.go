@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+// Email is a plain text email to a single recipient.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(context.Context, Email) error
+}
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 	domain "mygram-byferdiansyah/domain"
+	pagination "mygram-byferdiansyah/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -28,13 +29,27 @@ func (_m *CommentRepository) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentRepository) Get(_a0 context.Context, _a1 *[]domain.Comment, _a2 string) error {
-	ret := _m.Called(_a0, _a1, _a2)
+// Get provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentRepository) Get(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 *pagination.Page) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string) error); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, *pagination.Page) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByImage provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentRepository) GetByImage(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 *pagination.Page) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, *pagination.Page) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -56,6 +71,20 @@ func (_m *CommentRepository) GetByID(_a0 context.Context, _a1 *domain.Comment, _
 	return r0
 }
 
+// GetThread provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CommentRepository) GetThread(_a0 context.Context, _a1 *[]domain.Comment, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: _a0, _a1
 func (_m *CommentRepository) Create(_a0 context.Context, _a1 *domain.Comment) error {
 	ret := _m.Called(_a0, _a1)
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 	domain "mygram-byferdiansyah/domain"
+	pagination "mygram-byferdiansyah/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -14,12 +15,12 @@ type ImageRepository struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: _a0, _a1
-func (_m *ImageRepository) Delete(_a0 context.Context, _a1 string) error {
+// Create provides a mock function with given fields: _a0, _a1
+func (_m *ImageRepository) Create(_a0 context.Context, _a1 *domain.Image) error {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image) error); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
@@ -28,12 +29,12 @@ func (_m *ImageRepository) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: _a0, _a1
-func (_m *ImageRepository) Get(_a0 context.Context, _a1 *[]domain.Image) error {
+// Delete provides a mock function with given fields: _a0, _a1
+func (_m *ImageRepository) Delete(_a0 context.Context, _a1 string) error {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Image) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
@@ -42,12 +43,33 @@ func (_m *ImageRepository) Get(_a0 context.Context, _a1 *[]domain.Image) error {
 	return r0
 }
 
-// GetByID provides a mock function with given fields: _a0, _a1, _a2
-func (_m *ImageRepository) GetByID(_a0 context.Context, _a1 *domain.Image, _a2 string) error {
+// Edit provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageRepository) Edit(_a0 context.Context, _a1 domain.Image, _a2 string) (domain.Image, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.Image
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Image, string) domain.Image); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.Image)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Image, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageRepository) Get(_a0 context.Context, _a1 *[]domain.Image, _a2 *pagination.Page) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image, string) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Image, *pagination.Page) error); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
@@ -56,13 +78,13 @@ func (_m *ImageRepository) GetByID(_a0 context.Context, _a1 *domain.Image, _a2 s
 	return r0
 }
 
-// Create provides a mock function with given fields: _a0, _a1
-func (_m *ImageRepository) Create(_a0 context.Context, _a1 *domain.Image) error {
-	ret := _m.Called(_a0, _a1)
+// GetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageRepository) GetByID(_a0 context.Context, _a1 *domain.Image, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,19 +92,19 @@ func (_m *ImageRepository) Create(_a0 context.Context, _a1 *domain.Image) error
 	return r0
 }
 
-// Edit provides a mock function with given fields: _a0, _a1, _a2
-func (_m *ImageRepository) Edit(_a0 context.Context, _a1 domain.Image, _a2 string) (domain.Image, error) {
+// GetIDByContentHash provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageRepository) GetIDByContentHash(_a0 context.Context, _a1 string, _a2 string) (string, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 domain.Image
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Image, string) domain.Image); ok {
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(domain.Image)
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Image, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -91,6 +113,48 @@ func (_m *ImageRepository) Edit(_a0 context.Context, _a1 domain.Image, _a2 strin
 	return r0, r1
 }
 
+// GetNearDuplicates provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageRepository) GetNearDuplicates(_a0 context.Context, _a1 *[]domain.ImageDuplicate, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.ImageDuplicate, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUnprocessed provides a mock function with given fields: _a0, _a1
+func (_m *ImageRepository) GetUnprocessed(_a0 context.Context, _a1 *[]domain.Image) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Image) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveVariants provides a mock function with given fields: _a0, _a1
+func (_m *ImageRepository) SaveVariants(_a0 context.Context, _a1 *domain.Image) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewImageRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -4,7 +4,9 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	domain "mygram-byferdiansyah/domain"
+	pagination "mygram-byferdiansyah/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -14,6 +16,20 @@ type ImageUseCase struct {
 	mock.Mock
 }
 
+// Create provides a mock function with given fields: _a0, _a1
+func (_m *ImageUseCase) Create(_a0 context.Context, _a1 *domain.Image) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: _a0, _a1
 func (_m *ImageUseCase) Delete(_a0 context.Context, _a1 string) error {
 	ret := _m.Called(_a0, _a1)
@@ -28,13 +44,34 @@ func (_m *ImageUseCase) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: _a0, _a1
-func (_m *ImageUseCase) Get(_a0 context.Context, _a1 *[]domain.Image) error {
-	ret := _m.Called(_a0, _a1)
+// Edit provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageUseCase) Edit(_a0 context.Context, _a1 domain.Image, _a2 string) (domain.Image, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.Image
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Image, string) domain.Image); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.Image)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Image, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageUseCase) Get(_a0 context.Context, _a1 *[]domain.Image, _a2 *pagination.Page) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Image) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Image, *pagination.Page) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -56,13 +93,13 @@ func (_m *ImageUseCase) GetByID(_a0 context.Context, _a1 *domain.Image, _a2 stri
 	return r0
 }
 
-// Create provides a mock function with given fields: _a0, _a1
-func (_m *ImageUseCase) Create(_a0 context.Context, _a1 *domain.Image) error {
-	ret := _m.Called(_a0, _a1)
+// GetFeed provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ImageUseCase) GetFeed(_a0 context.Context, _a1 *[]domain.Image, _a2 string, _a3 *pagination.Page) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Image, string, *pagination.Page) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,25 +107,32 @@ func (_m *ImageUseCase) Create(_a0 context.Context, _a1 *domain.Image) error {
 	return r0
 }
 
-// Edit provides a mock function with given fields: _a0, _a1, _a2
-func (_m *ImageUseCase) Edit(_a0 context.Context, _a1 domain.Image, _a2 string) (domain.Image, error) {
+// GetNearDuplicates provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageUseCase) GetNearDuplicates(_a0 context.Context, _a1 *[]domain.ImageDuplicate, _a2 int) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 domain.Image
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Image, string) domain.Image); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.ImageDuplicate, int) error); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(domain.Image)
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Image, string) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	return r0
+}
+
+// Upload provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ImageUseCase) Upload(_a0 context.Context, _a1 *domain.Image, _a2 io.Reader) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Image, io.Reader) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
 type mockConstructorTestingTNewImageUseCase interface {
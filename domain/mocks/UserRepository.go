@@ -5,11 +5,12 @@ package mocks
 import (
 	context "context"
 	domain "mygram-byferdiansyah/domain"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 )
 
-
+// UserRepository is an autogenerated mock type for the UserRepository type
 type UserRepository struct {
 	mock.Mock
 }
@@ -28,6 +29,97 @@ func (_m *UserRepository) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
+// Edit provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) Edit(_a0 context.Context, _a1 domain.User) (domain.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) Get(_a0 context.Context, _a1 *[]domain.User) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.User) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByEmail provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetByEmail(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetByID(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUsername provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetByUsername(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetProfile(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Login provides a mock function with given fields: _a0, _a1
 func (_m *UserRepository) Login(_a0 context.Context, _a1 *domain.User) error {
 	ret := _m.Called(_a0, _a1)
@@ -56,25 +148,60 @@ func (_m *UserRepository) Register(_a0 context.Context, _a1 *domain.User) error
 	return r0
 }
 
-// Edit provides a mock function with given fields: _a0, _a1
-func (_m *UserRepository) Edit(_a0 context.Context, _a1 domain.User) (domain.User, error) {
-	ret := _m.Called(_a0, _a1)
+// SetPassword provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetPassword(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 domain.User
-	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.User); ok {
-		r0 = rf(_a0, _a1)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(domain.User)
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.User) error); ok {
-		r1 = rf(_a0, _a1)
+	return r0
+}
+
+// SetProfileImage provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetProfileImage(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
+}
+
+// SetRole provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetRole(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSuspended provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetSuspended(_a0 context.Context, _a1 string, _a2 *time.Time) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 type mockConstructorTestingTNewUserRepository interface {
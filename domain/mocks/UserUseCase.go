@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	domain "mygram-byferdiansyah/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -28,12 +29,33 @@ func (_m *UserUseCase) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Login provides a mock function with given fields: _a0, _a1
-func (_m *UserUseCase) Login(_a0 context.Context, _a1 *domain.User) error {
+// Edit provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) Edit(_a0 context.Context, _a1 domain.User) (domain.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) Get(_a0 context.Context, _a1 *[]domain.User) error {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.User) error); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
@@ -42,8 +64,50 @@ func (_m *UserUseCase) Login(_a0 context.Context, _a1 *domain.User) error {
 	return r0
 }
 
-// Register provides a mock function with given fields: _a0, _a1
-func (_m *UserUseCase) Register(_a0 context.Context, _a1 *domain.User) error {
+// GetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUseCase) GetByID(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUsername provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUseCase) GetByUsername(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUseCase) GetProfile(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Login provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) Login(_a0 context.Context, _a1 *domain.User) error {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 error
@@ -56,25 +120,32 @@ func (_m *UserUseCase) Register(_a0 context.Context, _a1 *domain.User) error {
 	return r0
 }
 
-// Edit provides a mock function with given fields: _a0, _a1
-func (_m *UserUseCase) Edit(_a0 context.Context, _a1 domain.User) (domain.User, error) {
+// Register provides a mock function with given fields: _a0, _a1
+func (_m *UserUseCase) Register(_a0 context.Context, _a1 *domain.User) error {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 domain.User
-	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.User); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Get(0).(domain.User)
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.User) error); ok {
-		r1 = rf(_a0, _a1)
+	return r0
+}
+
+// SetAvatar provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserUseCase) SetAvatar(_a0 context.Context, _a1 *domain.User, _a2 io.Reader) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, io.Reader) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
 type mockConstructorTestingTNewUserUseCase interface {
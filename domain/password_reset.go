@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const MinPasswordLength = 6
+
+var (
+	ErrPasswordTooShort     = errors.New("the password must be at least 6 characters")
+	ErrWrongPassword        = errors.New("the current password you entered is wrong")
+	ErrPasswordResetInvalid = errors.New("the reset token is invalid or expired")
+)
+
+// PasswordReset lets the owner of an email set a new password without the
+// current one. Only the SHA-256 hash of the token is stored and a token can
+// be used once.
+type PasswordReset struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	Token     string     `gorm:"-" json:"-"`
+	ExpiresAt *time.Time `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type PasswordUseCase interface {
+	Change(context.Context, string, string, string) error
+	RequestReset(context.Context, string) error
+	Reset(context.Context, string, string) error
+}
+
+type PasswordResetRepository interface {
+	Create(context.Context, *PasswordReset) error
+	GetByHash(context.Context, *PasswordReset, string) error
+	Use(context.Context, PasswordReset, string) error
+}
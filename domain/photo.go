@@ -2,22 +2,98 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"io"
+	"mygram-byferdiansyah/pagination"
 	"time"
 
 	"github.com/asaskevich/govalidator"
 	"gorm.io/gorm"
 )
 
+const (
+	DuplicatePolicyReject = "reject"
+	DuplicatePolicyFlag   = "flag"
+
+	// MaxDuplicateDistance is the largest Hamming distance between perceptual
+	// hashes that near-duplicates can be searched for.
+	MaxDuplicateDistance = 3
+)
+
+var (
+	ErrUnsupportedImageType = errors.New("the image must be a JPEG, PNG, GIF or WebP file")
+	ErrImageTooLarge        = errors.New("the image is larger than the upload limit")
+	ErrDuplicateImage       = errors.New("you have already uploaded this image")
+	ErrDistanceOutOfRange   = errors.New("the distance must be between 0 and 3")
+)
+
 type Image struct {
-	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	Title     string     `gorm:"type:VARCHAR(50);not null" valid:"required" form:"title" json:"title" example:"A Image Title"`
-	Caption   string     `form:"caption" json:"caption"`
-	ImageUrl  string     `gorm:"not null" valid:"required" form:"image_url" json:"image_url" example:"https://www.example.com/image.jpg"`
-	UserID    string     `gorm:"type:VARCHAR(50);not null" json:"user_id"`
-	User      *User      `gorm:"foreignKey:UserID;constraint:onEdit:CASCADE,onDelete:CASCADE" json:"-"`
-	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
-	Comment   *Comment   `json:"-"`
+	ID          string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	Title       string         `gorm:"type:VARCHAR(50);not null" valid:"required" form:"title" json:"title" example:"A Image Title"`
+	Caption     string         `form:"caption" json:"caption"`
+	ImageUrl    string         `gorm:"not null" valid:"required" form:"image_url" json:"image_url" example:"https://www.example.com/image.jpg"`
+	ObjectKey   string         `gorm:"type:VARCHAR(255)" json:"-"`
+	ContentType string         `gorm:"type:VARCHAR(50)" json:"-"`
+	Size        int64          `json:"-"`
+	Width       int            `json:"-"`
+	Height      int            `json:"-"`
+	BlurHash    string         `gorm:"type:VARCHAR(100)" json:"-"`
+	ProcessedAt *time.Time     `json:"-"`
+	ContentHash string         `gorm:"type:CHAR(64);index" json:"-"`
+	DuplicateOf string         `gorm:"type:VARCHAR(50)" json:"-"`
+	PHash       *int64         `json:"-"`
+	PHashBand0  int            `gorm:"index" json:"-"`
+	PHashBand1  int            `gorm:"index" json:"-"`
+	PHashBand2  int            `gorm:"index" json:"-"`
+	PHashBand3  int            `gorm:"index" json:"-"`
+	Variants    []ImageVariant `gorm:"foreignKey:ImageID;constraint:onDelete:CASCADE" json:"-"`
+	LikeCount   int            `gorm:"not null;default:0" json:"like_count"`
+	LikedByMe   bool           `gorm:"-" json:"liked_by_me"`
+	UserID      string         `gorm:"type:VARCHAR(50);not null" json:"user_id"`
+	User        *User          `gorm:"foreignKey:UserID;constraint:onEdit:CASCADE,onDelete:CASCADE" json:"-"`
+	CreatedAt   *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt   *time.Time     `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
+	Comment     *Comment       `json:"-"`
+}
+
+// ImageVariant is a downscaled copy of an uploaded image, named after its
+// width.
+type ImageVariant struct {
+	ID          string `gorm:"primaryKey;type:VARCHAR(50)" json:"-"`
+	ImageID     string `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_image_variants_image_name" json:"-"`
+	Name        string `gorm:"type:VARCHAR(20);not null;uniqueIndex:idx_image_variants_image_name" json:"name"`
+	Width       int    `gorm:"not null" json:"width"`
+	Height      int    `gorm:"not null" json:"height"`
+	ObjectKey   string `gorm:"type:VARCHAR(255);not null" json:"-"`
+	ContentType string `gorm:"type:VARCHAR(50);not null" json:"-"`
+	Size        int64  `json:"-"`
+}
+
+// ImageDuplicate pairs two images whose perceptual hashes are Distance bits
+// apart.
+type ImageDuplicate struct {
+	ImageID         string `json:"image_id"`
+	UserID          string `json:"user_id"`
+	DuplicateID     string `json:"duplicate_id"`
+	DuplicateUserID string `json:"duplicate_user_id"`
+	Distance        int    `json:"distance"`
+}
+
+// SetPHash stores the perceptual hash along with its four 16 bit bands.
+// Hashes at most MaxDuplicateDistance bits apart share at least one band, so
+// the indexed bands narrow down the candidates for a near-duplicate.
+func (photo *Image) SetPHash(hash uint64) {
+	signed := int64(hash)
+
+	photo.PHash = &signed
+	photo.PHashBand0 = int(hash & 0xFFFF)
+	photo.PHashBand1 = int(hash >> 16 & 0xFFFF)
+	photo.PHashBand2 = int(hash >> 32 & 0xFFFF)
+	photo.PHashBand3 = int(hash >> 48 & 0xFFFF)
+}
+
+func (photo *Image) OwnerID() string {
+	return photo.UserID
 }
 
 func (photo *Image) BeforeCreate(db *gorm.DB) (err error) {
@@ -36,17 +112,30 @@ func (photo *Image) BeforeEdit(db *gorm.DB) (err error) {
 }
 
 type ImageUseCase interface {
-	Get(context.Context, *[]Image) error
+	Get(context.Context, *[]Image, *pagination.Page) error
+	GetFeed(context.Context, *[]Image, string, *pagination.Page) error
 	Create(context.Context, *Image) error
+	Upload(context.Context, *Image, io.Reader) error
 	GetByID(context.Context, *Image, string) error
 	Edit(context.Context, Image, string) (Image, error)
 	Delete(context.Context, string) error
+	GetNearDuplicates(context.Context, *[]ImageDuplicate, int) error
 }
 
 type ImageRepository interface {
-	Get(context.Context, *[]Image) error
+	Get(context.Context, *[]Image, *pagination.Page) error
 	Create(context.Context, *Image) error
 	GetByID(context.Context, *Image, string) error
 	Edit(context.Context, Image, string) (Image, error)
 	Delete(context.Context, string) error
+	GetUnprocessed(context.Context, *[]Image) error
+	SaveVariants(context.Context, *Image) error
+	GetIDByContentHash(context.Context, string, string) (string, error)
+	GetNearDuplicates(context.Context, *[]ImageDuplicate, int) error
+}
+
+// ImageQueue hands uploaded images over to the background worker that
+// prepares their variants.
+type ImageQueue interface {
+	Enqueue(string)
 }
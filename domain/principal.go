@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Principal is the authenticated caller of a request. APIKeyID is set when
+// the caller signed in with an API key rather than an access token, and
+// Scopes then limits what it can do.
+type Principal struct {
+	UserID    string
+	Email     string
+	Roles     []string
+	TokenID   string
+	APIKeyID  string
+	Scopes    []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+func (principal Principal) HasRole(role string) bool {
+	for _, r := range principal.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (principal Principal) HasScope(scope string) bool {
+	for _, s := range principal.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal, so usecases and
+// repositories can see who is acting.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal stored in ctx by WithPrincipal.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+
+	return principal, ok
+}
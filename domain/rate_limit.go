@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// The rate limit policies routes are limited with. Every policy keeps its
+// own buckets, so requests limited by a stricter policy on a route also
+// count against the default one of its group.
+const (
+	RateLimitDefault            = "default"
+	RateLimitCreateImage        = "images.create"
+	RateLimitCreateComment      = "comments.create"
+	RateLimitLoginTwoFactor     = "users.login_2fa"
+	RateLimitVerifyEmail        = "users.verify_email"
+	RateLimitResendVerification = "users.resend_verification"
+	RateLimitForgotPassword     = "users.forgot_password"
+)
+
+// RateLimitPolicy is a token bucket that holds up to Limit requests and
+// refills at Limit requests every Period, so a client can send a burst of
+// Limit requests and then keeps going at the average rate.
+type RateLimitPolicy struct {
+	Limit  int
+	Period time.Duration
+}
+
+// RateLimitResult tells whether a request was let through. RetryAfter is
+// how long until the next request is, and ResetAfter how long until the
+// bucket is full again.
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// RateLimitBucket is the state of a token bucket as the database keeps it.
+// FullAt is when the bucket would be full again, after which it can be
+// dropped.
+type RateLimitBucket struct {
+	Key        string     `gorm:"primaryKey;type:VARCHAR(150)"`
+	Tokens     float64    `gorm:"not null"`
+	RefilledAt *time.Time `gorm:"not null"`
+	FullAt     *time.Time `gorm:"not null;index"`
+}
+
+// Take refills a bucket that held tokens elapsed ago and takes a request out
+// of it. It returns the tokens left in the bucket along with the result.
+func (policy RateLimitPolicy) Take(tokens float64, elapsed time.Duration) (float64, RateLimitResult) {
+	limit := float64(policy.Limit)
+	interval := float64(policy.Period) / limit
+	tokens = math.Min(limit, tokens+float64(elapsed)/interval)
+	result := RateLimitResult{}
+
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) * interval)
+	}
+
+	result.Remaining = int(tokens)
+	result.ResetAfter = time.Duration((limit - tokens) * interval)
+
+	return tokens, result
+}
+
+// RateLimitStore keeps the token buckets requests are limited with. Take
+// takes a request out of the bucket with the key, starting a full bucket for
+// a key it hasn't seen.
+type RateLimitStore interface {
+	Take(context.Context, string, RateLimitPolicy) (RateLimitResult, error)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("the refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("the refresh token has already been used")
+)
+
+// RefreshToken is a long-lived opaque token that can be exchanged for a new
+// access token. Only the SHA-256 hash is stored; Token holds the plain value
+// right after it has been issued. Every token issued by rotating another one
+// shares its FamilyID, so a whole login session can be revoked at once.
+type RefreshToken struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	FamilyID  string     `gorm:"type:VARCHAR(50);not null;index" json:"family_id"`
+	TokenHash string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	Token     string     `gorm:"-" json:"-"`
+	ExpiresAt *time.Time `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type RefreshTokenUseCase interface {
+	Create(context.Context, *RefreshToken) error
+	Rotate(context.Context, *RefreshToken, string) error
+	Revoke(context.Context, string) error
+}
+
+type RefreshTokenRepository interface {
+	Create(context.Context, *RefreshToken) error
+	GetByHash(context.Context, *RefreshToken, string) error
+	Rotate(context.Context, string, *RefreshToken) error
+	RevokeFamily(context.Context, string) error
+	RevokeAll(context.Context, string) error
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"mygram-byferdiansyah/pagination"
 	"time"
 
 	"github.com/asaskevich/govalidator"
@@ -18,6 +19,10 @@ type SocialMedia struct {
 	User           *User      `gorm:"foreignKey:UserID;constraint:onEdit:CASCADE,onDelete:CASCADE" json:"user"`
 }
 
+func (s *SocialMedia) OwnerID() string {
+	return s.UserID
+}
+
 func (s *SocialMedia) BeforeCreate(db *gorm.DB) (err error) {
 	if _, err := govalidator.ValidateStruct(s); err != nil {
 		return err
@@ -34,7 +39,7 @@ func (s *SocialMedia) BeforeEdit(db *gorm.DB) (err error) {
 }
 
 type SocialMediaUseCase interface {
-	Get(context.Context, *[]SocialMedia, string) error
+	Get(context.Context, *[]SocialMedia, string, *pagination.Page) error
 	Create(context.Context, *SocialMedia) error
 	GetByID(context.Context, *SocialMedia, string) error
 	Edit(context.Context, SocialMedia, string) (SocialMedia, error)
@@ -42,7 +47,7 @@ type SocialMediaUseCase interface {
 }
 
 type SocialMediaRepository interface {
-	Get(context.Context, *[]SocialMedia, string) error
+	Get(context.Context, *[]SocialMedia, string, *pagination.Page) error
 	Create(context.Context, *SocialMedia) error
 	GetByID(context.Context, *SocialMedia, string) error
 	Edit(context.Context, SocialMedia, string) (SocialMedia, error)
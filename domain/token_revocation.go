@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RevokedToken blocks a single access token, identified by its jti claim,
+// until the token would have expired anyway.
+type RevokedToken struct {
+	JTI       string     `gorm:"primaryKey;type:VARCHAR(50)" json:"jti"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	ExpiresAt *time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+}
+
+// TokenTimePrecision is how precisely an access token records when it was
+// issued. Cutoffs are stored at the same precision, so a token issued in the
+// millisecond of a cutoff, like the one handed out right after changing the
+// password, isn't taken for one issued before it.
+const TokenTimePrecision = time.Millisecond
+
+// TokenCutoff blocks every access token of a user issued before ValidAfter.
+// It deliberately has no foreign key so it outlives a deleted account.
+type TokenCutoff struct {
+	UserID     string     `gorm:"primaryKey;type:VARCHAR(50)" json:"user_id"`
+	ValidAfter *time.Time `gorm:"not null" json:"valid_after"`
+}
+
+type TokenRevocationUseCase interface {
+	Revoke(context.Context, RevokedToken) error
+	RevokeAll(context.Context, string) error
+	IsRevoked(context.Context, string, string, time.Time) (bool, error)
+}
+
+type TokenRevocationRepository interface {
+	Revoke(context.Context, RevokedToken) error
+	RevokeAll(context.Context, string, time.Time) error
+	IsRevoked(context.Context, string, string, time.Time) (bool, error)
+}
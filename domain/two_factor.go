@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const RecoveryCodeCount = 10
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication isn't enabled")
+	ErrTwoFactorNotEnrolled = errors.New("enroll in two-factor authentication before confirming it")
+	ErrInvalidCode          = errors.New("the code you entered is wrong")
+	ErrChallengeInvalid     = errors.New("the login challenge is invalid or expired, sign in again")
+)
+
+// TwoFactorEnrollment is what a user needs to set up an authenticator app.
+// The recovery codes are only ever shown here, each one signs in once when
+// the app is lost.
+type TwoFactorEnrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+// RecoveryCode is the SHA-256 hash of a single-use code that stands in for a
+// TOTP code.
+type RecoveryCode struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:VARCHAR(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// LoginChallenge is handed out instead of tokens when the password of a user
+// with two-factor authentication is right. Only the SHA-256 hash of the
+// token is stored, and the challenge is dropped after too many wrong codes.
+type LoginChallenge struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	Token     string     `gorm:"-" json:"-"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	ExpiresAt *time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type TwoFactorUseCase interface {
+	Enroll(context.Context, string) (TwoFactorEnrollment, error)
+	Confirm(context.Context, string, string) error
+	Disable(context.Context, string, string, string) error
+	RegenerateRecoveryCodes(context.Context, string, string, string) ([]string, error)
+	Challenge(context.Context, *LoginChallenge) error
+	Verify(context.Context, string, string) (string, error)
+}
+
+type TwoFactorRepository interface {
+	Enroll(context.Context, string, string, []string) error
+	Enable(context.Context, string, int64) error
+	Disable(context.Context, string) error
+	SetRecoveryCodes(context.Context, string, []string) error
+	UseStep(context.Context, string, int64) error
+	UseRecoveryCode(context.Context, string, string) error
+	CreateChallenge(context.Context, *LoginChallenge) error
+	GetChallenge(context.Context, *LoginChallenge, string) error
+	FailChallenge(context.Context, string, int) error
+	DeleteChallenge(context.Context, string) error
+}
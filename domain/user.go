@@ -2,24 +2,78 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"io"
 	"mygram-byferdiansyah/helpers"
+	"strings"
 	"time"
 
 	"github.com/asaskevich/govalidator"
 	"gorm.io/gorm"
 )
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 300
+)
+
+var (
+	ErrUserSuspended      = errors.New("your account has been suspended")
+	ErrDisplayNameTooLong = errors.New("the display name must be at most 50 characters")
+	ErrBioTooLong         = errors.New("the bio must be at most 300 characters")
+	ErrUsernameTaken      = errors.New("the username you entered has been used")
+	ErrEmailTaken         = errors.New("the email you entered has been used")
+)
+
 type User struct {
-	ID              string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	Username        string         `gorm:"type:VARCHAR(50);uniqueIndex;not null" valid:"required" form:"username" json:"username" example:"johndoe"`
-	Email           string         `gorm:"type:VARCHAR(50);uniqueIndex;not null" valid:"email,required" form:"email" json:"email" example:"johndoe@example.com"`
-	Password        string         `gorm:"not null" valid:"required,minstringlength(6)" form:"password" json:"password,omitempty" example:"secret"`
-	Age             uint           `gorm:"not null" valid:"required,range(8|63)" form:"age" json:"age,omitempty" example:"8"`
-	ProfileImageUrl string         `json:"profileImageUrl,omitempty" example:"https://www.example.com/image.jpg"`
-	CreatedAt       *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt       *time.Time     `gorm:"not null;autocreateTime" json:"updated_at,omitempty"`
-	Images          *[]Image       `json:"-"`
-	SocialMedias    *[]SocialMedia `json:"-"`
+	ID                 string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	Username           string         `gorm:"type:VARCHAR(50);uniqueIndex;not null" valid:"required" form:"username" json:"username" example:"johndoe"`
+	Email              string         `gorm:"type:VARCHAR(50);uniqueIndex;not null" valid:"email,required" form:"email" json:"email" example:"johndoe@example.com"`
+	EmailVerifiedAt    *time.Time     `json:"-"`
+	Password           string         `gorm:"not null" valid:"required,minstringlength(6)" form:"password" json:"password,omitempty" example:"secret"`
+	Age                uint           `gorm:"not null" valid:"required,range(8|63)" form:"age" json:"age,omitempty" example:"8"`
+	ProfileImageUrl    string         `json:"profileImageUrl,omitempty" example:"https://www.example.com/image.jpg"`
+	DisplayName        string         `gorm:"type:VARCHAR(50)" json:"-"`
+	Bio                string         `gorm:"type:VARCHAR(300)" json:"-"`
+	Role               string         `gorm:"type:VARCHAR(20);not null;default:user" valid:"in(user|moderator|admin)" json:"-"`
+	SuspendedAt        *time.Time     `json:"-"`
+	TOTPSecret         string         `gorm:"type:VARCHAR(64)" json:"-"`
+	TOTPLastStep       int64          `gorm:"not null;default:0" json:"-"`
+	TwoFactorEnabledAt *time.Time     `json:"-"`
+	Private            bool           `gorm:"not null;default:false" json:"-"`
+	FollowerCount      int            `gorm:"not null;default:0" json:"-"`
+	FollowingCount     int            `gorm:"not null;default:0" json:"-"`
+	ImageCount         int            `gorm:"-" json:"-"`
+	CreatedAt          *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt          *time.Time     `gorm:"not null;autocreateTime" json:"updated_at,omitempty"`
+	Images             *[]Image       `json:"-"`
+	SocialMedias       *[]SocialMedia `json:"-"`
+}
+
+// Roles returns the roles carried in the user's access tokens.
+func (user *User) Roles() []string {
+	if user.Role == "" {
+		return []string{RoleUser}
+	}
+
+	return []string{user.Role}
+}
+
+// AvatarKey returns the key of the stored avatar ProfileImageUrl points at,
+// or false when it doesn't point at one under the avatars of the user. Links
+// to any other stored file are never treated as the avatar.
+func (user *User) AvatarKey() (string, bool) {
+	key, ok := FileKey(user.ProfileImageUrl)
+
+	if !ok || !strings.HasPrefix(key, "avatars/"+user.ID+"/") {
+		return "", false
+	}
+
+	return key, true
 }
 
 func (user *User) BeforeCreate(db *gorm.DB) (err error) {
@@ -43,13 +97,27 @@ func (user *User) BeforeEdit(db *gorm.DB) (err error) {
 type UserUseCase interface {
 	Register(context.Context, *User) error
 	Login(context.Context, *User) error
+	Get(context.Context, *[]User) error
+	GetByID(context.Context, *User, string) error
+	GetByUsername(context.Context, *User, string) error
+	GetProfile(context.Context, *User, string) error
 	Edit(context.Context, User) (User, error)
+	SetAvatar(context.Context, *User, io.Reader) error
 	Delete(context.Context, string) error
 }
 
 type UserRepository interface {
 	Register(context.Context, *User) error
 	Login(context.Context, *User) error
+	Get(context.Context, *[]User) error
+	GetByID(context.Context, *User, string) error
+	GetByUsername(context.Context, *User, string) error
+	GetByEmail(context.Context, *User, string) error
+	GetProfile(context.Context, *User, string) error
 	Edit(context.Context, User) (User, error)
+	SetPassword(context.Context, string, string) error
+	SetProfileImage(context.Context, string, string) error
+	SetRole(context.Context, string, string) error
+	SetSuspended(context.Context, string, *time.Time) error
 	Delete(context.Context, string) error
 }
@@ -0,0 +1,55 @@
+package feed
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// feedStore builds the feed on every request by querying the images of the
+// accounts the user follows, so following, unfollowing and posting cost
+// nothing extra.
+type feedStore struct {
+	db *gorm.DB
+}
+
+func NewFeedStore(db *gorm.DB) *feedStore {
+	return &feedStore{db}
+}
+
+func (feedStore *feedStore) Get(ctx context.Context, images *[]domain.Image, userID string, page *pagination.Page) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	db := feedStore.db.WithContext(ctx)
+
+	followees := db.Model(&domain.Follow{}).Select("followee_id").Where("follower_id = ? AND status = ?", userID, domain.FollowStatusAccepted)
+
+	if err = page.Query(db).Where("user_id IN (?)", followees).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email")
+	}).Preload("Variants").Find(&images).Error; err != nil {
+		return err
+	}
+
+	pagination.Finish(page, images, func(image domain.Image) (*time.Time, string) {
+		return image.CreatedAt, image.ID
+	})
+
+	return
+}
+
+func (feedStore *feedStore) Add(ctx context.Context, image *domain.Image) (err error) {
+	return
+}
+
+func (feedStore *feedStore) Follow(ctx context.Context, followerID string, followeeID string) (err error) {
+	return
+}
+
+func (feedStore *feedStore) Unfollow(ctx context.Context, followerID string, followeeID string) (err error) {
+	return
+}
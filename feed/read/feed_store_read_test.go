@@ -0,0 +1,54 @@
+package feed_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+	"testing"
+	"time"
+
+	feedStore "mygram-byferdiansyah/feed/read"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recorder keeps the SQL of every statement instead of logging it.
+type recorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (recorder *recorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	recorder.statements = append(recorder.statements, sql)
+}
+
+func TestFeedStore(t *testing.T) {
+	ctx := context.Background()
+	recorder := &recorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: recorder})
+
+	assert.NoError(t, err)
+
+	store := feedStore.NewFeedStore(db)
+
+	t.Run("get a page of the images of the followed accounts", func(t *testing.T) {
+		page := &pagination.Page{Limit: 2, Sort: pagination.SortDesc}
+		images := []domain.Image{}
+
+		assert.NoError(t, store.Get(ctx, &images, "user-123", page))
+		assert.Equal(t, `SELECT * FROM "images" WHERE user_id IN (SELECT "followee_id" FROM "follows" WHERE follower_id = 'user-123' AND status = 'accepted') ORDER BY "created_at" DESC,"id" DESC LIMIT 3`, recorder.statements[0])
+	})
+
+	t.Run("keep nothing on posting and following", func(t *testing.T) {
+		recorder.statements = nil
+
+		assert.NoError(t, store.Add(ctx, &domain.Image{ID: "image-123", UserID: "user-123"}))
+		assert.NoError(t, store.Follow(ctx, "user-123", "user-234"))
+		assert.NoError(t, store.Unfollow(ctx, "user-123", "user-234"))
+		assert.Empty(t, recorder.statements)
+	})
+}
@@ -0,0 +1,136 @@
+package feed
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// backfillSize is how many of the latest images of an account are copied
+// into the feed of a new follower.
+const backfillSize = 500
+
+// feedStore keeps a feed per user in the feed_items table and copies every
+// image into the feeds of the followers of its author when it is posted, so
+// reading a feed is a single indexed range scan.
+type feedStore struct {
+	db *gorm.DB
+}
+
+// item is the part of a feed item a page is cut from.
+type item struct {
+	ID        string
+	CreatedAt *time.Time
+}
+
+func NewFeedStore(db *gorm.DB) *feedStore {
+	return &feedStore{db}
+}
+
+func (feedStore *feedStore) Get(ctx context.Context, images *[]domain.Image, userID string, page *pagination.Page) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	db := feedStore.db.WithContext(ctx)
+
+	// The feed items are paged on their own and the images loaded after, as
+	// the keyset of a page is made of the created_at and id columns.
+	feed := db.Model(&domain.FeedItem{}).Select("image_id AS id", "created_at").Where("user_id = ?", userID)
+	items := []item{}
+
+	if err = page.Query(db.Table("(?) AS feed_items", feed)).Find(&items).Error; err != nil {
+		return err
+	}
+
+	pagination.Finish(page, &items, func(item item) (*time.Time, string) {
+		return item.CreatedAt, item.ID
+	})
+
+	*images = []domain.Image{}
+
+	if len(items) == 0 {
+		return
+	}
+
+	ids := make([]string, len(items))
+	positions := make(map[string]int, len(items))
+
+	for i, item := range items {
+		ids[i] = item.ID
+		positions[item.ID] = i
+	}
+
+	found := []domain.Image{}
+
+	if err = db.Where("id IN ?", ids).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email")
+	}).Preload("Variants").Find(&found).Error; err != nil {
+		return err
+	}
+
+	ordered := make([]*domain.Image, len(items))
+
+	for i := range found {
+		ordered[positions[found[i].ID]] = &found[i]
+	}
+
+	for _, image := range ordered {
+		if image != nil {
+			*images = append(*images, *image)
+		}
+	}
+
+	return
+}
+
+// Add copies image into the feeds of the accepted followers of its author.
+func (feedStore *feedStore) Add(ctx context.Context, image *domain.Image) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = feedStore.db.WithContext(ctx).Exec(
+		"INSERT INTO feed_items (user_id, image_id, created_at) SELECT follower_id, ?, ? FROM follows WHERE followee_id = ? AND status = ? ON CONFLICT DO NOTHING",
+		image.ID, image.CreatedAt, image.UserID, domain.FollowStatusAccepted,
+	).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Follow copies the latest images of the followee into the feed of the
+// follower. Following again copies nothing twice.
+func (feedStore *feedStore) Follow(ctx context.Context, followerID string, followeeID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = feedStore.db.WithContext(ctx).Exec(
+		"INSERT INTO feed_items (user_id, image_id, created_at) SELECT ?, id, created_at FROM images WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT ? ON CONFLICT DO NOTHING",
+		followerID, followeeID, backfillSize,
+	).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Unfollow removes the images of the followee from the feed of the follower.
+func (feedStore *feedStore) Unfollow(ctx context.Context, followerID string, followeeID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	images := feedStore.db.Model(&domain.Image{}).Select("id").Where("user_id = ?", followeeID)
+
+	if err = feedStore.db.WithContext(ctx).Where("user_id = ? AND image_id IN (?)", followerID, images).Delete(&domain.FeedItem{}).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,74 @@
+package feed_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+	"testing"
+	"time"
+
+	feedStore "mygram-byferdiansyah/feed/write"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recorder keeps the SQL of every statement instead of logging it.
+type recorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (recorder *recorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	recorder.statements = append(recorder.statements, sql)
+}
+
+func open(t *testing.T) (*gorm.DB, *recorder) {
+	recorder := &recorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: recorder})
+
+	assert.NoError(t, err)
+
+	return db, recorder
+}
+
+func TestFeedStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get a page of the feed items of the user", func(t *testing.T) {
+		db, recorder := open(t)
+		page := &pagination.Page{Limit: 2, Sort: pagination.SortDesc}
+		images := []domain.Image{}
+
+		assert.NoError(t, feedStore.NewFeedStore(db).Get(ctx, &images, "user-123", page))
+		assert.Equal(t, []string{
+			`SELECT * FROM (SELECT image_id AS id,"created_at" FROM "feed_items" WHERE user_id = 'user-123') AS feed_items ORDER BY "created_at" DESC,"id" DESC LIMIT 3`,
+		}, recorder.statements)
+		assert.Empty(t, images)
+	})
+
+	t.Run("add an image to the feeds of the followers", func(t *testing.T) {
+		db, recorder := open(t)
+		createdAt := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+
+		assert.NoError(t, feedStore.NewFeedStore(db).Add(ctx, &domain.Image{ID: "image-123", UserID: "user-123", CreatedAt: &createdAt}))
+		assert.Equal(t, []string{
+			`INSERT INTO feed_items (user_id, image_id, created_at) SELECT follower_id, 'image-123', '2023-01-02 15:04:05' FROM follows WHERE followee_id = 'user-123' AND status = 'accepted' ON CONFLICT DO NOTHING`,
+		}, recorder.statements)
+	})
+
+	t.Run("backfill and empty the feed on follow and unfollow", func(t *testing.T) {
+		db, recorder := open(t)
+		store := feedStore.NewFeedStore(db)
+
+		assert.NoError(t, store.Follow(ctx, "user-123", "user-234"))
+		assert.NoError(t, store.Unfollow(ctx, "user-123", "user-234"))
+		assert.Equal(t, []string{
+			`INSERT INTO feed_items (user_id, image_id, created_at) SELECT 'user-123', id, created_at FROM images WHERE user_id = 'user-234' ORDER BY created_at DESC, id DESC LIMIT 500 ON CONFLICT DO NOTHING`,
+			`DELETE FROM "feed_items" WHERE user_id = 'user-123' AND image_id IN (SELECT "id" FROM "images" WHERE user_id = 'user-234')`,
+		}, recorder.statements)
+	})
+}
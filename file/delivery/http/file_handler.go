@@ -0,0 +1,80 @@
+package delivery
+
+import (
+	"errors"
+	"mime"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fileHandler struct {
+	blobStore domain.BlobStore
+	urlSigner *helpers.URLSigner
+}
+
+func NewFileHandler(routers *gin.Engine, blobStore domain.BlobStore, urlSigner *helpers.URLSigner, rateLimiter *middleware.RateLimiter) {
+	handler := &fileHandler{blobStore, urlSigner}
+
+	routers.GET("/files/*key", rateLimiter.Limit(domain.RateLimitDefault), handler.Get)
+}
+
+// Get godoc
+// @Summary			Get a stored file
+// @Description	Serve an uploaded file through the signed link returned with the resource it belongs to
+// @Tags        files
+// @Produce     image/jpeg,image/png,image/gif,image/webp
+// @Param       key				path			string	true	"File key"
+// @Param       expires		query			string	true	"Expiry of the link"
+// @Param       signature	query			string	true	"Signature of the link"
+// @Success     200
+// @Failure     403	{object}	helpers.ResponseMessage
+// @Failure     404	{object}	helpers.ResponseMessage
+// @Router      /files/{key}	[get]
+func (handler *fileHandler) Get(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	if err := handler.urlSigner.Verify(domain.FileURLPath(key), ctx.Query("expires"), ctx.Query("signature")); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	body, err := handler.blobStore.Get(ctx.Request.Context(), key)
+
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrBlobNotFound) {
+			status = http.StatusNotFound
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	defer body.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	ctx.DataFromReader(http.StatusOK, -1, contentType, body, map[string]string{
+		"Cache-Control":          "private, max-age=300",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
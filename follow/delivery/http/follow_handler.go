@@ -0,0 +1,412 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/follow/utils"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"mygram-byferdiansyah/pagination"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type followHandler struct {
+	followUseCase domain.FollowUseCase
+	userUseCase   domain.UserUseCase
+	urlSigner     *helpers.URLSigner
+}
+
+func NewFollowHandler(routers *gin.Engine, followUseCase domain.FollowUseCase, userUseCase domain.UserUseCase, urlSigner *helpers.URLSigner, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, rateLimiter *middleware.RateLimiter) {
+	handler := &followHandler{followUseCase, userUseCase, urlSigner}
+
+	router := routers.Group("/users")
+	{
+		router.Use(rateLimiter.LimitAddress(domain.RateLimitDefault))
+		router.Use(middleware.Authentication(tokenManager, tokenRevocationUseCase))
+		router.Use(rateLimiter.Limit(domain.RateLimitDefault))
+		router.PUT("/:username/follow", handler.Follow)
+		router.DELETE("/:username/follow", handler.Unfollow)
+		router.GET("/:username/followers", handler.GetFollowers)
+		router.GET("/:username/following", handler.GetFollowing)
+		router.GET("/follow-requests", handler.GetRequests)
+		router.PUT("/follow-requests/:username", handler.Approve)
+		router.DELETE("/follow-requests/:username", handler.Reject)
+		router.PUT("/privacy", handler.SetPrivacy)
+	}
+}
+
+// Follow godoc
+// @Summary			Follow a user
+// @Description	Follow a user with authentication user. Following a private account sends a follow request the account has to approve
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       username	path			string	true	"Username"
+// @Success     200			{object}	utils.ResponseDataFollowState
+// @Failure     400			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/{username}/follow	[put]
+func (handler *followHandler) Follow(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+	userID, ok := handler.userID(ctx)
+
+	if !ok {
+		return
+	}
+
+	status, err := handler.followUseCase.Follow(ctx.Request.Context(), principal.UserID, userID)
+
+	if err != nil {
+		abort(ctx, err, fmt.Sprintf("user with username %s doesn't exist", ctx.Param("username")))
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.FollowState{
+			Status: status,
+		},
+	})
+}
+
+// Unfollow godoc
+// @Summary			Unfollow a user
+// @Description	Unfollow a user, or withdraw the follow request, with authentication user. Unfollowing a user that isn't followed changes nothing
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       username	path			string	true	"Username"
+// @Success     200			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/{username}/follow	[delete]
+func (handler *followHandler) Unfollow(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+	userID, ok := handler.userID(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := handler.followUseCase.Unfollow(ctx.Request.Context(), principal.UserID, userID); err != nil {
+		abort(ctx, err, "")
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "you don't follow this user anymore",
+	})
+}
+
+// GetFollowers godoc
+// @Summary			Get the followers of a user
+// @Description	Get the accepted followers of a user with authentication user. The followers of a private account are only shown to the account and its followers
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       username				path			string	true	"Username"
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       sort						query			string	false	"Order by follow time"	Enums(asc, desc)	default(desc)
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
+// @Success     200			{object}	utils.ResponseDataFollows
+// @Failure     400			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/{username}/followers	[get]
+func (handler *followHandler) GetFollowers(ctx *gin.Context) {
+	userID, ok := handler.userID(ctx)
+
+	if !ok {
+		return
+	}
+
+	handler.list(ctx, userID, handler.followUseCase.GetFollowers, func(follow domain.Follow) *domain.User {
+		return follow.Follower
+	})
+}
+
+// GetFollowing godoc
+// @Summary			Get the users a user follows
+// @Description	Get the users a user follows with authentication user. The follows of a private account are only shown to the account and its followers
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       username				path			string	true	"Username"
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       sort						query			string	false	"Order by follow time"	Enums(asc, desc)	default(desc)
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
+// @Success     200			{object}	utils.ResponseDataFollows
+// @Failure     400			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/{username}/following	[get]
+func (handler *followHandler) GetFollowing(ctx *gin.Context) {
+	userID, ok := handler.userID(ctx)
+
+	if !ok {
+		return
+	}
+
+	handler.list(ctx, userID, handler.followUseCase.GetFollowing, func(follow domain.Follow) *domain.User {
+		return follow.Followee
+	})
+}
+
+// GetRequests godoc
+// @Summary			Get follow requests
+// @Description	Get the pending follow requests to authentication user
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       sort						query			string	false	"Order by request time"	Enums(asc, desc)	default(desc)
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
+// @Success     200			{object}	utils.ResponseDataFollows
+// @Failure     400			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/follow-requests	[get]
+func (handler *followHandler) GetRequests(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	handler.list(ctx, principal.UserID, func(ctx context.Context, follows *[]domain.Follow, viewerID string, userID string, page *pagination.Page) error {
+		return handler.followUseCase.GetRequests(ctx, follows, userID, page)
+	}, func(follow domain.Follow) *domain.User {
+		return follow.Follower
+	})
+}
+
+// Approve godoc
+// @Summary			Approve a follow request
+// @Description	Approve the follow request of a user to authentication user
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       username	path			string	true	"Username of the follower"
+// @Success     200			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/follow-requests/{username}	[put]
+func (handler *followHandler) Approve(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+	userID, ok := handler.userID(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := handler.followUseCase.Approve(ctx.Request.Context(), principal.UserID, userID); err != nil {
+		abort(ctx, err, fmt.Sprintf("follow request from %s doesn't exist", ctx.Param("username")))
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the follow request has been approved",
+	})
+}
+
+// Reject godoc
+// @Summary			Reject a follow request
+// @Description	Reject the follow request of a user to authentication user
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       username	path			string	true	"Username of the follower"
+// @Success     200			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/follow-requests/{username}	[delete]
+func (handler *followHandler) Reject(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+	userID, ok := handler.userID(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := handler.followUseCase.Reject(ctx.Request.Context(), principal.UserID, userID); err != nil {
+		abort(ctx, err, fmt.Sprintf("follow request from %s doesn't exist", ctx.Param("username")))
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the follow request has been rejected",
+	})
+}
+
+// SetPrivacy godoc
+// @Summary			Make the account private or public
+// @Description	Make the account of authentication user private or public. Making it public approves every pending follow request
+// @Tags        follows
+// @Accept      json
+// @Produce     json
+// @Param       json	body			utils.SetPrivacy	true	"Privacy"
+// @Success     200		{object}	utils.ResponseMessage
+// @Failure     400		{object}	utils.ResponseMessage
+// @Failure     401		{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /users/privacy	[put]
+func (handler *followHandler) SetPrivacy(ctx *gin.Context) {
+	var (
+		privacy utils.SetPrivacy
+		err     error
+	)
+
+	if err = ctx.ShouldBindJSON(&privacy); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = handler.followUseCase.SetPrivate(ctx.Request.Context(), principal.UserID, *privacy.Private); err != nil {
+		abort(ctx, err, fmt.Sprintf("user with id %s doesn't exist", principal.UserID))
+
+		return
+	}
+
+	message := "your account is public now"
+
+	if *privacy.Private {
+		message = "your account is private now"
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: message,
+	})
+}
+
+// list answers with a page of the follows of the user, showing for each one
+// the user that user picks out of it.
+func (handler *followHandler) list(ctx *gin.Context, userID string, get func(context.Context, *[]domain.Follow, string, string, *pagination.Page) error, user func(domain.Follow) *domain.User) {
+	var follows []domain.Follow
+
+	page, err := pagination.Parse(ctx)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = get(ctx.Request.Context(), &follows, principal.UserID, userID, page); err != nil {
+		abort(ctx, err, fmt.Sprintf("user with username %s doesn't exist", ctx.Param("username")))
+
+		return
+	}
+
+	listedFollows := []*utils.Follow{}
+
+	for _, follow := range follows {
+		listedFollow := &utils.Follow{
+			Status:    follow.Status,
+			CreatedAt: follow.CreatedAt,
+		}
+
+		if followUser := user(follow); followUser != nil {
+			listedFollow.User = &utils.User{
+				ID:              followUser.ID,
+				Username:        followUser.Username,
+				ProfileImageUrl: handler.avatarURL(*followUser),
+				Private:         followUser.Private,
+				FollowerCount:   followUser.FollowerCount,
+				FollowingCount:  followUser.FollowingCount,
+			}
+		}
+
+		listedFollows = append(listedFollows, listedFollow)
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status:     "success",
+		Data:       listedFollows,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+// userID looks up the user named by the username URL parameter. It answers
+// the request and returns false when there is no such user.
+func (handler *followHandler) userID(ctx *gin.Context) (string, bool) {
+	user := domain.User{}
+	username := ctx.Param("username")
+
+	if err := handler.userUseCase.GetByUsername(ctx.Request.Context(), &user, username); err != nil {
+		abort(ctx, err, fmt.Sprintf("user with username %s doesn't exist", username))
+
+		return "", false
+	}
+
+	return user.ID, true
+}
+
+// avatarURL returns a link the avatar of a listed user can be loaded from.
+// Links to stored files other than the avatars of the user are dropped.
+func (handler *followHandler) avatarURL(user domain.User) string {
+	if _, ok := user.AvatarKey(); ok {
+		return handler.urlSigner.Sign(user.ProfileImageUrl)
+	}
+
+	if _, ok := domain.FileKey(user.ProfileImageUrl); ok {
+		return ""
+	}
+
+	return user.ProfileImageUrl
+}
+
+// abort answers with the status err stands for, using notFound as the
+// message when a user or a follow request doesn't exist.
+func abort(ctx *gin.Context, err error, notFound string) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, domain.ErrSelfFollow):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrPrivateAccount):
+		status = http.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		status = http.StatusNotFound
+		err = errors.New(notFound)
+	}
+
+	ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+		Status:  "fail",
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type followRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) *followRepository {
+	return &followRepository{db}
+}
+
+// Create stores the follow unless the follower already follows or has asked
+// to follow the followee, in which case follow is filled with the existing
+// one.
+func (followRepository *followRepository) Create(ctx context.Context, follow *domain.Follow) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	follow.ID = fmt.Sprintf("follow-%s", ID)
+
+	return followRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(follow)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return tx.Where("follower_id = ? AND followee_id = ?", follow.FollowerID, follow.FolloweeID).Take(follow).Error
+		}
+
+		if follow.Status != domain.FollowStatusAccepted {
+			return nil
+		}
+
+		return countFollows(tx, []string{follow.FollowerID}, follow.FolloweeID, 1)
+	})
+}
+
+func (followRepository *followRepository) GetByUsers(ctx context.Context, follow *domain.Follow, followerID string, followeeID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = followRepository.db.WithContext(ctx).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Take(follow).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (followRepository *followRepository) GetFollowers(ctx context.Context, follows *[]domain.Follow, userID string, page *pagination.Page) (err error) {
+	return followRepository.list(ctx, follows, "followee_id", userID, domain.FollowStatusAccepted, "Follower", page)
+}
+
+func (followRepository *followRepository) GetFollowing(ctx context.Context, follows *[]domain.Follow, userID string, page *pagination.Page) (err error) {
+	return followRepository.list(ctx, follows, "follower_id", userID, domain.FollowStatusAccepted, "Followee", page)
+}
+
+func (followRepository *followRepository) GetRequests(ctx context.Context, follows *[]domain.Follow, userID string, page *pagination.Page) (err error) {
+	return followRepository.list(ctx, follows, "followee_id", userID, domain.FollowStatusPending, "Follower", page)
+}
+
+// Accept turns a pending follow into an accepted one.
+func (followRepository *followRepository) Accept(ctx context.Context, followerID string, followeeID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return followRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Follow{}).Where("follower_id = ? AND followee_id = ? AND status = ?", followerID, followeeID, domain.FollowStatusPending).Update("status", domain.FollowStatusAccepted)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return countFollows(tx, []string{followerID}, followeeID, 1)
+	})
+}
+
+func (followRepository *followRepository) Delete(ctx context.Context, followerID string, followeeID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return followRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		follow := domain.Follow{}
+
+		if err := tx.Clauses(clause.Returning{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&follow).Error; err != nil {
+			return err
+		}
+
+		if follow.ID == "" {
+			return gorm.ErrRecordNotFound
+		}
+
+		if follow.Status != domain.FollowStatusAccepted {
+			return nil
+		}
+
+		return countFollows(tx, []string{followerID}, followeeID, -1)
+	})
+}
+
+// SetPrivate changes whether the user is private. Going public accepts every
+// pending request and returns the followers whose requests were accepted.
+func (followRepository *followRepository) SetPrivate(ctx context.Context, userID string, private bool) (followerIDs []string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	err = followRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.User{}).Where("id = ?", userID).Update("private", private)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if private {
+			return nil
+		}
+
+		if err := tx.Model(&domain.Follow{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("followee_id = ? AND status = ?", userID, domain.FollowStatusPending).Pluck("follower_id", &followerIDs).Error; err != nil {
+			return err
+		}
+
+		if len(followerIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&domain.Follow{}).Where("followee_id = ? AND follower_id IN ?", userID, followerIDs).Update("status", domain.FollowStatusAccepted).Error; err != nil {
+			return err
+		}
+
+		return countFollows(tx, followerIDs, userID, 1)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return followerIDs, nil
+}
+
+func (followRepository *followRepository) list(ctx context.Context, follows *[]domain.Follow, column string, userID string, status string, user string, page *pagination.Page) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = page.Query(followRepository.db.WithContext(ctx).Where(column+" = ? AND status = ?", userID, status)).Preload(user, func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "profile_image_url", "private", "follower_count", "following_count")
+	}).Find(follows).Error; err != nil {
+		return err
+	}
+
+	pagination.Finish(page, follows, func(follow domain.Follow) (*time.Time, string) {
+		return follow.CreatedAt, follow.ID
+	})
+
+	return
+}
+
+// countFollows adds delta to the following count of every follower and
+// delta for each of them to the follower count of the followee.
+func countFollows(tx *gorm.DB, followerIDs []string, followeeID string, delta int) error {
+	if err := tx.Model(&domain.User{}).Where("id IN ?", followerIDs).UpdateColumn("following_count", gorm.Expr("following_count + ?", delta)).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&domain.User{}).Where("id = ?", followeeID).UpdateColumn("follower_count", gorm.Expr("follower_count + ?", delta*len(followerIDs))).Error
+}
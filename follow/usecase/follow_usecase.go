@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+
+	"gorm.io/gorm"
+)
+
+type followUseCase struct {
+	followRepository domain.FollowRepository
+	userRepository   domain.UserRepository
+	feedStore        domain.FeedStore
+}
+
+func NewFollowUseCase(followRepository domain.FollowRepository, userRepository domain.UserRepository, feedStore domain.FeedStore) *followUseCase {
+	return &followUseCase{followRepository, userRepository, feedStore}
+}
+
+// Follow makes the follower follow the followee right away, or asks to when
+// the followee is private, and returns the status of the follow. Following
+// someone again returns the existing status and fills in the feed again.
+func (followUseCase *followUseCase) Follow(ctx context.Context, followerID string, followeeID string) (status string, err error) {
+	if followerID == followeeID {
+		return "", domain.ErrSelfFollow
+	}
+
+	followee := domain.User{}
+
+	if err = followUseCase.userRepository.GetByID(ctx, &followee, followeeID); err != nil {
+		return "", err
+	}
+
+	follow := domain.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		Status:     domain.FollowStatusAccepted,
+	}
+
+	if followee.Private {
+		follow.Status = domain.FollowStatusPending
+	}
+
+	if err = followUseCase.followRepository.Create(ctx, &follow); err != nil {
+		return "", err
+	}
+
+	if follow.Status == domain.FollowStatusAccepted {
+		followUseCase.fillFeed(ctx, followerID, followeeID)
+	}
+
+	return follow.Status, nil
+}
+
+// Unfollow stops following the followee or withdraws the request to, and
+// takes the images of the followee out of the feed. It does nothing when
+// there is neither.
+func (followUseCase *followUseCase) Unfollow(ctx context.Context, followerID string, followeeID string) (err error) {
+	if err = followUseCase.followRepository.Delete(ctx, followerID, followeeID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err = followUseCase.feedStore.Unfollow(ctx, followerID, followeeID); err != nil {
+		log.Printf("removing user %s from the feed of user %s: %s", followeeID, followerID, err)
+	}
+
+	return nil
+}
+
+func (followUseCase *followUseCase) GetFollowers(ctx context.Context, follows *[]domain.Follow, viewerID string, userID string, page *pagination.Page) (err error) {
+	if err = followUseCase.canSeeFollows(ctx, viewerID, userID); err != nil {
+		return err
+	}
+
+	if err = followUseCase.followRepository.GetFollowers(ctx, follows, userID, page); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (followUseCase *followUseCase) GetFollowing(ctx context.Context, follows *[]domain.Follow, viewerID string, userID string, page *pagination.Page) (err error) {
+	if err = followUseCase.canSeeFollows(ctx, viewerID, userID); err != nil {
+		return err
+	}
+
+	if err = followUseCase.followRepository.GetFollowing(ctx, follows, userID, page); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (followUseCase *followUseCase) GetRequests(ctx context.Context, follows *[]domain.Follow, userID string, page *pagination.Page) (err error) {
+	if err = followUseCase.followRepository.GetRequests(ctx, follows, userID, page); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (followUseCase *followUseCase) Approve(ctx context.Context, followeeID string, followerID string) (err error) {
+	if err = followUseCase.followRepository.Accept(ctx, followerID, followeeID); err != nil {
+		return err
+	}
+
+	followUseCase.fillFeed(ctx, followerID, followeeID)
+
+	return
+}
+
+// Reject turns down a pending request. Accepted follows are left alone.
+func (followUseCase *followUseCase) Reject(ctx context.Context, followeeID string, followerID string) (err error) {
+	follow := domain.Follow{}
+
+	if err = followUseCase.followRepository.GetByUsers(ctx, &follow, followerID, followeeID); err != nil {
+		return err
+	}
+
+	if follow.Status != domain.FollowStatusPending {
+		return gorm.ErrRecordNotFound
+	}
+
+	if err = followUseCase.followRepository.Delete(ctx, followerID, followeeID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (followUseCase *followUseCase) SetPrivate(ctx context.Context, userID string, private bool) (err error) {
+	followerIDs, err := followUseCase.followRepository.SetPrivate(ctx, userID, private)
+
+	if err != nil {
+		return err
+	}
+
+	for _, followerID := range followerIDs {
+		followUseCase.fillFeed(ctx, followerID, userID)
+	}
+
+	return
+}
+
+// fillFeed puts the images of the followee into the feed of the follower
+// once the follow is accepted. The follow is already stored by then, so a
+// failure is only logged rather than failing the request.
+func (followUseCase *followUseCase) fillFeed(ctx context.Context, followerID string, followeeID string) {
+	if err := followUseCase.feedStore.Follow(ctx, followerID, followeeID); err != nil {
+		log.Printf("adding user %s to the feed of user %s: %s", followeeID, followerID, err)
+	}
+}
+
+// canSeeFollows lets anyone see the follows of a public account, but only
+// the account itself and its accepted followers see those of a private one.
+func (followUseCase *followUseCase) canSeeFollows(ctx context.Context, viewerID string, userID string) (err error) {
+	if viewerID == userID {
+		return nil
+	}
+
+	user := domain.User{}
+
+	if err = followUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return err
+	}
+
+	if !user.Private {
+		return nil
+	}
+
+	follow := domain.Follow{}
+
+	if err = followUseCase.followRepository.GetByUsers(ctx, &follow, viewerID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrPrivateAccount
+		}
+
+		return err
+	}
+
+	if follow.Status != domain.FollowStatusAccepted {
+		return domain.ErrPrivateAccount
+	}
+
+	return nil
+}
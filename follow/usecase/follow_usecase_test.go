@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/pagination"
+	"testing"
+
+	followUseCase "mygram-byferdiansyah/follow/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestFollow(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStore := new(mocks.FeedStore)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStore)
+
+	t.Run("follow a public account correctly", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-234").Return(nil).Once()
+		mockFollowRepository.On("Create", mock.Anything, mock.MatchedBy(func(follow *domain.Follow) bool {
+			return follow.FollowerID == "user-123" && follow.FolloweeID == "user-234" && follow.Status == domain.FollowStatusAccepted
+		})).Return(nil).Once()
+		mockFeedStore.On("Follow", mock.Anything, "user-123", "user-234").Return(nil).Once()
+
+		status, err := followUseCase.Follow(context.Background(), "user-123", "user-234")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.FollowStatusAccepted, status)
+		mockUserRepository.AssertExpectations(t)
+		mockFollowRepository.AssertExpectations(t)
+		mockFeedStore.AssertExpectations(t)
+	})
+
+	t.Run("follow a private account as a request", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-234").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.User).Private = true
+		}).Return(nil).Once()
+		mockFollowRepository.On("Create", mock.Anything, mock.MatchedBy(func(follow *domain.Follow) bool {
+			return follow.Status == domain.FollowStatusPending
+		})).Return(nil).Once()
+
+		status, err := followUseCase.Follow(context.Background(), "user-123", "user-234")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.FollowStatusPending, status)
+		mockUserRepository.AssertExpectations(t)
+		mockFollowRepository.AssertExpectations(t)
+	})
+
+	t.Run("follow yourself", func(t *testing.T) {
+		_, err := followUseCase.Follow(context.Background(), "user-123", "user-123")
+
+		assert.ErrorIs(t, err, domain.ErrSelfFollow)
+		mockUserRepository.AssertExpectations(t)
+		mockFollowRepository.AssertExpectations(t)
+	})
+}
+
+func TestUnfollow(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStore := new(mocks.FeedStore)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStore)
+
+	t.Run("unfollow someone you don't follow", func(t *testing.T) {
+		mockFollowRepository.On("Delete", mock.Anything, "user-123", "user-234").Return(gorm.ErrRecordNotFound).Once()
+		mockFeedStore.On("Unfollow", mock.Anything, "user-123", "user-234").Return(nil).Once()
+
+		err := followUseCase.Unfollow(context.Background(), "user-123", "user-234")
+
+		assert.NoError(t, err)
+		mockFollowRepository.AssertExpectations(t)
+		mockFeedStore.AssertExpectations(t)
+	})
+}
+
+func TestApprove(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStore := new(mocks.FeedStore)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStore)
+
+	t.Run("approve a request and fill the feed of the follower", func(t *testing.T) {
+		mockFollowRepository.On("Accept", mock.Anything, "user-234", "user-123").Return(nil).Once()
+		mockFeedStore.On("Follow", mock.Anything, "user-234", "user-123").Return(nil).Once()
+
+		err := followUseCase.Approve(context.Background(), "user-123", "user-234")
+
+		assert.NoError(t, err)
+		mockFollowRepository.AssertExpectations(t)
+		mockFeedStore.AssertExpectations(t)
+	})
+}
+
+func TestSetPrivate(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStore := new(mocks.FeedStore)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStore)
+
+	t.Run("go public and fill the feeds of the accepted followers", func(t *testing.T) {
+		mockFollowRepository.On("SetPrivate", mock.Anything, "user-123", false).Return([]string{"user-234", "user-345"}, nil).Once()
+		mockFeedStore.On("Follow", mock.Anything, "user-234", "user-123").Return(nil).Once()
+		mockFeedStore.On("Follow", mock.Anything, "user-345", "user-123").Return(errors.New("connection refused")).Once()
+
+		err := followUseCase.SetPrivate(context.Background(), "user-123", false)
+
+		assert.NoError(t, err)
+		mockFollowRepository.AssertExpectations(t)
+		mockFeedStore.AssertExpectations(t)
+	})
+}
+
+func TestGetFollowers(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStore := new(mocks.FeedStore)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStore)
+	page := &pagination.Page{Limit: pagination.DefaultLimit, Sort: pagination.SortDesc}
+
+	private := func(args mock.Arguments) {
+		args.Get(1).(*domain.User).Private = true
+	}
+
+	t.Run("get followers of a private account as a follower", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-234").Run(private).Return(nil).Once()
+		mockFollowRepository.On("GetByUsers", mock.Anything, mock.AnythingOfType("*domain.Follow"), "user-123", "user-234").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Follow).Status = domain.FollowStatusAccepted
+		}).Return(nil).Once()
+		mockFollowRepository.On("GetFollowers", mock.Anything, mock.AnythingOfType("*[]domain.Follow"), "user-234", page).Return(nil).Once()
+
+		err := followUseCase.GetFollowers(context.Background(), &[]domain.Follow{}, "user-123", "user-234", page)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockFollowRepository.AssertExpectations(t)
+	})
+
+	t.Run("get followers of a private account with a pending request", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-234").Run(private).Return(nil).Once()
+		mockFollowRepository.On("GetByUsers", mock.Anything, mock.AnythingOfType("*domain.Follow"), "user-123", "user-234").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Follow).Status = domain.FollowStatusPending
+		}).Return(nil).Once()
+
+		err := followUseCase.GetFollowers(context.Background(), &[]domain.Follow{}, "user-123", "user-234", page)
+
+		assert.ErrorIs(t, err, domain.ErrPrivateAccount)
+		mockUserRepository.AssertExpectations(t)
+		mockFollowRepository.AssertExpectations(t)
+	})
+
+	t.Run("get your own followers", func(t *testing.T) {
+		mockFollowRepository.On("GetFollowers", mock.Anything, mock.AnythingOfType("*[]domain.Follow"), "user-123", page).Return(nil).Once()
+
+		err := followUseCase.GetFollowers(context.Background(), &[]domain.Follow{}, "user-123", "user-123", page)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockFollowRepository.AssertExpectations(t)
+	})
+}
+
+func TestReject(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStore := new(mocks.FeedStore)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStore)
+
+	t.Run("reject a pending request correctly", func(t *testing.T) {
+		mockFollowRepository.On("GetByUsers", mock.Anything, mock.AnythingOfType("*domain.Follow"), "user-234", "user-123").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Follow).Status = domain.FollowStatusPending
+		}).Return(nil).Once()
+		mockFollowRepository.On("Delete", mock.Anything, "user-234", "user-123").Return(nil).Once()
+
+		err := followUseCase.Reject(context.Background(), "user-123", "user-234")
+
+		assert.NoError(t, err)
+		mockFollowRepository.AssertExpectations(t)
+	})
+
+	t.Run("reject an accepted follow", func(t *testing.T) {
+		mockFollowRepository.On("GetByUsers", mock.Anything, mock.AnythingOfType("*domain.Follow"), "user-234", "user-123").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Follow).Status = domain.FollowStatusAccepted
+		}).Return(nil).Once()
+
+		err := followUseCase.Reject(context.Background(), "user-123", "user-234")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockFollowRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,43 @@
+package utils
+
+import "time"
+
+type User struct {
+	ID              string `json:"id" example:"user-123"`
+	Username        string `json:"username" example:"johndoe"`
+	ProfileImageUrl string `json:"profile_image_url,omitempty" example:"https://www.example.com/image.jpg"`
+	Private         bool   `json:"private" example:"false"`
+	FollowerCount   int    `json:"follower_count" example:"1"`
+	FollowingCount  int    `json:"following_count" example:"1"`
+}
+
+type Follow struct {
+	User      *User      `json:"user"`
+	Status    string     `json:"status" example:"accepted"`
+	CreatedAt *time.Time `json:"created_at" example:"the created at generated here"`
+}
+
+type ResponseDataFollows struct {
+	Status     string    `json:"status" example:"success"`
+	Data       []*Follow `json:"data"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+}
+
+type FollowState struct {
+	Status string `json:"status" example:"pending"`
+}
+
+type ResponseDataFollowState struct {
+	Status string      `json:"status" example:"success"`
+	Data   FollowState `json:"data"`
+}
+
+type SetPrivacy struct {
+	Private *bool `json:"private" binding:"required" example:"true"`
+}
+
+type ResponseMessage struct {
+	Status string `json:"status" example:"fail"`
+	Data   string `json:"data" example:"the error explained here"`
+}
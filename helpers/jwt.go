@@ -2,58 +2,163 @@ package helpers
 
 import (
 	"errors"
-	"log"
-	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
-func GenerateToken(id string, email string) string {
+var errNoSigningKey = errors.New("no signing key is active yet")
+
+// TokenManager issues and verifies access tokens. Without signing keys it
+// signs them with key using HS256, so only holders of the key can verify
+// them. With signing keys it signs them with the newest active one, and a
+// key keeps verifying the tokens it signed for an access token lifetime
+// after the next one takes over.
+type TokenManager struct {
+	key         []byte
+	accessTTL   time.Duration
+	signingKeys []SigningKey
+}
+
+func NewTokenManager(key string, accessTTL time.Duration, signingKeys ...SigningKey) *TokenManager {
+	sorted := append([]SigningKey(nil), signingKeys...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom)
+	})
+
+	return &TokenManager{[]byte(key), accessTTL, sorted}
+}
+
+// AccessTTL is how long an access token issued by GenerateToken stays valid.
+func (tokenManager *TokenManager) AccessTTL() time.Duration {
+	return tokenManager.accessTTL
+}
+
+func (tokenManager *TokenManager) GenerateToken(id string, email string, roles []string) (string, error) {
+	jti, err := gonanoid.New(21)
+
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
 	claims := jwt.MapClaims{
 		"id":    id,
 		"email": email,
+		"roles": roles,
+		"jti":   jti,
+		"iat":   float64(now.UnixMilli()) / 1000,
+		"exp":   now.Add(tokenManager.accessTTL).Unix(),
+	}
+
+	if len(tokenManager.signingKeys) == 0 {
+		parseToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+		return parseToken.SignedString(tokenManager.key)
 	}
 
-	parseToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signingKey, ok := tokenManager.signingKey(now)
 
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file: ", err)
+	if !ok {
+		return "", errNoSigningKey
 	}
 
-	signedToken, _ := parseToken.SignedString([]byte(os.Getenv("TOKEN_KEY")))
+	parseToken := jwt.NewWithClaims(signingKey.method, claims)
+
+	parseToken.Header["kid"] = signingKey.ID
+
+	return parseToken.SignedString(signingKey.key)
+}
+
+// JWKS returns the public keys tokens can be verified with, including the
+// ones scheduled to sign tokens later so verifiers can fetch them ahead.
+func (tokenManager *TokenManager) JWKS() []JWK {
+	now := time.Now()
+	jwks := []JWK{}
+
+	for i, signingKey := range tokenManager.signingKeys {
+		if signingKey.ActiveFrom.After(now) || tokenManager.verifies(i, now) {
+			jwks = append(jwks, signingKey.JWK())
+		}
+	}
 
-	return signedToken
+	return jwks
 }
 
-func VerifyToken(ctx *gin.Context) (interface{}, error) {
+func (tokenManager *TokenManager) VerifyToken(ctx *gin.Context) (jwt.MapClaims, error) {
 	errResponse := errors.New("sign in to proceed")
 	headerToken := ctx.Request.Header.Get("Authorization")
-	bearer := strings.HasPrefix(headerToken, "Bearer")
 
-	if !bearer {
+	if !strings.HasPrefix(headerToken, "Bearer ") {
 		return nil, errResponse
 	}
 
-	stringToken := strings.Split(headerToken, " ")[1]
+	stringToken := strings.TrimPrefix(headerToken, "Bearer ")
 
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file: ", err)
-	}
+	token, err := jwt.Parse(stringToken, func(token *jwt.Token) (interface{}, error) {
+		if len(tokenManager.signingKeys) == 0 {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errResponse
+			}
+
+			return tokenManager.key, nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		now := time.Now()
 
-	token, _ := jwt.Parse(stringToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errResponse
+		for i, signingKey := range tokenManager.signingKeys {
+			if signingKey.ID == kid && token.Method.Alg() == signingKey.Algorithm() && tokenManager.verifies(i, now) {
+				return signingKey.key.Public(), nil
+			}
 		}
 
-		return []byte(os.Getenv("TOKEN_KEY")), nil
+		return nil, errResponse
 	})
 
-	if _, ok := token.Claims.(jwt.MapClaims); !ok && !token.Valid {
+	if err != nil || !token.Valid {
+		return nil, errResponse
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	// Tokens issued before expiry was introduced carry no exp claim and
+	// would otherwise stay valid forever.
+	if _, hasExpiry := claims["exp"]; !ok || !hasExpiry {
 		return nil, errResponse
 	}
 
-	return token.Claims.(jwt.MapClaims), nil
+	return claims, nil
+}
+
+// signingKey returns the newest of the signing keys that is active at now.
+func (tokenManager *TokenManager) signingKey(now time.Time) (SigningKey, bool) {
+	for i := len(tokenManager.signingKeys) - 1; i >= 0; i-- {
+		if !tokenManager.signingKeys[i].ActiveFrom.After(now) {
+			return tokenManager.signingKeys[i], true
+		}
+	}
+
+	return SigningKey{}, false
+}
+
+// verifies reports whether the i-th signing key still verifies tokens at
+// now. It does from when it becomes active until an access token lifetime
+// after the next key does.
+func (tokenManager *TokenManager) verifies(i int, now time.Time) bool {
+	if tokenManager.signingKeys[i].ActiveFrom.After(now) {
+		return false
+	}
+
+	if i+1 == len(tokenManager.signingKeys) {
+		return true
+	}
+
+	return now.Before(tokenManager.signingKeys[i+1].ActiveFrom.Add(tokenManager.accessTTL))
 }
@@ -0,0 +1,153 @@
+package helpers_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"mygram-byferdiansyah/helpers"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func pemKey(t *testing.T, key interface{}) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func ed25519Key(t *testing.T, id string, activeFrom time.Time) helpers.SigningKey {
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	signingKey, err := helpers.ParseSigningKey(id, pemKey(t, privateKey), activeFrom)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signingKey
+}
+
+func verify(tokenManager *helpers.TokenManager, token string) error {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer "+token)
+
+	_, err := tokenManager.VerifyToken(ctx)
+
+	return err
+}
+
+func header(t *testing.T, token string) map[string]interface{} {
+	data, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+
+	assert.NoError(t, err)
+
+	header := map[string]interface{}{}
+
+	assert.NoError(t, json.Unmarshal(data, &header))
+
+	return header
+}
+
+func TestTokenManager(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("sign and verify with RS256", func(t *testing.T) {
+		privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		signingKey, err := helpers.ParseSigningKey("rsa-1", pemKey(t, privateKey), time.Time{})
+
+		assert.NoError(t, err)
+
+		tokenManager := helpers.NewTokenManager("secret", time.Minute, signingKey)
+		token, err := tokenManager.GenerateToken("user-123", "johndoe@example.com", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "RS256", header(t, token)["alg"])
+		assert.Equal(t, "rsa-1", header(t, token)["kid"])
+		assert.NoError(t, verify(tokenManager, token))
+
+		jwks := tokenManager.JWKS()
+
+		assert.Len(t, jwks, 1)
+		assert.Equal(t, "RSA", jwks[0].KeyType)
+		assert.Equal(t, "AQAB", jwks[0].E)
+	})
+
+	t.Run("sign and verify with EdDSA", func(t *testing.T) {
+		tokenManager := helpers.NewTokenManager("secret", time.Minute, ed25519Key(t, "ed-1", time.Time{}))
+		token, err := tokenManager.GenerateToken("user-123", "johndoe@example.com", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EdDSA", header(t, token)["alg"])
+		assert.NoError(t, verify(tokenManager, token))
+		assert.Equal(t, "OKP", tokenManager.JWKS()[0].KeyType)
+		assert.Equal(t, "Ed25519", tokenManager.JWKS()[0].Curve)
+	})
+
+	t.Run("reject tokens signed with the shared key once signing keys are set", func(t *testing.T) {
+		token, _ := helpers.NewTokenManager("secret", time.Minute).GenerateToken("user-123", "johndoe@example.com", nil)
+
+		assert.Error(t, verify(helpers.NewTokenManager("secret", time.Minute, ed25519Key(t, "ed-1", time.Time{})), token))
+	})
+
+	t.Run("reject tokens with an unknown kid", func(t *testing.T) {
+		token, _ := helpers.NewTokenManager("secret", time.Minute, ed25519Key(t, "ed-1", time.Time{})).GenerateToken("user-123", "johndoe@example.com", nil)
+
+		assert.Error(t, verify(helpers.NewTokenManager("secret", time.Minute, ed25519Key(t, "ed-1", time.Time{})), token))
+	})
+
+	t.Run("rotate to the next key on schedule", func(t *testing.T) {
+		now := time.Now()
+		current := ed25519Key(t, "current", now.Add(-time.Hour))
+		next := ed25519Key(t, "next", now.Add(time.Hour))
+		tokenManager := helpers.NewTokenManager("secret", 15*time.Minute, next, current)
+
+		token, err := tokenManager.GenerateToken("user-123", "johndoe@example.com", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "current", header(t, token)["kid"])
+		assert.Len(t, tokenManager.JWKS(), 2)
+	})
+
+	t.Run("verify tokens of the previous key until they expire", func(t *testing.T) {
+		now := time.Now()
+		previous := ed25519Key(t, "previous", now.Add(-2*time.Hour))
+		token, _ := helpers.NewTokenManager("secret", 15*time.Minute, previous).GenerateToken("user-123", "johndoe@example.com", nil)
+
+		rotated := helpers.NewTokenManager("secret", 15*time.Minute, previous, ed25519Key(t, "current", now.Add(-time.Minute)))
+
+		assert.NoError(t, verify(rotated, token))
+		assert.Len(t, rotated.JWKS(), 2)
+
+		retired := helpers.NewTokenManager("secret", 15*time.Minute, previous, ed25519Key(t, "current", now.Add(-time.Hour)))
+
+		assert.Error(t, verify(retired, token))
+		assert.Len(t, retired.JWKS(), 1)
+		assert.Equal(t, "current", retired.JWKS()[0].ID)
+	})
+
+	t.Run("parse only private keys fit for signing", func(t *testing.T) {
+		weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+		_, err := helpers.ParseSigningKey("weak", pemKey(t, weak), time.Time{})
+
+		assert.ErrorContains(t, err, "at least 2048 bits")
+
+		publicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+		der, _ := x509.MarshalPKIXPublicKey(publicKey)
+		_, err = helpers.ParseSigningKey("public", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), time.Time{})
+
+		assert.ErrorContains(t, err, "not a private key")
+	})
+}
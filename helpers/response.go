@@ -1,8 +1,10 @@
 package helpers
 
 type ResponseData struct {
-	Status string      `json:"status"`
-	Data   interface{} `json:"data"`
+	Status     string      `json:"status"`
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
 }
 
 type ResponseMessage struct {
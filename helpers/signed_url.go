@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var ErrSignedURLInvalid = errors.New("the link is invalid or has expired")
+
+// URLSigner hands out links to stored files that stop working after ttl, so
+// files can be served without an Authorization header.
+type URLSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+func NewURLSigner(key string, ttl time.Duration) *URLSigner {
+	return &URLSigner{[]byte(key), ttl}
+}
+
+// Sign returns path with the expires and signature query parameters that
+// Verify checks.
+func (urlSigner *URLSigner) Sign(path string) string {
+	expires := strconv.FormatInt(time.Now().Add(urlSigner.ttl).Unix(), 10)
+
+	return fmt.Sprintf("%s?expires=%s&signature=%s", path, expires, url.QueryEscape(urlSigner.signature(path, expires)))
+}
+
+func (urlSigner *URLSigner) Verify(path string, expires string, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+
+	if err != nil || time.Now().Unix() > unix {
+		return ErrSignedURLInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(urlSigner.signature(path, expires))) {
+		return ErrSignedURLInvalid
+	}
+
+	return nil
+}
+
+func (urlSigner *URLSigner) signature(path string, expires string) string {
+	mac := hmac.New(sha256.New, urlSigner.key)
+
+	mac.Write([]byte(path + "\n" + expires))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,155 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// minRSAKeySize is the smallest RSA modulus, in bits, accepted for signing.
+const minRSAKeySize = 2048
+
+// SigningKey is a private key that signs access tokens from ActiveFrom until
+// a newer key becomes active. ID goes into the kid header of the tokens it
+// signs so verifiers can tell the keys apart.
+type SigningKey struct {
+	ID         string
+	ActiveFrom time.Time
+	key        crypto.Signer
+	method     jwt.SigningMethod
+}
+
+// JWK is the public half of a signing key in the JSON Web Key format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	ID        string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// ParseSigningKey reads a PEM encoded private key, which can be an RSA key
+// of at least 2048 bits to sign with RS256 or an Ed25519 key to sign with
+// EdDSA.
+func ParseSigningKey(id string, data []byte, activeFrom time.Time) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("signing key %s is not PEM encoded", id)
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return SigningKey{}, fmt.Errorf("signing key %s is a %s, not a private key", id, block.Type)
+	}
+
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("parsing signing key %s: %w", id, err)
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSAKeySize {
+			return SigningKey{}, fmt.Errorf("signing key %s must be at least %d bits", id, minRSAKeySize)
+		}
+
+		return SigningKey{id, activeFrom, key, jwt.SigningMethodRS256}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{id, activeFrom, key, SigningMethodEdDSA}, nil
+	}
+
+	return SigningKey{}, fmt.Errorf("signing key %s must be an RSA or Ed25519 key", id)
+}
+
+// Algorithm is the alg header of the tokens the key signs.
+func (signingKey SigningKey) Algorithm() string {
+	return signingKey.method.Alg()
+}
+
+// JWK returns the public key verifiers check the tokens signed by the key
+// with.
+func (signingKey SigningKey) JWK() JWK {
+	jwk := JWK{ID: signingKey.ID, Use: "sig", Algorithm: signingKey.Algorithm()}
+
+	switch public := signingKey.key.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return jwk
+}
+
+var errEdDSAKey = errors.New("EdDSA needs an Ed25519 key")
+
+// signingMethodEdDSA signs tokens with Ed25519, which jwt-go has no method
+// for.
+type signingMethodEdDSA struct{}
+
+var SigningMethodEdDSA jwt.SigningMethod = signingMethodEdDSA{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+func (signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+
+	if !ok {
+		return "", errEdDSAKey
+	}
+
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+func (signingMethodEdDSA) Verify(signingString string, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+
+	if !ok {
+		return errEdDSAKey
+	}
+
+	decoded, err := jwt.DecodeSegment(signature)
+
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), decoded) {
+		return jwt.ErrSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package helpers_test
+
+import (
+	"encoding/base32"
+	"mygram-byferdiansyah/helpers"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTP(t *testing.T) {
+	// The SHA-1 secret and vectors of RFC 6238 appendix B, cut to 6 digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	t.Run("generate the codes of the rfc", func(t *testing.T) {
+		for unix, code := range map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		} {
+			generated, err := helpers.TOTPCode(secret, time.Unix(unix, 0))
+
+			assert.NoError(t, err)
+			assert.Equal(t, code, generated)
+		}
+	})
+
+	t.Run("validate a code one step off", func(t *testing.T) {
+		step, ok := helpers.ValidateTOTP(secret, "287082", time.Unix(59+30, 0))
+
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), step)
+
+		_, ok = helpers.ValidateTOTP(secret, "287082", time.Unix(59+60, 0))
+
+		assert.False(t, ok)
+	})
+
+	t.Run("generate a secret authenticator apps accept", func(t *testing.T) {
+		secret, err := helpers.GenerateTOTPSecret()
+
+		assert.NoError(t, err)
+		assert.Len(t, secret, 32)
+
+		uri := helpers.TOTPURI("MyGram", "johndoe@example.com", secret)
+
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/MyGram:johndoe@example.com?"))
+		assert.Contains(t, uri, "secret="+secret)
+		assert.Contains(t, uri, "issuer=MyGram")
+	})
+}
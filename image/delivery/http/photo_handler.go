@@ -1,42 +1,59 @@
 package delivery
 
 import (
+	"errors"
+	"fmt"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/helpers"
-	"mygram-byferdiansyah/image/delivery/http/middleware"
 	"mygram-byferdiansyah/image/utils"
+	"mygram-byferdiansyah/middleware"
+	"mygram-byferdiansyah/pagination"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type imageHandler struct {
-	imageUseCase domain.ImageUseCase
+	imageUseCase  domain.ImageUseCase
+	likeUseCase   domain.LikeUseCase
+	urlSigner     *helpers.URLSigner
+	maxUploadSize int64
 }
 
-func NewImageHandler(routers *gin.Engine, imageUseCase domain.ImageUseCase) {
-	handler := &imageHandler{imageUseCase}
+func NewImageHandler(routers *gin.Engine, imageUseCase domain.ImageUseCase, likeUseCase domain.LikeUseCase, urlSigner *helpers.URLSigner, maxUploadSize int64, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, apiKeyUseCase domain.APIKeyUseCase, rateLimiter *middleware.RateLimiter, verifiedEmail gin.HandlerFunc) {
+	handler := &imageHandler{imageUseCase, likeUseCase, urlSigner, maxUploadSize}
 
 	router := routers.Group("/images")
 	{
-		router.Use(middleware.Authentication())
-		router.GET("", handler.Get)
-		router.POST("", handler.Create)
-		router.PUT("/:imageId", middleware.Authorization(handler.imageUseCase), handler.Edit)
-		router.DELETE("/:imageId", middleware.Authorization(handler.imageUseCase), handler.Delete)
+		router.Use(rateLimiter.LimitAddress(domain.RateLimitDefault))
+		router.Use(middleware.AuthenticationWithAPIKeys(tokenManager, tokenRevocationUseCase, apiKeyUseCase))
+		router.Use(rateLimiter.Limit(domain.RateLimitDefault))
+		router.GET("", middleware.RequireScope(domain.ScopeImagesRead), handler.Get)
+		router.GET("/feed", middleware.RequireScope(domain.ScopeImagesRead), handler.GetFeed)
+		router.POST("", middleware.RequireScope(domain.ScopeImagesWrite), verifiedEmail, rateLimiter.Limit(domain.RateLimitCreateImage), handler.Create)
+		router.GET("/:imageId", middleware.RequireScope(domain.ScopeImagesRead), handler.GetByID)
+		router.PUT("/:imageId", middleware.RequireScope(domain.ScopeImagesWrite), middleware.RequireOwner[domain.Image](handler.imageUseCase, "image", "imageId"), handler.Edit)
+		router.DELETE("/:imageId", middleware.RequireScope(domain.ScopeImagesWrite), middleware.RequireOwner[domain.Image](handler.imageUseCase, "image", "imageId"), handler.Delete)
 	}
 }
 
 // Get godoc
 // @Summary    	Get all images
-// @Description	Get all images with authentication user
+// @Description	Get all images with authentication user. The images of a private account are only shown to the account and its followers
 // @Tags        images
 // @Accept      json
 // @Produce     json
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       sort						query			string	false	"Order by creation time"	Enums(asc, desc)	default(desc)
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
+// @Param       user_id					query			string	false	"Only images posted by this user"
 // @Success     200			{object}	utils.ResponseDataGetedImage
 // @Failure     400			{object}	utils.ResponseMessage
 // @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /images	[get]
 func (handler *imageHandler) Get(ctx *gin.Context) {
@@ -45,7 +62,9 @@ func (handler *imageHandler) Get(ctx *gin.Context) {
 		err    error
 	)
 
-	if err = handler.imageUseCase.Get(ctx.Request.Context(), &images); err != nil {
+	page, err := pagination.Parse(ctx, "user_id")
+
+	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -54,40 +73,165 @@ func (handler *imageHandler) Get(ctx *gin.Context) {
 		return
 	}
 
+	if err = handler.imageUseCase.Get(ctx.Request.Context(), &images, page); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.markLiked(ctx, images); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
 	getsImages := []*utils.GetedImage{}
 
 	for _, image := range images {
-		getsImages = append(getsImages, &utils.GetedImage{
-			ID:        image.ID,
-			Title:     image.Title,
-			Caption:   image.Caption,
-			ImageUrl:  image.ImageUrl,
-			UserID:    image.UserID,
-			CreatedAt: image.CreatedAt,
-			UpdatedAt: image.UpdatedAt,
-			User: &utils.User{
-				Email:    image.User.Email,
-				Username: image.User.Username,
-			},
+		getsImages = append(getsImages, handler.geted(image))
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status:     "success",
+		Data:       getsImages,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+// GetByID godoc
+// @Summary    	Get an image
+// @Description	Get an image by id with authentication user. The images of a private account are only shown to the account and its followers
+// @Tags        images
+// @Accept      json
+// @Produce     json
+// @Param       imageId	path			string	true	"Image ID"
+// @Success     200			{object}	utils.ResponseDataGetedImageByID
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /images/{imageId}	[get]
+func (handler *imageHandler) GetByID(ctx *gin.Context) {
+	var (
+		image domain.Image
+		err   error
+	)
+
+	imageID := ctx.Param("imageId")
+
+	if err = handler.imageUseCase.GetByID(ctx.Request.Context(), &image, imageID); err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("image with id %s doesn't exist", imageID)
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
 		})
+
+		return
 	}
 
 	ctx.JSON(http.StatusOK, helpers.ResponseData{
 		Status: "success",
-		Data:   getsImages,
+		Data:   handler.geted(image),
+	})
+}
+
+// GetFeed godoc
+// @Summary    	Get the home feed
+// @Description	Get the images of the accounts authentication user follows, newest first
+// @Tags        images
+// @Accept      json
+// @Produce     json
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
+// @Success     200			{object}	utils.ResponseDataGetedImage
+// @Failure     400			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /images/feed	[get]
+func (handler *imageHandler) GetFeed(ctx *gin.Context) {
+	var (
+		images []domain.Image
+		err    error
+	)
+
+	page, err := pagination.Parse(ctx)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = handler.imageUseCase.GetFeed(ctx.Request.Context(), &images, principal.UserID, page); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.markLiked(ctx, images); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	feedImages := []*utils.GetedImage{}
+
+	for _, image := range images {
+		feedImages = append(feedImages, handler.geted(image))
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status:     "success",
+		Data:       feedImages,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
 	})
 }
 
 // Create godoc
 // @Summary    	Create a image
-// @Description	Create and create a image with authentication user
+// @Description	Upload and create a image with authentication user. A JSON body with an image_url is still accepted for images hosted elsewhere
 // @Tags        images
-// @Accept      json
+// @Accept      multipart/form-data
 // @Produce     json
-// @Param       json		body			utils.AddImage	true	"Add Image"
+// @Param       title		formData	string	true	"Title"
+// @Param       caption	formData	string	false	"Caption"
+// @Param       image		formData	file		true	"JPEG, PNG, GIF or WebP image"
 // @Success     201			{object}  utils.ResponseDataAddedImage
 // @Failure     400			{object}	utils.ResponseMessage
 // @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
+// @Failure     409			{object}	utils.ResponseMessage
+// @Failure     413			{object}	utils.ResponseMessage
+// @Failure     415			{object}	utils.ResponseMessage
+// @Failure     429			{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /images	[post]
 func (handler *imageHandler) Create(ctx *gin.Context) {
@@ -96,8 +240,13 @@ func (handler *imageHandler) Create(ctx *gin.Context) {
 		err   error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if ctx.ContentType() == "multipart/form-data" {
+		handler.upload(ctx, principal.UserID)
+
+		return
+	}
 
 	if err = ctx.ShouldBindJSON(&image); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -108,7 +257,8 @@ func (handler *imageHandler) Create(ctx *gin.Context) {
 		return
 	}
 
-	image.UserID = userID
+	image.UserID = principal.UserID
+	image.LikeCount = 0
 
 	if err = handler.imageUseCase.Create(ctx.Request.Context(), &image); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -119,15 +269,85 @@ func (handler *imageHandler) Create(ctx *gin.Context) {
 		return
 	}
 
+	handler.created(ctx, image)
+}
+
+func (handler *imageHandler) upload(ctx *gin.Context, userID string) {
+	// Leave room for the other form fields and the multipart boundaries.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, handler.maxUploadSize+1<<20)
+
+	fileHeader, err := ctx.FormFile("image")
+
+	if err != nil {
+		status := http.StatusBadRequest
+
+		var maxBytesError *http.MaxBytesError
+
+		if errors.As(err, &maxBytesError) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	defer file.Close()
+
+	image := domain.Image{
+		Title:   ctx.PostForm("title"),
+		Caption: ctx.PostForm("caption"),
+		UserID:  userID,
+	}
+
+	if err = handler.imageUseCase.Upload(ctx.Request.Context(), &image, file); err != nil {
+		status := http.StatusBadRequest
+
+		switch {
+		case errors.Is(err, domain.ErrImageTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, domain.ErrUnsupportedImageType):
+			status = http.StatusUnsupportedMediaType
+		case errors.Is(err, domain.ErrDuplicateImage):
+			status = http.StatusConflict
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	handler.created(ctx, image)
+}
+
+func (handler *imageHandler) created(ctx *gin.Context, image domain.Image) {
 	ctx.JSON(http.StatusCreated, helpers.ResponseData{
 		Status: "success",
 		Data: utils.AddedImage{
-			ID:        image.ID,
-			Title:     image.Title,
-			Caption:   image.Caption,
-			ImageUrl:  image.ImageUrl,
-			UserID:    image.UserID,
-			CreatedAt: image.CreatedAt,
+			ID:          image.ID,
+			Title:       image.Title,
+			Caption:     image.Caption,
+			ImageUrl:    handler.imageURL(image),
+			UserID:      image.UserID,
+			DuplicateOf: image.DuplicateOf,
+			CreatedAt:   image.CreatedAt,
 		},
 	})
 }
@@ -143,6 +363,7 @@ func (handler *imageHandler) Create(ctx *gin.Context) {
 // @Success     200		{object}  utils.ResponseDataEditedImage
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
 // @Failure     404		{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /images/{id}		[put]
@@ -184,7 +405,7 @@ func (handler *imageHandler) Edit(ctx *gin.Context) {
 			ID:        image.ID,
 			UserID:    image.UserID,
 			Title:     image.Title,
-			ImageUrl:  image.ImageUrl,
+			ImageUrl:  handler.imageURL(image),
 			Caption:   image.Caption,
 			UpdatedAt: image.UpdatedAt,
 		},
@@ -201,6 +422,7 @@ func (handler *imageHandler) Edit(ctx *gin.Context) {
 // @Success     200	{object}	utils.ResponseMessageDeletedImage
 // @Failure     400	{object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
 // @Failure     404	{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /images/{id}	[delete]
@@ -220,3 +442,78 @@ func (handler *imageHandler) Delete(ctx *gin.Context) {
 		"message": "your image has been successfully deleted",
 	})
 }
+
+// imageURL returns a signed link for uploaded images and the stored URL for
+// images hosted elsewhere.
+func (handler *imageHandler) imageURL(image domain.Image) string {
+	if image.ObjectKey != "" && image.ImageUrl == domain.FileURLPath(image.ObjectKey) {
+		return handler.urlSigner.Sign(image.ImageUrl)
+	}
+
+	return image.ImageUrl
+}
+
+// variantURLs maps the width of every variant of the image to a signed link.
+func (handler *imageHandler) variantURLs(image domain.Image) map[string]string {
+	if len(image.Variants) == 0 {
+		return nil
+	}
+
+	variants := make(map[string]string, len(image.Variants))
+
+	for _, variant := range image.Variants {
+		variants[variant.Name] = handler.urlSigner.Sign(domain.FileURLPath(variant.ObjectKey))
+	}
+
+	return variants
+}
+
+// markLiked sets LikedByMe on the images the caller has liked.
+func (handler *imageHandler) markLiked(ctx *gin.Context, images []domain.Image) error {
+	principal, _ := middleware.GetPrincipal(ctx)
+	ids := make([]string, 0, len(images))
+
+	for _, image := range images {
+		ids = append(ids, image.ID)
+	}
+
+	liked, err := handler.likeUseCase.GetLiked(ctx.Request.Context(), principal.UserID, domain.LikeTargetImage, ids)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range images {
+		images[i].LikedByMe = liked[images[i].ID]
+	}
+
+	return nil
+}
+
+// geted maps an image and its preloaded user to the response shape.
+func (handler *imageHandler) geted(image domain.Image) *utils.GetedImage {
+	geted := &utils.GetedImage{
+		ID:        image.ID,
+		Title:     image.Title,
+		Caption:   image.Caption,
+		ImageUrl:  handler.imageURL(image),
+		Width:     image.Width,
+		Height:    image.Height,
+		BlurHash:  image.BlurHash,
+		Variants:  handler.variantURLs(image),
+		LikeCount: image.LikeCount,
+		LikedByMe: image.LikedByMe,
+		UserID:    image.UserID,
+		CreatedAt: image.CreatedAt,
+		UpdatedAt: image.UpdatedAt,
+	}
+
+	if image.User != nil {
+		geted.User = &utils.User{
+			Email:    image.User.Email,
+			Username: image.User.Username,
+		}
+	}
+
+	return geted
+}
@@ -0,0 +1,126 @@
+package imaging
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+const base83 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// BlurHash encodes img as a BlurHash (https://blurha.sh) with x by y
+// components, which clients can render as a placeholder while the image
+// loads. The image is downscaled first since the hash only keeps the
+// lowest frequencies anyway.
+func BlurHash(img image.Image, x int, y int) string {
+	if bounds := img.Bounds(); bounds.Dx() > 32 {
+		img = Resize(img, 32)
+	} else {
+		rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+		img = rgba
+	}
+
+	rgba := img.(*image.RGBA)
+	width, height := rgba.Rect.Dx(), rgba.Rect.Dy()
+	factors := make([][3]float64, 0, x*y)
+
+	for j := 0; j < y; j++ {
+		for i := 0; i < x; i++ {
+			var factor [3]float64
+
+			normalisation := 2.0
+
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+
+			for py := 0; py < height; py++ {
+				for px := 0; px < width; px++ {
+					basis := normalisation * math.Cos(math.Pi*float64(i)*float64(px)/float64(width)) * math.Cos(math.Pi*float64(j)*float64(py)/float64(height))
+					pixel := rgba.RGBAAt(px, py)
+
+					factor[0] += basis * sRGBToLinear(pixel.R)
+					factor[1] += basis * sRGBToLinear(pixel.G)
+					factor[2] += basis * sRGBToLinear(pixel.B)
+				}
+			}
+
+			scale := 1 / float64(width*height)
+
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	var hash strings.Builder
+
+	hash.WriteString(encode83((x-1)+(y-1)*9, 1))
+
+	maximum := 1.0
+
+	if len(factors) > 1 {
+		actualMaximum := 0.0
+
+		for _, factor := range factors[1:] {
+			actualMaximum = math.Max(actualMaximum, math.Max(math.Abs(factor[0]), math.Max(math.Abs(factor[1]), math.Abs(factor[2]))))
+		}
+
+		quantisedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximum = float64(quantisedMaximum+1) / 166
+
+		hash.WriteString(encode83(quantisedMaximum, 1))
+	} else {
+		hash.WriteString(encode83(0, 1))
+	}
+
+	dc := factors[0]
+
+	hash.WriteString(encode83(linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4))
+
+	for _, factor := range factors[1:] {
+		hash.WriteString(encode83(quantise(factor[0], maximum)*19*19+quantise(factor[1], maximum)*19+quantise(factor[2], maximum), 2))
+	}
+
+	return hash.String()
+}
+
+func quantise(value float64, maximum float64) int {
+	signed := math.Copysign(math.Pow(math.Abs(value/maximum), 0.5), value)
+
+	return int(math.Max(0, math.Min(18, math.Floor(signed*9+9.5))))
+}
+
+func encode83(value int, length int) string {
+	encoded := make([]byte, length)
+
+	for i := length - 1; i >= 0; i-- {
+		encoded[i] = base83[value%83]
+		value /= 83
+	}
+
+	return string(encoded)
+}
+
+func sRGBToLinear(value uint8) float64 {
+	v := float64(value) / 255
+
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
@@ -0,0 +1,31 @@
+package imaging
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// DHash computes the 64 bit difference hash of img: the image is shrunk to
+// 9x8 grey pixels and every bit records whether a pixel is brighter than its
+// right neighbour. Resized or recompressed copies of an image end up a few
+// bits apart at most.
+func DHash(img image.Image) uint64 {
+	grey := image.NewGray(image.Rect(0, 0, 9, 8))
+
+	draw.CatmullRom.Scale(grey, grey.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+
+			if grey.GrayAt(x, y).Y > grey.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
@@ -0,0 +1,241 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+)
+
+const (
+	tagOrientation = 0x0112
+	tagGPSInfo     = 0x8825
+)
+
+// typeSizes is the size in bytes of one value of each TIFF field type.
+var typeSizes = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// tiff is the TIFF structure of the Exif metadata in a JPEG APP1 segment, a
+// PNG eXIf chunk or a WebP EXIF chunk. For PNG, chunk is the type and data
+// of the chunk and checksum the CRC following it.
+type tiff struct {
+	data     []byte
+	order    binary.ByteOrder
+	chunk    []byte
+	checksum []byte
+}
+
+// StripGPS returns a copy of the JPEG, PNG or WebP data with the GPS IFD of
+// its Exif metadata emptied and every GPS value zeroed. The length of the
+// data and the rest of the metadata, such as the orientation, are left
+// untouched. Anything that isn't one of those with Exif metadata is returned
+// as is.
+func StripGPS(data []byte) []byte {
+	stripped := append([]byte(nil), data...)
+	exif, ok := findExif(stripped)
+
+	if !ok {
+		return data
+	}
+
+	entry, ok := exif.find(exif.ifd0(), tagGPSInfo)
+
+	if !ok {
+		return data
+	}
+
+	gps := exif.order.Uint32(exif.data[entry+8:])
+
+	if uint64(gps)+2 > uint64(len(exif.data)) {
+		return data
+	}
+
+	count := uint32(exif.order.Uint16(exif.data[gps:]))
+
+	if uint64(gps)+2+uint64(count)*12 > uint64(len(exif.data)) {
+		return data
+	}
+
+	for i := uint32(0); i < count; i++ {
+		field := gps + 2 + i*12
+		size := typeSizes[exif.order.Uint16(exif.data[field+2:])] * exif.order.Uint32(exif.data[field+4:])
+
+		if offset := exif.order.Uint32(exif.data[field+8:]); size > 4 && uint64(offset)+uint64(size) <= uint64(len(exif.data)) {
+			zero(exif.data[offset : offset+size])
+		}
+
+		zero(exif.data[field : field+12])
+	}
+
+	exif.order.PutUint16(exif.data[gps:], 0)
+
+	if exif.checksum != nil {
+		binary.BigEndian.PutUint32(exif.checksum, crc32.ChecksumIEEE(exif.chunk))
+	}
+
+	return stripped
+}
+
+// Orientation returns the Exif orientation of the JPEG data, from 1 to 8, or
+// 1 when it has none.
+func Orientation(data []byte) int {
+	exif, ok := findJPEGExif(data)
+
+	if !ok {
+		return 1
+	}
+
+	entry, ok := exif.find(exif.ifd0(), tagOrientation)
+
+	if !ok {
+		return 1
+	}
+
+	if orientation := int(exif.order.Uint16(exif.data[entry+8:])); orientation >= 1 && orientation <= 8 {
+		return orientation
+	}
+
+	return 1
+}
+
+// findExif finds the Exif metadata of JPEG, PNG or WebP data. The returned
+// data shares memory with data.
+func findExif(data []byte) (tiff, bool) {
+	switch {
+	case bytes.HasPrefix(data, pngSignature):
+		return findPNGExif(data)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return findWebPExif(data)
+	}
+
+	return findJPEGExif(data)
+}
+
+// findJPEGExif walks the JPEG segments up to the start of the image data
+// looking for the APP1 Exif segment.
+func findJPEGExif(data []byte) (tiff, bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return tiff{}, false
+	}
+
+	for offset := 2; offset+4 <= len(data) && data[offset] == 0xFF; {
+		marker := data[offset+1]
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+
+		if marker == 0xDA || length < 2 || offset+2+length > len(data) {
+			break
+		}
+
+		segment := data[offset+4 : offset+2+length]
+
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return newTIFF(segment)
+		}
+
+		offset += 2 + length
+	}
+
+	return tiff{}, false
+}
+
+// findPNGExif walks the PNG chunks looking for the eXIf chunk.
+func findPNGExif(data []byte) (tiff, bool) {
+	for offset := len(pngSignature); offset+12 <= len(data); {
+		length := binary.BigEndian.Uint32(data[offset:])
+
+		if uint64(offset)+12+uint64(length) > uint64(len(data)) {
+			break
+		}
+
+		end := offset + 8 + int(length)
+
+		if string(data[offset+4:offset+8]) == "eXIf" {
+			exif, ok := newTIFF(data[offset+8 : end])
+			exif.chunk = data[offset+4 : end]
+			exif.checksum = data[end : end+4]
+
+			return exif, ok
+		}
+
+		offset = end + 4
+	}
+
+	return tiff{}, false
+}
+
+// findWebPExif walks the chunks of a WebP RIFF container looking for the
+// EXIF chunk.
+func findWebPExif(data []byte) (tiff, bool) {
+	for offset := 12; offset+8 <= len(data); {
+		size := binary.LittleEndian.Uint32(data[offset+4:])
+
+		if uint64(offset)+8+uint64(size) > uint64(len(data)) {
+			break
+		}
+
+		end := offset + 8 + int(size)
+
+		if string(data[offset:offset+4]) == "EXIF" {
+			return newTIFF(data[offset+8 : end])
+		}
+
+		offset = end + end%2
+	}
+
+	return tiff{}, false
+}
+
+// newTIFF reads the byte order of the TIFF structure in data, which may
+// start with the "Exif" header JPEG uses.
+func newTIFF(data []byte) (tiff, bool) {
+	exif := tiff{data: bytes.TrimPrefix(data, []byte("Exif\x00\x00"))}
+
+	if len(exif.data) < 8 {
+		return tiff{}, false
+	}
+
+	switch string(exif.data[:2]) {
+	case "II":
+		exif.order = binary.LittleEndian
+	case "MM":
+		exif.order = binary.BigEndian
+	default:
+		return tiff{}, false
+	}
+
+	return exif, true
+}
+
+func (exif tiff) ifd0() uint32 {
+	return exif.order.Uint32(exif.data[4:])
+}
+
+// find returns the offset of the entry for tag in the IFD at ifd.
+func (exif tiff) find(ifd uint32, tag uint16) (uint32, bool) {
+	if uint64(ifd)+2 > uint64(len(exif.data)) {
+		return 0, false
+	}
+
+	count := uint32(exif.order.Uint16(exif.data[ifd:]))
+
+	for i := uint32(0); i < count; i++ {
+		entry := ifd + 2 + i*12
+
+		if uint64(entry)+12 > uint64(len(exif.data)) {
+			return 0, false
+		}
+
+		if exif.order.Uint16(exif.data[entry:]) == tag {
+			return entry, true
+		}
+	}
+
+	return 0, false
+}
+
+func zero(data []byte) {
+	for i := range data {
+		data[i] = 0
+	}
+}
@@ -0,0 +1,144 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// maxPixels keeps a small file that declares huge dimensions from
+// exhausting memory when it is decoded.
+const maxPixels = 50_000_000
+
+var ErrTooManyPixels = errors.New("the image has too many pixels")
+
+// extensions lists the image types that can be uploaded, keyed by the MIME
+// type sniffed from their first bytes.
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Extension returns the file extension uploads of contentType are stored
+// with, or false when images of that type can't be uploaded.
+func Extension(contentType string) (string, bool) {
+	extension, ok := extensions[contentType]
+
+	return extension, ok
+}
+
+// Decode decodes a JPEG, PNG, GIF or WebP image, turned upright according to
+// its Exif orientation. The format is returned as registered by the image
+// package, e.g. "jpeg".
+func Decode(data []byte) (image.Image, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if config.Width*config.Height > maxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if format == "jpeg" {
+		img = orient(img, Orientation(data))
+	}
+
+	return img, format, nil
+}
+
+// Resize scales img down to width, keeping its aspect ratio.
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+
+	if height < 1 {
+		height = 1
+	}
+
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Src, nil)
+
+	return resized
+}
+
+// Encode encodes img as PNG when the original format may carry transparency
+// and as JPEG otherwise, returning the content type and file extension.
+func Encode(img image.Image, format string) ([]byte, string, string, error) {
+	var buffer bytes.Buffer
+
+	if format == "png" || format == "gif" {
+		if err := png.Encode(&buffer, img); err != nil {
+			return nil, "", "", err
+		}
+
+		return buffer.Bytes(), "image/png", ".png", nil
+	}
+
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", "", err
+	}
+
+	return buffer.Bytes(), "image/jpeg", ".jpg", nil
+}
+
+// orient applies the Exif orientation to img, see
+// https://www.exif.org/Exif2-2.PDF page 18 for the meaning of each value.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	rotated := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	if orientation >= 5 {
+		rotated = image.NewRGBA(image.Rect(0, 0, height, width))
+	}
+
+	for y := 0; y < rotated.Rect.Dy(); y++ {
+		for x := 0; x < rotated.Rect.Dx(); x++ {
+			var sourceX, sourceY int
+
+			switch orientation {
+			case 2:
+				sourceX, sourceY = width-1-x, y
+			case 3:
+				sourceX, sourceY = width-1-x, height-1-y
+			case 4:
+				sourceX, sourceY = x, height-1-y
+			case 5:
+				sourceX, sourceY = y, x
+			case 6:
+				sourceX, sourceY = y, height-1-x
+			case 7:
+				sourceX, sourceY = width-1-y, height-1-x
+			case 8:
+				sourceX, sourceY = width-1-y, x
+			}
+
+			rotated.Set(x, y, img.At(bounds.Min.X+sourceX, bounds.Min.Y+sourceY))
+		}
+	}
+
+	return rotated
+}
@@ -0,0 +1,201 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/bits"
+	"testing"
+
+	"mygram-byferdiansyah/image/imaging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var latitude = []byte{1, 0, 0, 0, 1, 0, 0, 0, 48, 0, 0, 0, 1, 0, 0, 0, 51, 0, 0, 0, 1, 0, 0, 0}
+
+// exifWithGPS returns Exif metadata carrying an orientation and a GPS
+// latitude.
+func exifWithGPS(orientation uint16) []byte {
+	le := binary.LittleEndian
+	exif := []byte("II*\x00\x08\x00\x00\x00")
+	exif = le.AppendUint16(exif, 2)
+	exif = append(le.AppendUint16(le.AppendUint16(exif, 0x0112), 3), 1, 0, 0, 0)
+	exif = append(le.AppendUint16(exif, orientation), 0, 0)
+	exif = append(le.AppendUint16(le.AppendUint16(exif, 0x8825), 4), 1, 0, 0, 0)
+	exif = le.AppendUint32(le.AppendUint32(exif, 38), 0)
+	exif = le.AppendUint16(exif, 1)
+	exif = append(le.AppendUint16(le.AppendUint16(exif, 0x0002), 5), 3, 0, 0, 0)
+	exif = le.AppendUint32(le.AppendUint32(exif, 56), 0)
+
+	return append(exif, latitude...)
+}
+
+// jpegWithExif returns a 4x2 JPEG carrying an orientation and a GPS latitude
+// in its Exif metadata.
+func jpegWithExif(t *testing.T, orientation uint16) []byte {
+	var encoded bytes.Buffer
+
+	assert.NoError(t, jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil))
+
+	segment := append([]byte("Exif\x00\x00"), exifWithGPS(orientation)...)
+	app1 := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(segment)+2))
+
+	return append(append(append([]byte{0xFF, 0xD8}, app1...), segment...), encoded.Bytes()[2:]...)
+}
+
+// pngWithExif returns a 4x2 PNG carrying a GPS latitude in an eXIf chunk
+// right after the header.
+func pngWithExif(t *testing.T) []byte {
+	var encoded bytes.Buffer
+
+	assert.NoError(t, png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 4, 2))))
+
+	exif := exifWithGPS(1)
+	chunk := append([]byte("eXIf"), exif...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk))
+	chunk = append(binary.BigEndian.AppendUint32(nil, uint32(len(exif))), chunk...)
+
+	// The signature and the IHDR chunk take up the first 33 bytes.
+	data := append([]byte(nil), encoded.Bytes()[:33]...)
+
+	return append(append(data, chunk...), encoded.Bytes()[33:]...)
+}
+
+// webpWithExif returns a 1x1 lossless WebP carrying a GPS latitude in an
+// EXIF chunk.
+func webpWithExif() []byte {
+	le := binary.LittleEndian
+	vp8x := append([]byte("VP8X"), 10, 0, 0, 0, 0x08, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	// A single pixel, with every prefix code holding a single symbol, padded
+	// to an even size.
+	vp8l := append([]byte("VP8L\x09\x00\x00\x00"), 0x2f, 0, 0, 0, 0, 0x88, 0x88, 0xfe, 0x07, 0)
+	exif := exifWithGPS(1)
+	chunks := append(append(append([]byte("WEBP"), vp8x...), vp8l...), append(le.AppendUint32([]byte("EXIF"), uint32(len(exif))), exif...)...)
+
+	return append(le.AppendUint32([]byte("RIFF"), uint32(len(chunks))), chunks...)
+}
+
+func TestStripGPS(t *testing.T) {
+	t.Run("strip gps and keep the orientation", func(t *testing.T) {
+		data := jpegWithExif(t, 6)
+
+		assert.True(t, bytes.Contains(data, latitude))
+
+		stripped := imaging.StripGPS(data)
+
+		assert.False(t, bytes.Contains(stripped, latitude))
+		assert.Len(t, stripped, len(data))
+		assert.Equal(t, 6, imaging.Orientation(stripped))
+		assert.True(t, bytes.Contains(data, latitude), "the input is left untouched")
+
+		_, _, err := imaging.Decode(stripped)
+
+		assert.NoError(t, err)
+	})
+
+	for name, data := range map[string][]byte{
+		"strip gps from the eXIf chunk of a png":  pngWithExif(t),
+		"strip gps from the EXIF chunk of a webp": webpWithExif(),
+	} {
+		data := data
+
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, bytes.Contains(data, latitude))
+
+			stripped := imaging.StripGPS(data)
+
+			assert.False(t, bytes.Contains(stripped, latitude))
+			assert.Len(t, stripped, len(data))
+
+			_, _, err := imaging.Decode(stripped)
+
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("strip gps leaves other formats alone", func(t *testing.T) {
+		data := []byte("GIF89a")
+
+		assert.Equal(t, data, imaging.StripGPS(data))
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("decode turns the image upright", func(t *testing.T) {
+		img, format, err := imaging.Decode(jpegWithExif(t, 6))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, image.Rect(0, 0, 2, 4), img.Bounds())
+	})
+
+	t.Run("resize keeps the aspect ratio", func(t *testing.T) {
+		img := imaging.Resize(image.NewRGBA(image.Rect(0, 0, 1200, 800)), 150)
+
+		assert.Equal(t, image.Rect(0, 0, 150, 100), img.Bounds())
+	})
+}
+
+func TestBlurHash(t *testing.T) {
+	t.Run("blurhash of a black image", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 255
+		}
+
+		assert.Equal(t, "L00000fQfQfQfQfQfQfQfQfQfQfQ", imaging.BlurHash(img, 4, 3))
+	})
+
+	t.Run("blurhash of a two colour image", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+
+		for y := 0; y < 48; y++ {
+			for x := 0; x < 64; x++ {
+				if x < 32 {
+					img.Set(x, y, color.RGBA{255, 0, 0, 255})
+				} else {
+					img.Set(x, y, color.RGBA{0, 0, 255, 255})
+				}
+			}
+		}
+
+		hash := imaging.BlurHash(img, 4, 3)
+
+		assert.Len(t, hash, 28)
+		assert.NotEqual(t, "fQ", hash[6:8], "the first horizontal component carries the split")
+	})
+}
+
+func TestDHash(t *testing.T) {
+	gradient := func(width int, height int) *image.RGBA {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				img.Set(x, y, color.RGBA{uint8(255 - x*255/width), uint8(y * 255 / height), 128, 255})
+			}
+		}
+
+		return img
+	}
+
+	t.Run("dhash of a resized copy is close", func(t *testing.T) {
+		original := imaging.DHash(gradient(640, 480))
+		resized := imaging.DHash(imaging.Resize(gradient(640, 480), 150))
+
+		assert.LessOrEqual(t, bits.OnesCount64(original^resized), 3)
+	})
+
+	t.Run("dhash of a different image is far", func(t *testing.T) {
+		original := imaging.DHash(gradient(640, 480))
+		blank := imaging.DHash(image.NewRGBA(image.Rect(0, 0, 640, 480)))
+
+		assert.Greater(t, bits.OnesCount64(original^blank), 3)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
+	"mygram-byferdiansyah/privacy"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -18,17 +20,21 @@ func NewImageRepository(db *gorm.DB) *imageRepository {
 	return &imageRepository{db}
 }
 
-func (imageRepository *imageRepository) Get(ctx context.Context, images *[]domain.Image) (err error) {
+func (imageRepository *imageRepository) Get(ctx context.Context, images *[]domain.Image, page *pagination.Page) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = imageRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+	if err = page.Query(imageRepository.db.WithContext(ctx).Scopes(privacy.Visible(ctx, "user_id"))).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "username", "email")
-	}).Find(&images).Error; err != nil {
+	}).Preload("Variants").Find(&images).Error; err != nil {
 		return err
 	}
 
+	pagination.Finish(page, images, func(image domain.Image) (*time.Time, string) {
+		return image.CreatedAt, image.ID
+	})
+
 	return
 }
 
@@ -53,7 +59,9 @@ func (imageRepository *imageRepository) GetByID(ctx context.Context, image *doma
 
 	defer cancel()
 
-	if err = imageRepository.db.WithContext(ctx).First(&image, &id).Error; err != nil {
+	if err = imageRepository.db.WithContext(ctx).Scopes(privacy.Visible(ctx, "user_id")).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email")
+	}).Preload("Variants").First(&image, &id).Error; err != nil {
 		return err
 	}
 
@@ -93,3 +101,104 @@ func (imageRepository *imageRepository) Delete(ctx context.Context, id string) (
 
 	return
 }
+
+// GetUnprocessed finds the uploaded images whose variants haven't been
+// generated yet, oldest first.
+func (imageRepository *imageRepository) GetUnprocessed(ctx context.Context, images *[]domain.Image) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = imageRepository.db.WithContext(ctx).Where("object_key <> '' AND processed_at IS NULL").Order("created_at").Find(images).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// SaveVariants replaces the variants of the image and records its
+// dimensions and BlurHash, marking it as processed.
+func (imageRepository *imageRepository) SaveVariants(ctx context.Context, image *domain.Image) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	now := time.Now()
+
+	err = imageRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("image_id = ?", image.ID).Delete(&domain.ImageVariant{}).Error; err != nil {
+			return err
+		}
+
+		for i := range image.Variants {
+			ID, _ := gonanoid.New(16)
+
+			image.Variants[i].ID = fmt.Sprintf("variant-%s", ID)
+			image.Variants[i].ImageID = image.ID
+		}
+
+		if len(image.Variants) > 0 {
+			if err := tx.Create(&image.Variants).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&domain.Image{}).Where("id = ?", image.ID).Updates(map[string]interface{}{
+			"width":        image.Width,
+			"height":       image.Height,
+			"blur_hash":    image.BlurHash,
+			"processed_at": now,
+		}).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	image.ProcessedAt = &now
+
+	return
+}
+
+// GetIDByContentHash returns the ID of an image of the user with the content
+// hash, or an empty string when there is none.
+func (imageRepository *imageRepository) GetIDByContentHash(ctx context.Context, userID string, hash string) (id string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	var ids []string
+
+	if err = imageRepository.db.WithContext(ctx).Model(&domain.Image{}).Where("user_id = ? AND content_hash = ?", userID, hash).Order("created_at").Limit(1).Pluck("id", &ids).Error; err != nil {
+		return "", err
+	}
+
+	if len(ids) > 0 {
+		id = ids[0]
+	}
+
+	return
+}
+
+// GetNearDuplicates pairs images across all users whose perceptual hashes are
+// at most distance bits apart, closest first. Only images sharing a hash band
+// are compared, which is exhaustive up to domain.MaxDuplicateDistance.
+func (imageRepository *imageRepository) GetNearDuplicates(ctx context.Context, duplicates *[]domain.ImageDuplicate, distance int) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	hamming := "length(replace(((a.p_hash # b.p_hash)::bit(64))::text, '0', ''))"
+
+	if err = imageRepository.db.WithContext(ctx).Raw(`
+		SELECT a.id AS image_id, a.user_id AS user_id, b.id AS duplicate_id, b.user_id AS duplicate_user_id, `+hamming+` AS distance
+		FROM images a
+		JOIN images b ON a.id < b.id AND (a.p_hash_band0 = b.p_hash_band0 OR a.p_hash_band1 = b.p_hash_band1 OR a.p_hash_band2 = b.p_hash_band2 OR a.p_hash_band3 = b.p_hash_band3)
+		WHERE a.p_hash IS NOT NULL AND b.p_hash IS NOT NULL AND `+hamming+` <= ?
+		ORDER BY distance, b.created_at DESC
+		LIMIT 100`, distance).Scan(duplicates).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -1,20 +1,45 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
 	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/image/imaging"
+	"mygram-byferdiansyah/pagination"
+	"net/http"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
 type imageUseCase struct {
 	imageRepository domain.ImageRepository
+	blobStore       domain.BlobStore
+	imageQueue      domain.ImageQueue
+	feedStore       domain.FeedStore
+	maxUploadSize   int64
+	duplicatePolicy string
+}
+
+func NewImageUseCase(imageRepository domain.ImageRepository, blobStore domain.BlobStore, imageQueue domain.ImageQueue, feedStore domain.FeedStore, maxUploadSize int64, duplicatePolicy string) *imageUseCase {
+	return &imageUseCase{imageRepository, blobStore, imageQueue, feedStore, maxUploadSize, duplicatePolicy}
 }
 
-func NewImageUseCase(imageRepository domain.ImageRepository) *imageUseCase {
-	return &imageUseCase{imageRepository}
+func (imageUseCase *imageUseCase) Get(ctx context.Context, images *[]domain.Image, page *pagination.Page) (err error) {
+	if err = imageUseCase.imageRepository.Get(ctx, images, page); err != nil {
+		return err
+	}
+
+	return
 }
 
-func (imageUseCase *imageUseCase) Get(ctx context.Context, images *[]domain.Image) (err error) {
-	if err = imageUseCase.imageRepository.Get(ctx, images); err != nil {
+// GetFeed gets the images of the accounts the user follows, newest first.
+func (imageUseCase *imageUseCase) GetFeed(ctx context.Context, images *[]domain.Image, userID string, page *pagination.Page) (err error) {
+	if err = imageUseCase.feedStore.Get(ctx, images, userID, page); err != nil {
 		return err
 	}
 
@@ -26,9 +51,96 @@ func (imageUseCase *imageUseCase) Create(ctx context.Context, image *domain.Imag
 		return err
 	}
 
+	imageUseCase.addToFeeds(ctx, image)
+
 	return
 }
 
+// Upload stores file, without its GPS location, and creates image pointing at
+// it. The type is sniffed from the content rather than trusted from the
+// client, and the stored file is removed again when the image cannot be
+// created. Variants are generated afterwards by the image queue.
+//
+// An image the user has already uploaded is refused with
+// domain.ErrDuplicateImage, or accepted with DuplicateOf set when the
+// duplicate policy is domain.DuplicatePolicyFlag.
+func (imageUseCase *imageUseCase) Upload(ctx context.Context, image *domain.Image, file io.Reader) (err error) {
+	content, err := io.ReadAll(io.LimitReader(file, imageUseCase.maxUploadSize+1))
+
+	if err != nil {
+		return err
+	}
+
+	if int64(len(content)) > imageUseCase.maxUploadSize {
+		return domain.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(content)
+	extension, ok := imaging.Extension(contentType)
+
+	if !ok {
+		return domain.ErrUnsupportedImageType
+	}
+
+	decoded, _, err := imaging.Decode(content)
+
+	if err != nil {
+		return domain.ErrUnsupportedImageType
+	}
+
+	content = imaging.StripGPS(content)
+
+	sum := sha256.Sum256(content)
+
+	image.ContentHash = hex.EncodeToString(sum[:])
+	image.SetPHash(imaging.DHash(decoded))
+
+	duplicateOf, err := imageUseCase.imageRepository.GetIDByContentHash(ctx, image.UserID, image.ContentHash)
+
+	if err != nil {
+		return err
+	}
+
+	if duplicateOf != "" {
+		if imageUseCase.duplicatePolicy != domain.DuplicatePolicyFlag {
+			return fmt.Errorf("%w as %s", domain.ErrDuplicateImage, duplicateOf)
+		}
+
+		image.DuplicateOf = duplicateOf
+	}
+
+	ID, _ := gonanoid.New(16)
+
+	image.ObjectKey = fmt.Sprintf("images/%s/%s%s", image.UserID, ID, extension)
+	image.ImageUrl = domain.FileURLPath(image.ObjectKey)
+	image.ContentType = contentType
+	image.Size = int64(len(content))
+
+	if err = imageUseCase.blobStore.Put(ctx, image.ObjectKey, bytes.NewReader(content), image.Size, contentType); err != nil {
+		return err
+	}
+
+	if err = imageUseCase.imageRepository.Create(ctx, image); err != nil {
+		imageUseCase.blobStore.Delete(ctx, image.ObjectKey)
+
+		return err
+	}
+
+	imageUseCase.imageQueue.Enqueue(image.ID)
+	imageUseCase.addToFeeds(ctx, image)
+
+	return
+}
+
+// addToFeeds puts a newly created image into the feeds of the followers of
+// its author. The image is already stored by then, so a failure is only
+// logged rather than failing the upload.
+func (imageUseCase *imageUseCase) addToFeeds(ctx context.Context, image *domain.Image) {
+	if err := imageUseCase.feedStore.Add(ctx, image); err != nil {
+		log.Printf("adding image %s to feeds: %s", image.ID, err)
+	}
+}
+
 func (imageUseCase *imageUseCase) GetByID(ctx context.Context, image *domain.Image, id string) (err error) {
 	if err = imageUseCase.imageRepository.GetByID(ctx, image, id); err != nil {
 		return err
@@ -46,9 +158,37 @@ func (imageUseCase *imageUseCase) Edit(ctx context.Context, image domain.Image,
 }
 
 func (imageUseCase *imageUseCase) Delete(ctx context.Context, id string) (err error) {
+	image := domain.Image{}
+
+	if err = imageUseCase.imageRepository.GetByID(ctx, &image, id); err != nil {
+		return err
+	}
+
 	if err = imageUseCase.imageRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	// The image is gone at this point, so a file that fails to delete is only
+	// left orphaned and doesn't fail the request.
+	if image.ObjectKey != "" {
+		imageUseCase.blobStore.Delete(ctx, image.ObjectKey)
+	}
+
+	for _, variant := range image.Variants {
+		imageUseCase.blobStore.Delete(ctx, variant.ObjectKey)
+	}
+
+	return
+}
+
+func (imageUseCase *imageUseCase) GetNearDuplicates(ctx context.Context, duplicates *[]domain.ImageDuplicate, distance int) (err error) {
+	if distance < 0 || distance > domain.MaxDuplicateDistance {
+		return domain.ErrDistanceOutOfRange
+	}
+
+	if err = imageUseCase.imageRepository.GetNearDuplicates(ctx, duplicates, distance); err != nil {
+		return err
+	}
+
 	return
 }
@@ -1,10 +1,15 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"image"
+	pngEncoder "image/png"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/pagination"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,17 +34,38 @@ func TestGet(t *testing.T) {
 	mockImages = append(mockImages, mockImage)
 
 	mockImageRepository := new(mocks.ImageRepository)
-	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	mockImageQueue := new(mocks.ImageQueue)
+	mockFeedStore := new(mocks.FeedStore)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, mockBlobStore, mockImageQueue, mockFeedStore, 1024, domain.DuplicatePolicyReject)
 
 	t.Run("get all images correctly", func(t *testing.T) {
-		mockImageRepository.On("Get", mock.Anything, mock.AnythingOfType("*[]domain.Image")).Return(nil).Once()
+		mockImageRepository.On("Get", mock.Anything, mock.AnythingOfType("*[]domain.Image"), mock.AnythingOfType("*pagination.Page")).Return(nil).Once()
 
-		err := imageUseCase.Get(context.Background(), &mockImages)
+		err := imageUseCase.Get(context.Background(), &mockImages, &pagination.Page{Limit: pagination.DefaultLimit})
 
 		assert.NoError(t, err)
 	})
 }
 
+func TestGetFeed(t *testing.T) {
+	mockImageRepository := new(mocks.ImageRepository)
+	mockFeedStore := new(mocks.FeedStore)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, new(mocks.BlobStore), new(mocks.ImageQueue), mockFeedStore, 1024, domain.DuplicatePolicyReject)
+
+	t.Run("get the feed of a user correctly", func(t *testing.T) {
+		page := &pagination.Page{Limit: pagination.DefaultLimit}
+
+		mockFeedStore.On("Get", mock.Anything, mock.AnythingOfType("*[]domain.Image"), "user-123", page).Return(nil).Once()
+
+		err := imageUseCase.GetFeed(context.Background(), &[]domain.Image{}, "user-123", page)
+
+		assert.NoError(t, err)
+		mockFeedStore.AssertExpectations(t)
+		mockImageRepository.AssertExpectations(t)
+	})
+}
+
 func TestCreate(t *testing.T) {
 	now := time.Now()
 	mockAddedImage := domain.Image{
@@ -52,7 +78,10 @@ func TestCreate(t *testing.T) {
 	}
 
 	mockImageRepository := new(mocks.ImageRepository)
-	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	mockImageQueue := new(mocks.ImageQueue)
+	mockFeedStore := new(mocks.FeedStore)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, mockBlobStore, mockImageQueue, mockFeedStore, 1024, domain.DuplicatePolicyReject)
 
 	t.Run("add image correctly", func(t *testing.T) {
 		tempMockAddImage := domain.Image{
@@ -64,6 +93,7 @@ func TestCreate(t *testing.T) {
 		tempMockAddImage.ID = "image-123"
 
 		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
+		mockFeedStore.On("Add", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
 
 		err := imageUseCase.Create(context.Background(), &tempMockAddImage)
 
@@ -89,6 +119,7 @@ func TestCreate(t *testing.T) {
 		tempMockAddImage.ID = "image-123"
 
 		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
+		mockFeedStore.On("Add", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
 
 		err := imageUseCase.Create(context.Background(), &tempMockAddImage)
 
@@ -114,6 +145,7 @@ func TestCreate(t *testing.T) {
 		tempMockAddImage.ID = "image-123"
 
 		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
+		mockFeedStore.On("Add", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
 
 		err := imageUseCase.Create(context.Background(), &tempMockAddImage)
 
@@ -138,6 +170,7 @@ func TestCreate(t *testing.T) {
 		tempMockAddImage.ID = "image-123"
 
 		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
+		mockFeedStore.On("Add", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
 
 		err := imageUseCase.Create(context.Background(), &tempMockAddImage)
 
@@ -152,6 +185,19 @@ func TestCreate(t *testing.T) {
 		assert.NotEqual(t, mockAddedImage.ImageUrl, tempMockAddImage.ImageUrl)
 		mockImageRepository.AssertExpectations(t)
 	})
+
+	t.Run("add image when the feeds can't be updated", func(t *testing.T) {
+		tempMockAddImage := mockAddedImage
+
+		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
+		mockFeedStore.On("Add", mock.Anything, &tempMockAddImage).Return(errors.New("connection refused")).Once()
+
+		err := imageUseCase.Create(context.Background(), &tempMockAddImage)
+
+		assert.NoError(t, err)
+		mockImageRepository.AssertExpectations(t)
+		mockFeedStore.AssertExpectations(t)
+	})
 }
 
 func TestGetBy(t *testing.T) {
@@ -169,7 +215,10 @@ func TestGetBy(t *testing.T) {
 	}
 
 	mockImageRepository := new(mocks.ImageRepository)
-	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	mockImageQueue := new(mocks.ImageQueue)
+	mockFeedStore := new(mocks.FeedStore)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, mockBlobStore, mockImageQueue, mockFeedStore, 1024, domain.DuplicatePolicyReject)
 
 	t.Run("get by id correctly", func(t *testing.T) {
 		mockImageID := "image-123"
@@ -208,7 +257,10 @@ func TestEdit(t *testing.T) {
 	}
 
 	mockImageRepository := new(mocks.ImageRepository)
-	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	mockImageQueue := new(mocks.ImageQueue)
+	mockFeedStore := new(mocks.FeedStore)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, mockBlobStore, mockImageQueue, mockFeedStore, 1024, domain.DuplicatePolicyReject)
 
 	t.Run("edit image correctly", func(t *testing.T) {
 		tempMockImageID := "image-123"
@@ -353,9 +405,13 @@ func TestDelete(t *testing.T) {
 	}
 
 	mockImageRepository := new(mocks.ImageRepository)
-	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	mockImageQueue := new(mocks.ImageQueue)
+	mockFeedStore := new(mocks.FeedStore)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, mockBlobStore, mockImageQueue, mockFeedStore, 1024, domain.DuplicatePolicyReject)
 
 	t.Run("delete image correctly", func(t *testing.T) {
+		mockImageRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), mock.AnythingOfType("string")).Return(nil).Once()
 		mockImageRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
 
 		err := imageUseCase.Delete(context.Background(), mockImage.ID)
@@ -365,11 +421,148 @@ func TestDelete(t *testing.T) {
 	})
 
 	t.Run("delete image with not found image", func(t *testing.T) {
-		mockImageRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
+		mockImageRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
 
 		err := imageUseCase.Delete(context.Background(), "image-234")
 
 		assert.Error(t, err)
 		mockImageRepository.AssertExpectations(t)
 	})
+
+	t.Run("delete uploaded image removes the file and its variants", func(t *testing.T) {
+		mockImageRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Image) = domain.Image{ID: "image-123", ObjectKey: "images/user-123/a.png", Variants: []domain.ImageVariant{{ObjectKey: "images/user-123/a_150.png"}}}
+		}).Return(nil).Once()
+		mockImageRepository.On("Delete", mock.Anything, "image-123").Return(nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "images/user-123/a.png").Return(nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "images/user-123/a_150.png").Return(nil).Once()
+
+		err := imageUseCase.Delete(context.Background(), "image-123")
+
+		assert.NoError(t, err)
+		mockImageRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+}
+
+func TestUpload(t *testing.T) {
+	var encoded bytes.Buffer
+
+	_ = pngEncoder.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+
+	png := encoded.Bytes()
+
+	mockImageRepository := new(mocks.ImageRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	mockImageQueue := new(mocks.ImageQueue)
+	mockFeedStore := new(mocks.FeedStore)
+	flaggingImageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, mockBlobStore, mockImageQueue, mockFeedStore, 1024, domain.DuplicatePolicyFlag)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, mockBlobStore, mockImageQueue, mockFeedStore, 1024, domain.DuplicatePolicyReject)
+
+	t.Run("upload image correctly", func(t *testing.T) {
+		image := domain.Image{Title: "A Title", UserID: "user-123"}
+
+		mockBlobStore.On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "images/user-123/") && strings.HasSuffix(key, ".png")
+		}), mock.Anything, int64(len(png)), "image/png").Return(nil).Once()
+		mockImageRepository.On("GetIDByContentHash", mock.Anything, "user-123", mock.AnythingOfType("string")).Return("", nil).Once()
+		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Image).ID = "image-123"
+		}).Return(nil).Once()
+		mockImageQueue.On("Enqueue", "image-123").Return().Once()
+		mockFeedStore.On("Add", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
+
+		err := imageUseCase.Upload(context.Background(), &image, bytes.NewReader(png))
+
+		assert.NoError(t, err)
+		mockImageQueue.AssertExpectations(t)
+		mockFeedStore.AssertExpectations(t)
+		assert.Equal(t, "image/png", image.ContentType)
+		assert.Equal(t, domain.FileURLPath(image.ObjectKey), image.ImageUrl)
+		assert.Len(t, image.ContentHash, 64)
+		assert.NotNil(t, image.PHash)
+		mockBlobStore.AssertExpectations(t)
+		mockImageRepository.AssertExpectations(t)
+	})
+
+	t.Run("upload file that isn't an image", func(t *testing.T) {
+		image := domain.Image{Title: "A Title", UserID: "user-123"}
+
+		err := imageUseCase.Upload(context.Background(), &image, strings.NewReader("<html><script>alert(1)</script></html>"))
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedImageType)
+	})
+
+	t.Run("upload image larger than the limit", func(t *testing.T) {
+		image := domain.Image{Title: "A Title", UserID: "user-123"}
+
+		err := imageUseCase.Upload(context.Background(), &image, bytes.NewReader(append(png, make([]byte, 1024)...)))
+
+		assert.ErrorIs(t, err, domain.ErrImageTooLarge)
+	})
+
+	t.Run("upload image removes the file when the image cannot be created", func(t *testing.T) {
+		image := domain.Image{UserID: "user-123"}
+
+		mockImageRepository.On("GetIDByContentHash", mock.Anything, "user-123", mock.AnythingOfType("string")).Return("", nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, int64(len(png)), "image/png").Return(nil).Once()
+		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(errors.New("title: non zero value required")).Once()
+		mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
+
+		err := imageUseCase.Upload(context.Background(), &image, bytes.NewReader(png))
+
+		assert.Error(t, err)
+		mockBlobStore.AssertExpectations(t)
+		mockImageRepository.AssertExpectations(t)
+	})
+
+	t.Run("upload image the user has already uploaded", func(t *testing.T) {
+		image := domain.Image{Title: "A Title", UserID: "user-123"}
+
+		mockImageRepository.On("GetIDByContentHash", mock.Anything, "user-123", mock.AnythingOfType("string")).Return("image-123", nil).Once()
+
+		err := imageUseCase.Upload(context.Background(), &image, bytes.NewReader(png))
+
+		assert.ErrorIs(t, err, domain.ErrDuplicateImage)
+		assert.ErrorContains(t, err, "image-123")
+		mockImageRepository.AssertExpectations(t)
+	})
+
+	t.Run("upload image the user has already uploaded with the flag policy", func(t *testing.T) {
+		image := domain.Image{Title: "A Title", UserID: "user-123"}
+
+		mockImageRepository.On("GetIDByContentHash", mock.Anything, "user-123", mock.AnythingOfType("string")).Return("image-123", nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, int64(len(png)), "image/png").Return(nil).Once()
+		mockImageRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.Image")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Image).ID = "image-234"
+		}).Return(nil).Once()
+		mockImageQueue.On("Enqueue", "image-234").Return().Once()
+		mockFeedStore.On("Add", mock.Anything, mock.AnythingOfType("*domain.Image")).Return(nil).Once()
+
+		err := flaggingImageUseCase.Upload(context.Background(), &image, bytes.NewReader(png))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image-123", image.DuplicateOf)
+		mockImageRepository.AssertExpectations(t)
+	})
+}
+
+func TestGetNearDuplicates(t *testing.T) {
+	mockImageRepository := new(mocks.ImageRepository)
+	imageUseCase := imageUseCase.NewImageUseCase(mockImageRepository, new(mocks.BlobStore), new(mocks.ImageQueue), new(mocks.FeedStore), 1024, domain.DuplicatePolicyReject)
+
+	t.Run("get near duplicates correctly", func(t *testing.T) {
+		mockImageRepository.On("GetNearDuplicates", mock.Anything, mock.AnythingOfType("*[]domain.ImageDuplicate"), 2).Return(nil).Once()
+
+		err := imageUseCase.GetNearDuplicates(context.Background(), &[]domain.ImageDuplicate{}, 2)
+
+		assert.NoError(t, err)
+		mockImageRepository.AssertExpectations(t)
+	})
+
+	t.Run("get near duplicates with a distance out of range", func(t *testing.T) {
+		err := imageUseCase.GetNearDuplicates(context.Background(), &[]domain.ImageDuplicate{}, 4)
+
+		assert.ErrorIs(t, err, domain.ErrDistanceOutOfRange)
+	})
 }
@@ -10,19 +10,32 @@ type User struct {
 }
 
 type GetedImage struct {
-	ID        string     `json:"id"`
-	Title     string     `json:"title,"`
-	Caption   string     `json:"caption"`
-	ImageUrl  string     `json:"image_url"`
-	UserID    string     `json:"user_id"`
-	CreatedAt *time.Time `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
-	User      *User      `json:"user"`
+	ID        string            `json:"id"`
+	Title     string            `json:"title,"`
+	Caption   string            `json:"caption"`
+	ImageUrl  string            `json:"image_url"`
+	Width     int               `json:"width,omitempty" example:"1080"`
+	Height    int               `json:"height,omitempty" example:"720"`
+	BlurHash  string            `json:"blur_hash,omitempty" example:"LKO2?U%2Tw=w]~RBVZRi};RPxuwH"`
+	Variants  map[string]string `json:"variants,omitempty"`
+	LikeCount int               `json:"like_count" example:"3"`
+	LikedByMe bool              `json:"liked_by_me"`
+	UserID    string            `json:"user_id"`
+	CreatedAt *time.Time        `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at"`
+	User      *User             `json:"user"`
 }
 
 type ResponseDataGetedImage struct {
-	Status string       `json:"status" example:"success"`
-	Data   []GetedImage `json:"data"`
+	Status     string       `json:"status" example:"success"`
+	Data       []GetedImage `json:"data"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	PrevCursor string       `json:"prev_cursor,omitempty"`
+}
+
+type ResponseDataGetedImageByID struct {
+	Status string     `json:"status" example:"success"`
+	Data   GetedImage `json:"data"`
 }
 
 type AddImage struct {
@@ -32,12 +45,13 @@ type AddImage struct {
 }
 
 type AddedImage struct {
-	ID        string     `json:"id"`
-	Title     string     `json:"title"`
-	Caption   string     `json:"caption"`
-	ImageUrl  string     `json:"image_url"`
-	UserID    string     `json:"user_id"`
-	CreatedAt *time.Time `json:"created_at"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Caption     string     `json:"caption"`
+	ImageUrl    string     `json:"image_url"`
+	UserID      string     `json:"user_id"`
+	DuplicateOf string     `json:"duplicate_of,omitempty" example:"image-123"`
+	CreatedAt   *time.Time `json:"created_at"`
 }
 
 type ResponseDataAddedImage struct {
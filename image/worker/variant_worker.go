@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/image/imaging"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Widths are the widths of the variants generated for every uploaded image.
+// Widths that aren't smaller than the original are skipped, so clients fall
+// back to the original for those.
+var Widths = []int{150, 640, 1080}
+
+type variantWorker struct {
+	imageRepository domain.ImageRepository
+	blobStore       domain.BlobStore
+	queue           chan string
+}
+
+func NewVariantWorker(imageRepository domain.ImageRepository, blobStore domain.BlobStore, queueSize int) *variantWorker {
+	return &variantWorker{imageRepository, blobStore, make(chan string, queueSize)}
+}
+
+// Enqueue schedules the image for processing without blocking the upload.
+// When the queue is full the image is left unprocessed and picked up again
+// the next time Run starts.
+func (variantWorker *variantWorker) Enqueue(imageID string) {
+	select {
+	case variantWorker.queue <- imageID:
+	default:
+		log.Printf("variant queue is full, image %s will be processed on the next start", imageID)
+	}
+}
+
+// Run first processes the images left unprocessed by a previous run and then
+// the queued ones, until ctx is done.
+func (variantWorker *variantWorker) Run(ctx context.Context) {
+	var pending []domain.Image
+
+	if err := variantWorker.imageRepository.GetUnprocessed(ctx, &pending); err != nil {
+		log.Printf("loading unprocessed images: %s", err)
+	}
+
+	for _, image := range pending {
+		variantWorker.process(ctx, image.ID)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case imageID := <-variantWorker.queue:
+			variantWorker.process(ctx, imageID)
+		}
+	}
+}
+
+func (variantWorker *variantWorker) process(ctx context.Context, imageID string) {
+	if err := variantWorker.Process(ctx, imageID); err != nil {
+		log.Printf("processing image %s: %s", imageID, err)
+	}
+}
+
+// Process generates the variants, dimensions and BlurHash of the image. An
+// image that cannot be decoded is still marked as processed, without
+// variants, so it isn't retried on every start.
+func (variantWorker *variantWorker) Process(ctx context.Context, imageID string) (err error) {
+	image := domain.Image{}
+
+	if err = variantWorker.imageRepository.GetByID(ctx, &image, imageID); err != nil {
+		return err
+	}
+
+	body, err := variantWorker.blobStore.Get(ctx, image.ObjectKey)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(body)
+
+	body.Close()
+
+	if err != nil {
+		return err
+	}
+
+	decoded, format, err := imaging.Decode(data)
+
+	if err != nil {
+		image.Variants = nil
+
+		if saveErr := variantWorker.imageRepository.SaveVariants(ctx, &image); saveErr != nil {
+			return saveErr
+		}
+
+		return err
+	}
+
+	bounds := decoded.Bounds()
+
+	image.Width = bounds.Dx()
+	image.Height = bounds.Dy()
+	image.BlurHash = imaging.BlurHash(decoded, 4, 3)
+	image.Variants = nil
+
+	base := strings.TrimSuffix(image.ObjectKey, path.Ext(image.ObjectKey))
+
+	for _, width := range Widths {
+		if width >= image.Width {
+			continue
+		}
+
+		resized := imaging.Resize(decoded, width)
+
+		encoded, contentType, extension, err := imaging.Encode(resized, format)
+
+		if err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("%s_%d%s", base, width, extension)
+
+		if err = variantWorker.blobStore.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), contentType); err != nil {
+			return err
+		}
+
+		image.Variants = append(image.Variants, domain.ImageVariant{
+			Name:        strconv.Itoa(width),
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+			ObjectKey:   key,
+			ContentType: contentType,
+			Size:        int64(len(encoded)),
+		})
+	}
+
+	return variantWorker.imageRepository.SaveVariants(ctx, &image)
+}
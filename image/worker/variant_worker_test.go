@@ -0,0 +1,96 @@
+package worker_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"testing"
+
+	"mygram-byferdiansyah/image/worker"
+	blobStore "mygram-byferdiansyah/storage/local"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("process image correctly", func(t *testing.T) {
+		var encoded bytes.Buffer
+
+		img := image.NewRGBA(image.Rect(0, 0, 800, 600))
+
+		for i := range img.Pix {
+			img.Pix[i] = 255
+		}
+
+		img.Set(10, 10, color.RGBA{255, 0, 0, 255})
+
+		assert.NoError(t, png.Encode(&encoded, img))
+
+		store := blobStore.NewBlobStore(t.TempDir())
+
+		assert.NoError(t, store.Put(ctx, "images/user-123/a.png", bytes.NewReader(encoded.Bytes()), int64(encoded.Len()), "image/png"))
+
+		mockImageRepository := new(mocks.ImageRepository)
+		variantWorker := worker.NewVariantWorker(mockImageRepository, store, 1)
+
+		var saved domain.Image
+
+		mockImageRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Image) = domain.Image{ID: "image-123", ObjectKey: "images/user-123/a.png"}
+		}).Return(nil).Once()
+		mockImageRepository.On("SaveVariants", mock.Anything, mock.AnythingOfType("*domain.Image")).Run(func(args mock.Arguments) {
+			saved = *args.Get(1).(*domain.Image)
+		}).Return(nil).Once()
+
+		err := variantWorker.Process(ctx, "image-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 800, saved.Width)
+		assert.Equal(t, 600, saved.Height)
+		assert.Len(t, saved.BlurHash, 28)
+		assert.Len(t, saved.Variants, 2, "the 1080 variant would be larger than the original")
+		assert.Equal(t, "150", saved.Variants[0].Name)
+		assert.Equal(t, 112, saved.Variants[0].Height)
+		assert.Equal(t, "images/user-123/a_640.png", saved.Variants[1].ObjectKey)
+
+		body, err := store.Get(ctx, saved.Variants[1].ObjectKey)
+
+		assert.NoError(t, err)
+
+		config, format, err := image.DecodeConfig(body)
+		body.Close()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, 640, config.Width)
+		mockImageRepository.AssertExpectations(t)
+	})
+
+	t.Run("process image that cannot be decoded", func(t *testing.T) {
+		store := blobStore.NewBlobStore(t.TempDir())
+
+		assert.NoError(t, store.Put(ctx, "images/user-123/b.png", bytes.NewReader([]byte("\x89PNG\r\n\x1a\ngarbage")), 16, "image/png"))
+
+		mockImageRepository := new(mocks.ImageRepository)
+		variantWorker := worker.NewVariantWorker(mockImageRepository, store, 1)
+
+		mockImageRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-234").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Image) = domain.Image{ID: "image-234", ObjectKey: "images/user-123/b.png"}
+		}).Return(nil).Once()
+		mockImageRepository.On("SaveVariants", mock.Anything, mock.MatchedBy(func(image *domain.Image) bool {
+			return len(image.Variants) == 0
+		})).Return(nil).Once()
+
+		err := variantWorker.Process(ctx, "image-234")
+
+		assert.Error(t, err)
+		mockImageRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,129 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/like/utils"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type likeHandler struct {
+	likeUseCase domain.LikeUseCase
+}
+
+func NewLikeHandler(routers *gin.Engine, likeUseCase domain.LikeUseCase, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, rateLimiter *middleware.RateLimiter) {
+	handler := &likeHandler{likeUseCase}
+
+	authentication := middleware.Authentication(tokenManager, tokenRevocationUseCase)
+	addressLimit := rateLimiter.LimitAddress(domain.RateLimitDefault)
+	rateLimit := rateLimiter.Limit(domain.RateLimitDefault)
+
+	routers.PUT("/images/:imageId/like", addressLimit, authentication, rateLimit, handler.LikeImage)
+	routers.DELETE("/images/:imageId/like", addressLimit, authentication, rateLimit, handler.UnlikeImage)
+	routers.PUT("/comments/:commentId/like", addressLimit, authentication, rateLimit, handler.LikeComment)
+	routers.DELETE("/comments/:commentId/like", addressLimit, authentication, rateLimit, handler.UnlikeComment)
+}
+
+// LikeImage godoc
+// @Summary			Like an image
+// @Description	Like an image with authentication user. Liking an image twice changes nothing. The images of a private account can only be liked by the account and its followers
+// @Tags        likes
+// @Accept      json
+// @Produce     json
+// @Param       imageId	path			string	true	"Image ID"
+// @Success     200			{object}	utils.ResponseDataLike
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /images/{imageId}/like	[put]
+func (handler *likeHandler) LikeImage(ctx *gin.Context) {
+	handler.respond(ctx, domain.LikeTargetImage, "imageId", true, handler.likeUseCase.Like)
+}
+
+// UnlikeImage godoc
+// @Summary			Unlike an image
+// @Description	Take back the like of an image with authentication user. Unliking an image that isn't liked changes nothing
+// @Tags        likes
+// @Accept      json
+// @Produce     json
+// @Param       imageId	path			string	true	"Image ID"
+// @Success     200			{object}	utils.ResponseDataLike
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     404			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /images/{imageId}/like	[delete]
+func (handler *likeHandler) UnlikeImage(ctx *gin.Context) {
+	handler.respond(ctx, domain.LikeTargetImage, "imageId", false, handler.likeUseCase.Unlike)
+}
+
+// LikeComment godoc
+// @Summary			Like a comment
+// @Description	Like a comment with authentication user. Liking a comment twice changes nothing. Comments on the images of a private account can only be liked by the account and its followers
+// @Tags        likes
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path			string	true	"Comment ID"
+// @Success     200				{object}	utils.ResponseDataLike
+// @Failure     401				{object}	utils.ResponseMessage
+// @Failure     404				{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /comments/{commentId}/like	[put]
+func (handler *likeHandler) LikeComment(ctx *gin.Context) {
+	handler.respond(ctx, domain.LikeTargetComment, "commentId", true, handler.likeUseCase.Like)
+}
+
+// UnlikeComment godoc
+// @Summary			Unlike a comment
+// @Description	Take back the like of a comment with authentication user. Unliking a comment that isn't liked changes nothing
+// @Tags        likes
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path			string	true	"Comment ID"
+// @Success     200				{object}	utils.ResponseDataLike
+// @Failure     401				{object}	utils.ResponseMessage
+// @Failure     404				{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /comments/{commentId}/like	[delete]
+func (handler *likeHandler) UnlikeComment(ctx *gin.Context) {
+	handler.respond(ctx, domain.LikeTargetComment, "commentId", false, handler.likeUseCase.Unlike)
+}
+
+// respond applies a like or an unlike of the target named by the URL
+// parameter param and answers with the new state of the like.
+func (handler *likeHandler) respond(ctx *gin.Context, targetType string, param string, liked bool, apply func(context.Context, string, string, string) (int, error)) {
+	principal, _ := middleware.GetPrincipal(ctx)
+	targetID := ctx.Param(param)
+
+	count, err := apply(ctx.Request.Context(), principal.UserID, targetType, targetID)
+
+	if err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("%s with id %s doesn't exist", targetType, targetID)
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.Like{
+			Liked:     liked,
+			LikeCount: count,
+		},
+	})
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/privacy"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeTarget is where the likes of a kind of target are counted, which
+// column of a like points at it and which of them a user may see.
+type likeTarget struct {
+	table   string
+	column  string
+	visible func(context.Context) func(*gorm.DB) *gorm.DB
+}
+
+var likeTargets = map[string]likeTarget{
+	domain.LikeTargetImage: {"images", "image_id", func(ctx context.Context) func(*gorm.DB) *gorm.DB {
+		return privacy.Visible(ctx, "user_id")
+	}},
+	domain.LikeTargetComment: {"comments", "comment_id", func(ctx context.Context) func(*gorm.DB) *gorm.DB {
+		return privacy.VisibleImages(ctx, "image_id")
+	}},
+}
+
+type likeRepository struct {
+	db *gorm.DB
+}
+
+func NewLikeRepository(db *gorm.DB) *likeRepository {
+	return &likeRepository{db}
+}
+
+// Like records the like and bumps the counter of the target in the same
+// transaction. Liking a target twice changes nothing, and targets of private
+// accounts the user doesn't follow are treated as missing.
+func (likeRepository *likeRepository) Like(ctx context.Context, userID string, targetType string, targetID string) (count int, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	target, ok := likeTargets[targetType]
+
+	if !ok {
+		return 0, domain.ErrInvalidLikeTarget
+	}
+
+	err = likeRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := likeCount(tx.Scopes(target.visible(ctx)), target, targetID); err != nil {
+			return err
+		}
+
+		ID, _ := gonanoid.New(16)
+
+		like := domain.Like{ID: fmt.Sprintf("like-%s", ID), UserID: userID}
+
+		if targetType == domain.LikeTargetImage {
+			like.ImageID = &targetID
+		} else {
+			like.CommentID = &targetID
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected > 0 {
+			if err := tx.Table(target.table).Where("id = ?", targetID).UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		count, err = likeCount(tx, target, targetID)
+
+		return err
+	})
+
+	return
+}
+
+// Unlike removes the like and lowers the counter of the target in the same
+// transaction. Unliking a target that isn't liked changes nothing.
+func (likeRepository *likeRepository) Unlike(ctx context.Context, userID string, targetType string, targetID string) (count int, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	target, ok := likeTargets[targetType]
+
+	if !ok {
+		return 0, domain.ErrInvalidLikeTarget
+	}
+
+	err = likeRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := likeCount(tx, target, targetID); err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ?", userID).Where(target.column+" = ?", targetID).Delete(&domain.Like{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected > 0 {
+			if err := tx.Table(target.table).Where("id = ?", targetID).UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		count, err = likeCount(tx, target, targetID)
+
+		return err
+	})
+
+	return
+}
+
+// GetLiked reports which of targetIDs the user has liked.
+func (likeRepository *likeRepository) GetLiked(ctx context.Context, userID string, targetType string, targetIDs []string) (liked map[string]bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	target, ok := likeTargets[targetType]
+
+	if !ok {
+		return nil, domain.ErrInvalidLikeTarget
+	}
+
+	liked = make(map[string]bool, len(targetIDs))
+
+	if len(targetIDs) == 0 {
+		return liked, nil
+	}
+
+	var likedIDs []string
+
+	if err = likeRepository.db.WithContext(ctx).Model(&domain.Like{}).Where("user_id = ?", userID).Where(target.column+" IN ?", targetIDs).Pluck(target.column, &likedIDs).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range likedIDs {
+		liked[id] = true
+	}
+
+	return liked, nil
+}
+
+// likeCount returns the like counter of the target, or gorm.ErrRecordNotFound
+// when it doesn't exist.
+func likeCount(tx *gorm.DB, target likeTarget, targetID string) (int, error) {
+	var counts []int
+
+	if err := tx.Table(target.table).Where("id = ?", targetID).Pluck("like_count", &counts).Error; err != nil {
+		return 0, err
+	}
+
+	if len(counts) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+
+	return counts[0], nil
+}
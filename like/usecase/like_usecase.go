@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+)
+
+type likeUseCase struct {
+	likeRepository domain.LikeRepository
+}
+
+func NewLikeUseCase(likeRepository domain.LikeRepository) *likeUseCase {
+	return &likeUseCase{likeRepository}
+}
+
+func (likeUseCase *likeUseCase) Like(ctx context.Context, userID string, targetType string, targetID string) (count int, err error) {
+	if count, err = likeUseCase.likeRepository.Like(ctx, userID, targetType, targetID); err != nil {
+		return 0, err
+	}
+
+	return
+}
+
+func (likeUseCase *likeUseCase) Unlike(ctx context.Context, userID string, targetType string, targetID string) (count int, err error) {
+	if count, err = likeUseCase.likeRepository.Unlike(ctx, userID, targetType, targetID); err != nil {
+		return 0, err
+	}
+
+	return
+}
+
+// GetLiked reports which of targetIDs the user has liked. Anonymous callers
+// have liked nothing.
+func (likeUseCase *likeUseCase) GetLiked(ctx context.Context, userID string, targetType string, targetIDs []string) (liked map[string]bool, err error) {
+	if userID == "" || len(targetIDs) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	if liked, err = likeUseCase.likeRepository.GetLiked(ctx, userID, targetType, targetIDs); err != nil {
+		return nil, err
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"testing"
+
+	likeUseCase "mygram-byferdiansyah/like/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLike(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository)
+
+	t.Run("like an image correctly", func(t *testing.T) {
+		mockLikeRepository.On("Like", mock.Anything, "user-123", domain.LikeTargetImage, "image-123").Return(1, nil).Once()
+
+		count, err := likeUseCase.Like(context.Background(), "user-123", domain.LikeTargetImage, "image-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("like a comment that doesn't exist", func(t *testing.T) {
+		mockLikeRepository.On("Like", mock.Anything, "user-123", domain.LikeTargetComment, "comment-123").Return(0, errors.New("record not found")).Once()
+
+		_, err := likeUseCase.Like(context.Background(), "user-123", domain.LikeTargetComment, "comment-123")
+
+		assert.Error(t, err)
+		mockLikeRepository.AssertExpectations(t)
+	})
+}
+
+func TestUnlike(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository)
+
+	t.Run("unlike an image correctly", func(t *testing.T) {
+		mockLikeRepository.On("Unlike", mock.Anything, "user-123", domain.LikeTargetImage, "image-123").Return(0, nil).Once()
+
+		count, err := likeUseCase.Unlike(context.Background(), "user-123", domain.LikeTargetImage, "image-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		mockLikeRepository.AssertExpectations(t)
+	})
+}
+
+func TestGetLiked(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository)
+
+	t.Run("get liked images correctly", func(t *testing.T) {
+		mockLikeRepository.On("GetLiked", mock.Anything, "user-123", domain.LikeTargetImage, []string{"image-123", "image-234"}).Return(map[string]bool{"image-123": true}, nil).Once()
+
+		liked, err := likeUseCase.GetLiked(context.Background(), "user-123", domain.LikeTargetImage, []string{"image-123", "image-234"})
+
+		assert.NoError(t, err)
+		assert.True(t, liked["image-123"])
+		assert.False(t, liked["image-234"])
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("get liked of an empty page without a query", func(t *testing.T) {
+		liked, err := likeUseCase.GetLiked(context.Background(), "user-123", domain.LikeTargetImage, nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, liked)
+		mockLikeRepository.AssertExpectations(t)
+	})
+}
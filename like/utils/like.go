@@ -0,0 +1,16 @@
+package utils
+
+type Like struct {
+	Liked     bool `json:"liked" example:"true"`
+	LikeCount int  `json:"like_count" example:"1"`
+}
+
+type ResponseDataLike struct {
+	Status string `json:"status" example:"success"`
+	Data   Like   `json:"data"`
+}
+
+type ResponseMessage struct {
+	Status string `json:"status" example:"fail"`
+	Data   string `json:"data" example:"the error explained here"`
+}
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"sync"
+)
+
+// mailer keeps every email it is given in memory instead of delivering it.
+// It stands in for a real mail server in development and in tests.
+type mailer struct {
+	mu     sync.Mutex
+	emails []domain.Email
+}
+
+func NewMailer() *mailer {
+	return &mailer{}
+}
+
+func (mailer *mailer) Send(ctx context.Context, email domain.Email) (err error) {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+
+	mailer.emails = append(mailer.emails, email)
+
+	return
+}
+
+// Emails returns the emails sent so far, oldest first.
+func (mailer *mailer) Emails() []domain.Email {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+
+	return append([]domain.Email(nil), mailer.emails...)
+}
+
+// Last returns the most recent email sent to to, or false when none was.
+func (mailer *mailer) Last(to string) (domain.Email, bool) {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+
+	for i := len(mailer.emails) - 1; i >= 0; i-- {
+		if mailer.emails[i].To == to {
+			return mailer.emails[i], true
+		}
+	}
+
+	return domain.Email{}, false
+}
@@ -0,0 +1,32 @@
+package mail_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"testing"
+
+	mail "mygram-byferdiansyah/mail/outbox"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	t.Run("send emails into the outbox", func(t *testing.T) {
+		mailer := mail.NewMailer()
+
+		assert.NoError(t, mailer.Send(context.Background(), domain.Email{To: "johndoe@example.com", Subject: "first"}))
+		assert.NoError(t, mailer.Send(context.Background(), domain.Email{To: "janedoe@example.com", Subject: "second"}))
+		assert.NoError(t, mailer.Send(context.Background(), domain.Email{To: "johndoe@example.com", Subject: "third"}))
+
+		assert.Len(t, mailer.Emails(), 3)
+
+		email, ok := mailer.Last("johndoe@example.com")
+
+		assert.True(t, ok)
+		assert.Equal(t, "third", email.Subject)
+
+		_, ok = mailer.Last("nobody@example.com")
+
+		assert.False(t, ok)
+	})
+}
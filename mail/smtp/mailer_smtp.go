@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mygram-byferdiansyah/domain"
+	"net"
+	netMail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+var errHeaderInjection = errors.New("the email address or subject contains a line break")
+
+type mailer struct {
+	addr   string
+	auth   smtp.Auth
+	from   string
+	sender string
+}
+
+// NewMailer sends emails from the from address, which may carry a display
+// name such as "MyGram <no-reply@example.com>", through the SMTP server at
+// host and port. The server is only authenticated with when username is set,
+// and the credentials are only sent over TLS or to localhost.
+func NewMailer(host string, port string, username string, password string, from string) *mailer {
+	var auth smtp.Auth
+
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	sender := from
+
+	if address, err := netMail.ParseAddress(from); err == nil {
+		sender = address.Address
+	}
+
+	return &mailer{net.JoinHostPort(host, port), auth, from, sender}
+}
+
+func (mailer *mailer) Send(ctx context.Context, email domain.Email) (err error) {
+	for _, header := range []string{mailer.from, email.To, email.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return errHeaderInjection
+		}
+	}
+
+	message := strings.Join([]string{
+		"From: " + mailer.from,
+		"To: " + email.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", email.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+		"",
+		strings.ReplaceAll(email.Body, "\n", "\r\n"),
+	}, "\r\n")
+
+	done := make(chan error, 1)
+
+	// net/smtp takes no context, so a cancelled request stops waiting for the
+	// server but leaves the delivery to finish on its own.
+	go func() {
+		done <- smtp.SendMail(mailer.addr, mailer.auth, mailer.sender, []string{email.To}, []byte(message))
+	}()
+
+	select {
+	case err = <-done:
+		if err != nil {
+			return fmt.Errorf("sending email to %s: %w", email.To, err)
+		}
+
+		return
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"mygram-byferdiansyah/domain"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// received is what the stand-in server was given for one email.
+type received struct {
+	from string
+	to   []string
+	data string
+}
+
+// standIn accepts a single SMTP session on listener and sends what it
+// received on the channel once the session ends.
+func standIn(listener net.Listener) <-chan received {
+	done := make(chan received, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		email := received{}
+
+		text.PrintfLine("220 localhost ESMTP")
+
+		for {
+			line, err := text.ReadLine()
+
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+			switch command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250 localhost")
+			case "MAIL":
+				email.from = strings.TrimSuffix(strings.TrimPrefix(line, "MAIL FROM:<"), ">")
+				text.PrintfLine("250 OK")
+			case "RCPT":
+				email.to = append(email.to, strings.TrimSuffix(strings.TrimPrefix(line, "RCPT TO:<"), ">"))
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 Go ahead")
+
+				data, _ := bufio.NewReader(text.DotReader()).ReadString(0)
+
+				email.data = data
+				text.PrintfLine("250 OK")
+			case "QUIT":
+				text.PrintfLine("221 Bye")
+				done <- email
+
+				return
+			default:
+				text.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	return done
+}
+
+func TestSend(t *testing.T) {
+	t.Run("send email through the smtp server", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+		assert.NoError(t, err)
+
+		defer listener.Close()
+
+		done := standIn(listener)
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+		mailer := NewMailer(host, port, "", "", "MyGram <no-reply@example.com>")
+
+		err = mailer.Send(context.Background(), domain.Email{
+			To:      "johndoe@example.com",
+			Subject: "Reset your MyGram password",
+			Body:    "first line\nsecond line\n",
+		})
+
+		assert.NoError(t, err)
+
+		email := <-done
+
+		assert.Equal(t, "no-reply@example.com", email.from)
+		assert.Equal(t, []string{"johndoe@example.com"}, email.to)
+		assert.Contains(t, email.data, "From: MyGram <no-reply@example.com>\n")
+		assert.Contains(t, email.data, "To: johndoe@example.com\n")
+		assert.Contains(t, email.data, "Subject: Reset your MyGram password\n")
+		assert.Contains(t, email.data, "\nfirst line\nsecond line\n")
+	})
+
+	t.Run("send email with a line break in the subject", func(t *testing.T) {
+		mailer := NewMailer("127.0.0.1", "25", "", "", "no-reply@example.com")
+
+		err := mailer.Send(context.Background(), domain.Email{
+			To:      "johndoe@example.com",
+			Subject: "Hello\r\nBcc: janedoe@example.com",
+		})
+
+		assert.ErrorIs(t, err, errHeaderInjection)
+	})
+}
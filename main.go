@@ -1,13 +1,13 @@
 package main
 
 import (
-	"os"
+	"context"
+	"log"
+	"mygram-byferdiansyah/app"
+	"mygram-byferdiansyah/config"
+	"mygram-byferdiansyah/config/database"
 
 	_ "mygram-byferdiansyah/docs"
-
-	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // @title MyGram By Ferdiansya
@@ -24,50 +24,17 @@ import (
 // @securityDefinitions.apikey  Bearer
 // @in                          header
 // @name                        Authorization
-// @description					        Description for what is this security definition being used
+// @description					        Type "Bearer" followed by an access token, or by an API key on the routes its scopes allow
 func main() {
-	// if err := godotenv.Load(); err != nil {
-	// 	log.Fatal("Error loading .env file: ", err)
-	// }
-
-	// db := database.StartDB()
-
-	routers := gin.Default()
-
-	routers.Use(func(ctx *gin.Context) {
-		ctx.Writer.Header().Set("Content-Type", "application/json")
-		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		ctx.Writer.Header().Set("Access-Control-Max-Age", "86400")
-		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, PUT, DELETE, UPDATE")
-		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Max")
-		ctx.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-
-		if ctx.Request.Method == "OPTIONS" {
-			ctx.AbortWithStatus(200)
-		} else {
-			ctx.Next()
-		}
-	})
-
-	routers.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	config, err := config.Load()
 
-	// if err := godotenv.Load(); err != nil {
-	// 	log.Fatal("Error loading .env file: ", err)
-	// }
-
-	port := os.Getenv("PORT")
-
-	if len(os.Args) > 1 {
-		reqPort := os.Args[1]
-
-		if reqPort != "" {
-			port = reqPort
-		}
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	if port == "" {
-		port = "8080"
-	}
+	db := database.StartDB(config)
 
-	routers.Run(":" + port)
+	if err = app.Run(context.Background(), config, db); err != nil {
+		log.Fatal("Error starting server: ", err)
+	}
 }
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Authentication(tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := bearerAPIKey(ctx); ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: domain.ErrAPIKeyForbidden.Error(),
+			})
+
+			return
+		}
+
+		authenticateToken(ctx, tokenManager, tokenRevocationUseCase)
+	}
+}
+
+// OptionalAuthentication sets the principal of a request that comes with a
+// valid access token, and lets every other request through without one
+// instead of refusing it, such as a logout sent after the access token has
+// expired.
+func OptionalAuthentication(tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := bearerAPIKey(ctx); ok || ctx.Request.Header.Get("Authorization") == "" {
+			ctx.Next()
+
+			return
+		}
+
+		if claims, err := tokenManager.VerifyToken(ctx); err == nil {
+			if principal, ok := newPrincipal(claims); ok {
+				revoked, err := tokenRevocationUseCase.IsRevoked(ctx.Request.Context(), principal.TokenID, principal.UserID, principal.IssuedAt)
+
+				if err == nil && !revoked {
+					setPrincipal(ctx, principal)
+				}
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// AuthenticationWithAPIKeys authenticates the request like Authentication,
+// and also accepts an API key in place of the access token. Every route
+// behind it has to say which scope a key needs with RequireScope.
+func AuthenticationWithAPIKeys(tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, apiKeyUseCase domain.APIKeyUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key, ok := bearerAPIKey(ctx)
+
+		if !ok {
+			authenticateToken(ctx, tokenManager, tokenRevocationUseCase)
+
+			return
+		}
+
+		apiKey := domain.APIKey{}
+
+		if err := apiKeyUseCase.Authenticate(ctx.Request.Context(), &apiKey, key); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrAPIKeyInvalid), errors.Is(err, domain.ErrUserSuspended):
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+					Status:  "unauthenticated",
+					Message: err.Error(),
+				})
+			default:
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+					Status:  "fail",
+					Message: err.Error(),
+				})
+			}
+
+			return
+		}
+
+		setPrincipal(ctx, domain.Principal{
+			UserID:   apiKey.UserID,
+			Email:    apiKey.User.Email,
+			APIKeyID: apiKey.ID,
+			Scopes:   apiKey.Scopes,
+		})
+		ctx.Next()
+	}
+}
+
+func authenticateToken(ctx *gin.Context, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase) {
+	claims, err := tokenManager.VerifyToken(ctx)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, ok := newPrincipal(claims)
+
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: "sign in to proceed",
+		})
+
+		return
+	}
+
+	revoked, err := tokenRevocationUseCase.IsRevoked(ctx.Request.Context(), principal.TokenID, principal.UserID, principal.IssuedAt)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if revoked {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: "your session has been revoked, sign in to proceed",
+		})
+
+		return
+	}
+
+	setPrincipal(ctx, principal)
+	ctx.Next()
+}
+
+// bearerAPIKey returns the API key the request is authenticated with, if any.
+func bearerAPIKey(ctx *gin.Context) (string, bool) {
+	header := ctx.Request.Header.Get("Authorization")
+	key := strings.TrimPrefix(header, "Bearer ")
+
+	return key, key != header && strings.HasPrefix(key, domain.APIKeyPrefix)
+}
@@ -0,0 +1,269 @@
+package middleware_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tokenRevocationRepository "mygram-byferdiansyah/user/repository/memory"
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenManager := helpers.NewTokenManager("secret", time.Minute)
+	tokenRevocationUseCase := userUseCase.NewTokenRevocationUseCase(tokenRevocationRepository.NewTokenRevocationRepository())
+
+	router := gin.New()
+
+	router.GET("/me", middleware.Authentication(tokenManager, tokenRevocationUseCase), func(ctx *gin.Context) {
+		principal, ok := middleware.GetPrincipal(ctx)
+		propagated, _ := domain.PrincipalFrom(ctx.Request.Context())
+
+		if !ok || propagated.UserID != principal.UserID {
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+
+			return
+		}
+
+		ctx.String(http.StatusOK, principal.UserID)
+	})
+
+	request := func(token string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		router.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	t.Run("valid token sets the principal", func(t *testing.T) {
+		token, err := tokenManager.GenerateToken("user-123", "johndoe@example.com", []string{domain.RoleUser})
+
+		assert.NoError(t, err)
+
+		recorder := request(token)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "user-123", recorder.Body.String())
+	})
+
+	t.Run("missing token is unauthenticated", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("").Code)
+	})
+
+	t.Run("token signed with another key is unauthenticated", func(t *testing.T) {
+		token, err := helpers.NewTokenManager("other", time.Minute).GenerateToken("user-123", "johndoe@example.com", []string{domain.RoleUser})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, request(token).Code)
+	})
+
+	t.Run("token issued before revoke all is unauthenticated", func(t *testing.T) {
+		token, err := tokenManager.GenerateToken("user-234", "janedoe@example.com", []string{domain.RoleUser})
+
+		assert.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+
+		assert.NoError(t, tokenRevocationUseCase.RevokeAll(context.Background(), "user-234"))
+		assert.Equal(t, http.StatusUnauthorized, request(token).Code)
+	})
+
+	t.Run("token issued right after revoke all is authenticated", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, tokenRevocationUseCase.RevokeAll(context.Background(), "user-456"))
+
+			token, err := tokenManager.GenerateToken("user-456", "janedoe@example.com", []string{domain.RoleUser})
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, request(token).Code)
+		}
+	})
+
+	t.Run("revoked token is unauthenticated", func(t *testing.T) {
+		token, err := tokenManager.GenerateToken("user-345", "janedoe@example.com", []string{domain.RoleUser})
+
+		assert.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+
+		_, _, err = new(jwt.Parser).ParseUnverified(token, claims)
+
+		assert.NoError(t, err)
+
+		expiresAt := time.Now().Add(time.Minute)
+
+		assert.NoError(t, tokenRevocationUseCase.Revoke(context.Background(), domain.RevokedToken{JTI: claims["jti"].(string), UserID: "user-345", ExpiresAt: &expiresAt}))
+		assert.Equal(t, http.StatusUnauthorized, request(token).Code)
+	})
+}
+
+func TestOptionalAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenManager := helpers.NewTokenManager("secret", time.Minute)
+	tokenRevocationUseCase := userUseCase.NewTokenRevocationUseCase(tokenRevocationRepository.NewTokenRevocationRepository())
+
+	router := gin.New()
+
+	router.POST("/logout", middleware.OptionalAuthentication(tokenManager, tokenRevocationUseCase), func(ctx *gin.Context) {
+		principal, _ := middleware.GetPrincipal(ctx)
+
+		ctx.String(http.StatusOK, principal.UserID)
+	})
+
+	request := func(authorization string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		router.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	t.Run("valid token sets the principal", func(t *testing.T) {
+		token, err := tokenManager.GenerateToken("user-123", "johndoe@example.com", []string{domain.RoleUser})
+
+		assert.NoError(t, err)
+
+		recorder := request("Bearer " + token)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "user-123", recorder.Body.String())
+	})
+
+	t.Run("missing token lets the request through", func(t *testing.T) {
+		recorder := request("")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("invalid token lets the request through without a principal", func(t *testing.T) {
+		recorder := request("Bearer invalid")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("expired token lets the request through without a principal", func(t *testing.T) {
+		token, err := helpers.NewTokenManager("secret", -time.Minute).GenerateToken("user-123", "johndoe@example.com", []string{domain.RoleUser})
+
+		assert.NoError(t, err)
+
+		recorder := request("Bearer " + token)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+}
+
+func TestAuthenticationWithAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenManager := helpers.NewTokenManager("secret", time.Minute)
+	tokenRevocationUseCase := userUseCase.NewTokenRevocationUseCase(tokenRevocationRepository.NewTokenRevocationRepository())
+	key := domain.APIKeyPrefix + "abcdefghijklmnop"
+
+	request := func(apiKeyUseCase domain.APIKeyUseCase, authentication gin.HandlerFunc, token string) *httptest.ResponseRecorder {
+		router := gin.New()
+
+		router.GET("/images", authentication, middleware.RequireScope(domain.ScopeImagesRead), func(ctx *gin.Context) {
+			principal, _ := middleware.GetPrincipal(ctx)
+
+			ctx.String(http.StatusOK, principal.UserID)
+		})
+
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/images", nil)
+
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	t.Run("API key with the scope sets the principal", func(t *testing.T) {
+		mockAPIKeyUseCase := new(mocks.APIKeyUseCase)
+
+		mockAPIKeyUseCase.On("Authenticate", mock.Anything, mock.AnythingOfType("*domain.APIKey"), key).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.APIKey) = domain.APIKey{ID: "apikey-123", UserID: "user-123", Scopes: []string{domain.ScopeImagesRead}, User: &domain.User{Email: "johndoe@example.com"}}
+		}).Return(nil).Once()
+
+		recorder := request(mockAPIKeyUseCase, middleware.AuthenticationWithAPIKeys(tokenManager, tokenRevocationUseCase, mockAPIKeyUseCase), key)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "user-123", recorder.Body.String())
+		mockAPIKeyUseCase.AssertExpectations(t)
+	})
+
+	t.Run("API key without the scope is forbidden", func(t *testing.T) {
+		mockAPIKeyUseCase := new(mocks.APIKeyUseCase)
+
+		mockAPIKeyUseCase.On("Authenticate", mock.Anything, mock.AnythingOfType("*domain.APIKey"), key).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.APIKey) = domain.APIKey{ID: "apikey-123", UserID: "user-123", Scopes: []string{domain.ScopeCommentsWrite}, User: &domain.User{}}
+		}).Return(nil).Once()
+
+		recorder := request(mockAPIKeyUseCase, middleware.AuthenticationWithAPIKeys(tokenManager, tokenRevocationUseCase, mockAPIKeyUseCase), key)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), domain.ScopeImagesRead)
+		mockAPIKeyUseCase.AssertExpectations(t)
+	})
+
+	t.Run("revoked API key is unauthenticated", func(t *testing.T) {
+		mockAPIKeyUseCase := new(mocks.APIKeyUseCase)
+
+		mockAPIKeyUseCase.On("Authenticate", mock.Anything, mock.AnythingOfType("*domain.APIKey"), key).Return(domain.ErrAPIKeyInvalid).Once()
+
+		recorder := request(mockAPIKeyUseCase, middleware.AuthenticationWithAPIKeys(tokenManager, tokenRevocationUseCase, mockAPIKeyUseCase), key)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		mockAPIKeyUseCase.AssertExpectations(t)
+	})
+
+	t.Run("access token is let through whatever the scope", func(t *testing.T) {
+		mockAPIKeyUseCase := new(mocks.APIKeyUseCase)
+		token, err := tokenManager.GenerateToken("user-234", "janedoe@example.com", []string{domain.RoleUser})
+
+		assert.NoError(t, err)
+
+		recorder := request(mockAPIKeyUseCase, middleware.AuthenticationWithAPIKeys(tokenManager, tokenRevocationUseCase, mockAPIKeyUseCase), token)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "user-234", recorder.Body.String())
+		mockAPIKeyUseCase.AssertNotCalled(t, "Authenticate")
+	})
+
+	t.Run("API key is refused where only access tokens are accepted", func(t *testing.T) {
+		mockAPIKeyUseCase := new(mocks.APIKeyUseCase)
+
+		recorder := request(mockAPIKeyUseCase, middleware.Authentication(tokenManager, tokenRevocationUseCase), key)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), domain.ErrAPIKeyForbidden.Error())
+		mockAPIKeyUseCase.AssertNotCalled(t, "Authenticate")
+	})
+}
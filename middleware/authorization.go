@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const resourceKey = "resource"
+
+// OwnedResourceLoader loads a resource by id. The GetByID method of every
+// feature usecase satisfies it.
+type OwnedResourceLoader[T any] interface {
+	GetByID(context.Context, *T, string) error
+}
+
+// OwnedResource is implemented by the pointer type of every resource that
+// belongs to a single user.
+type OwnedResource[T any] interface {
+	*T
+	OwnerID() string
+}
+
+// RequireOwner loads the resource whose id is in the URL parameter param and
+// only lets the request through when it belongs to the authenticated user.
+// It answers 404 when the resource does not exist and 403 when it belongs to
+// someone else. The loaded resource is available to handlers via Resource.
+func RequireOwner[T any, PT OwnedResource[T]](loader OwnedResourceLoader[T], name string, param string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var resource T
+
+		resourceID := ctx.Param(param)
+		principal, ok := GetPrincipal(ctx)
+
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: "sign in to proceed",
+			})
+
+			return
+		}
+
+		if err := loader.GetByID(ctx.Request.Context(), &resource, resourceID); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("%s with id %s doesn't exist", name, resourceID),
+			})
+
+			return
+		}
+
+		if PT(&resource).OwnerID() != principal.UserID {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "forbidden",
+				Message: fmt.Sprintf("you don't have permission to view or edit this %s", name),
+			})
+
+			return
+		}
+
+		ctx.Set(resourceKey, &resource)
+		ctx.Next()
+	}
+}
+
+// RequireRole only lets the request through when the authenticated user has
+// at least one of roles.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := GetPrincipal(ctx)
+
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: "sign in to proceed",
+			})
+
+			return
+		}
+
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				ctx.Next()
+
+				return
+			}
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+			Status:  "forbidden",
+			Message: "you don't have permission to access this resource",
+		})
+	}
+}
+
+// RequireScope only lets requests made with an API key through when the key
+// has scope. Requests made with an access token are let through either way.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := GetPrincipal(ctx)
+
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: "sign in to proceed",
+			})
+
+			return
+		}
+
+		if principal.APIKeyID != "" && !principal.HasScope(scope) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "forbidden",
+				Message: fmt.Sprintf("the API key needs the %s scope", scope),
+			})
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// UserLoader loads a user by id. The GetByID method of domain.UserUseCase
+// satisfies it.
+type UserLoader interface {
+	GetByID(context.Context, *domain.User, string) error
+}
+
+// RequireVerifiedEmail only lets the request through when the authenticated
+// user has verified their email. The user is loaded on every request rather
+// than read from the token, so verifying takes effect right away and
+// changing the email takes it away.
+func RequireVerifiedEmail(users UserLoader) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var user domain.User
+
+		principal, ok := GetPrincipal(ctx)
+
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: "sign in to proceed",
+			})
+
+			return
+		}
+
+		if err := users.GetByID(ctx.Request.Context(), &user, principal.UserID); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: "account not found",
+			})
+
+			return
+		}
+
+		if user.EmailVerifiedAt == nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "forbidden",
+				Message: domain.ErrEmailNotVerified.Error(),
+			})
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Resource returns the resource loaded by RequireOwner for this request.
+func Resource[T any](ctx *gin.Context) (*T, bool) {
+	value, ok := ctx.Get(resourceKey)
+
+	if !ok {
+		return nil, false
+	}
+
+	resource, ok := value.(*T)
+
+	return resource, ok
+}
@@ -0,0 +1,136 @@
+package middleware_test
+
+import (
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRouter(imageUseCase domain.ImageUseCase, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+
+	router.PUT("/images/:imageId", func(ctx *gin.Context) {
+		ctx.Set("principal", domain.Principal{UserID: userID})
+	}, middleware.RequireOwner[domain.Image](imageUseCase, "image", "imageId"), func(ctx *gin.Context) {
+		image, ok := middleware.Resource[domain.Image](ctx)
+
+		if !ok {
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+
+			return
+		}
+
+		ctx.String(http.StatusOK, image.ID)
+	})
+
+	return router
+}
+
+func TestRequireOwner(t *testing.T) {
+	mockImage := domain.Image{
+		ID:     "image-123",
+		UserID: "user-123",
+	}
+
+	t.Run("owner is let through with the loaded resource", func(t *testing.T) {
+		mockImageUseCase := new(mocks.ImageUseCase)
+
+		mockImageUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Image) = mockImage
+		}).Return(nil).Once()
+
+		recorder := httptest.NewRecorder()
+
+		newRouter(mockImageUseCase, "user-123").ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/images/image-123", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "image-123", recorder.Body.String())
+		mockImageUseCase.AssertExpectations(t)
+	})
+
+	t.Run("other user is forbidden", func(t *testing.T) {
+		mockImageUseCase := new(mocks.ImageUseCase)
+
+		mockImageUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Image) = mockImage
+		}).Return(nil).Once()
+
+		recorder := httptest.NewRecorder()
+
+		newRouter(mockImageUseCase, "user-234").ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/images/image-123", nil))
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		mockImageUseCase.AssertExpectations(t)
+	})
+
+	t.Run("missing resource is not found", func(t *testing.T) {
+		mockImageUseCase := new(mocks.ImageUseCase)
+
+		mockImageUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Image"), "image-234").Return(errors.New("record not found")).Once()
+
+		recorder := httptest.NewRecorder()
+
+		newRouter(mockImageUseCase, "user-123").ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/images/image-234", nil))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		mockImageUseCase.AssertExpectations(t)
+	})
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifiedAt := time.Now()
+
+	request := func(userUseCase domain.UserUseCase) *httptest.ResponseRecorder {
+		router := gin.New()
+
+		router.POST("/images", func(ctx *gin.Context) {
+			ctx.Set("principal", domain.Principal{UserID: "user-123"})
+		}, middleware.RequireVerifiedEmail(userUseCase), func(ctx *gin.Context) {
+			ctx.Status(http.StatusCreated)
+		})
+
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/images", nil))
+
+		return recorder
+	}
+
+	t.Run("verified user is let through", func(t *testing.T) {
+		mockUserUseCase := new(mocks.UserUseCase)
+
+		mockUserUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123", EmailVerifiedAt: &verifiedAt}
+		}).Return(nil).Once()
+
+		assert.Equal(t, http.StatusCreated, request(mockUserUseCase).Code)
+		mockUserUseCase.AssertExpectations(t)
+	})
+
+	t.Run("unverified user is forbidden", func(t *testing.T) {
+		mockUserUseCase := new(mocks.UserUseCase)
+
+		mockUserUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123"}
+		}).Return(nil).Once()
+
+		recorder := request(mockUserUseCase)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), domain.ErrEmailNotVerified.Error())
+		mockUserUseCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"math"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "principal"
+
+// GetPrincipal returns the caller set by Authentication. It reports false on
+// routes that are not behind Authentication.
+func GetPrincipal(ctx *gin.Context) (domain.Principal, bool) {
+	value, ok := ctx.Get(principalKey)
+
+	if !ok {
+		return domain.Principal{}, false
+	}
+
+	principal, ok := value.(domain.Principal)
+
+	return principal, ok
+}
+
+// setPrincipal stores principal on the gin context and on the request
+// context handed down to usecases.
+func setPrincipal(ctx *gin.Context, principal domain.Principal) {
+	ctx.Set(principalKey, principal)
+	ctx.Request = ctx.Request.WithContext(domain.WithPrincipal(ctx.Request.Context(), principal))
+}
+
+// newPrincipal reads the principal out of verified token claims. It reports
+// false when the claims do not identify a user.
+func newPrincipal(claims jwt.MapClaims) (domain.Principal, bool) {
+	userID, _ := claims["id"].(string)
+	email, _ := claims["email"].(string)
+	tokenID, _ := claims["jti"].(string)
+	issuedAt, _ := claims["iat"].(float64)
+	expiresAt, _ := claims["exp"].(float64)
+
+	if userID == "" || tokenID == "" {
+		return domain.Principal{}, false
+	}
+
+	return domain.Principal{
+		UserID:    userID,
+		Email:     email,
+		Roles:     stringsClaim(claims["roles"]),
+		TokenID:   tokenID,
+		Scopes:    stringsClaim(claims["scopes"]),
+		IssuedAt:  time.UnixMilli(int64(math.Round(issuedAt * 1000))),
+		ExpiresAt: time.Unix(int64(expiresAt), 0),
+	}, true
+}
+
+func stringsClaim(claim interface{}) (values []string) {
+	items, _ := claim.([]interface{})
+
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter limits requests with the token buckets of named policies.
+type RateLimiter struct {
+	store    domain.RateLimitStore
+	policies map[string]domain.RateLimitPolicy
+}
+
+func NewRateLimiter(store domain.RateLimitStore, policies map[string]domain.RateLimitPolicy) *RateLimiter {
+	return &RateLimiter{store, policies}
+}
+
+// Limit returns the middleware that limits requests with the policy with the
+// name. Requests are counted per user behind Authentication and per client
+// IP in front of it, and the ones over the limit are answered with 429. The
+// limit isn't enforced while the store fails, so an outage of the store
+// doesn't take the routes down with it.
+func (rateLimiter *RateLimiter) Limit(name string) gin.HandlerFunc {
+	return rateLimiter.limit(name, func(ctx *gin.Context) string {
+		if principal, ok := GetPrincipal(ctx); ok {
+			return "user:" + principal.UserID
+		}
+
+		return "ip:" + ctx.ClientIP()
+	})
+}
+
+// LimitAddress is Limit counting every request per client IP, to go in front
+// of Authentication so requests with wrong tokens or API keys are limited
+// too.
+func (rateLimiter *RateLimiter) LimitAddress(name string) gin.HandlerFunc {
+	return rateLimiter.limit(name, func(ctx *gin.Context) string {
+		return "ip:" + ctx.ClientIP()
+	})
+}
+
+func (rateLimiter *RateLimiter) limit(name string, keyOf func(*gin.Context) string) gin.HandlerFunc {
+	policy, ok := rateLimiter.policies[name]
+
+	if !ok {
+		panic(fmt.Sprintf("rate limit policy %q is not configured", name))
+	}
+
+	return func(ctx *gin.Context) {
+		key := keyOf(ctx)
+
+		result, err := rateLimiter.store.Take(ctx.Request.Context(), name+":"+key, policy)
+
+		if err != nil {
+			log.Printf("rate limiting %s with %s: %v", key, name, err)
+			ctx.Next()
+
+			return
+		}
+
+		ctx.Header("RateLimit-Limit", strconv.Itoa(policy.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("RateLimit-Reset", strconv.Itoa(seconds(result.ResetAfter)))
+		ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, seconds(policy.Period)))
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("too many requests, try again in %s", time.Duration(seconds(result.RetryAfter))*time.Second),
+			})
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Allow takes a request counted by key rather than by client out of the
+// bucket of the policy with the name, for limits the request body decides
+// such as the email a reset is asked for. Like Limit, it lets the request
+// through while the store fails.
+func (rateLimiter *RateLimiter) Allow(ctx context.Context, name string, key string) bool {
+	policy, ok := rateLimiter.policies[name]
+
+	if !ok {
+		panic(fmt.Sprintf("rate limit policy %q is not configured", name))
+	}
+
+	result, err := rateLimiter.store.Take(ctx, name+":"+key, policy)
+
+	if err != nil {
+		log.Printf("rate limiting %s with %s: %v", key, name, err)
+
+		return true
+	}
+
+	return result.Allowed
+}
+
+// seconds rounds duration up to whole seconds, as the headers carry them.
+func seconds(duration time.Duration) int {
+	return int((duration + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,154 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ratelimit "mygram-byferdiansyah/ratelimit/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policies := map[string]domain.RateLimitPolicy{
+		domain.RateLimitVerifyEmail: {Limit: 2, Period: 100 * time.Millisecond},
+	}
+
+	newRouter := func(store domain.RateLimitStore, principal *domain.Principal) *gin.Engine {
+		router := gin.New()
+		rateLimiter := middleware.NewRateLimiter(store, policies)
+
+		router.POST("/verify-email", func(ctx *gin.Context) {
+			if principal != nil {
+				ctx.Set("principal", *principal)
+			}
+		}, rateLimiter.Limit(domain.RateLimitVerifyEmail), func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+
+		return router
+	}
+
+	request := func(router *gin.Engine, ip string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/verify-email", nil)
+
+		req.RemoteAddr = ip + ":1234"
+
+		router.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	t.Run("requests over the limit are rejected until the bucket refills", func(t *testing.T) {
+		router := newRouter(ratelimit.NewRateLimitStore(), nil)
+
+		recorder := request(router, "192.0.2.1")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", recorder.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, http.StatusOK, request(router, "192.0.2.1").Code)
+
+		recorder = request(router, "192.0.2.1")
+
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+		assert.Equal(t, http.StatusOK, request(router, "192.0.2.2").Code)
+
+		time.Sleep(60 * time.Millisecond)
+
+		assert.Equal(t, http.StatusOK, request(router, "192.0.2.1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(router, "192.0.2.1").Code)
+	})
+
+	t.Run("requests of a signed in user are counted across addresses", func(t *testing.T) {
+		router := newRouter(ratelimit.NewRateLimitStore(), &domain.Principal{UserID: "user-123"})
+
+		assert.Equal(t, http.StatusOK, request(router, "192.0.2.1").Code)
+		assert.Equal(t, http.StatusOK, request(router, "192.0.2.2").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(router, "192.0.2.3").Code)
+	})
+
+	t.Run("requests are let through while the store fails", func(t *testing.T) {
+		mockRateLimitStore := new(mocks.RateLimitStore)
+
+		mockRateLimitStore.On("Take", mock.Anything, "users.verify_email:ip:192.0.2.1", policies[domain.RateLimitVerifyEmail]).Return(domain.RateLimitResult{}, errors.New("connection refused"))
+
+		router := newRouter(mockRateLimitStore, nil)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, request(router, "192.0.2.1").Code)
+		}
+	})
+}
+
+func TestRateLimitAddress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policies := map[string]domain.RateLimitPolicy{
+		domain.RateLimitDefault: {Limit: 1, Period: time.Hour},
+	}
+
+	rateLimiter := middleware.NewRateLimiter(ratelimit.NewRateLimitStore(), policies)
+	router := gin.New()
+
+	router.GET("/images", func(ctx *gin.Context) {
+		ctx.Set("principal", domain.Principal{UserID: ctx.GetHeader("X-User")})
+	}, rateLimiter.LimitAddress(domain.RateLimitDefault), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	request := func(userID string) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/images", nil)
+
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-User", userID)
+
+		router.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	t.Run("requests are counted per address whoever sends them", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request("user-123"))
+		assert.Equal(t, http.StatusTooManyRequests, request("user-456"))
+	})
+}
+
+func TestRateLimitAllow(t *testing.T) {
+	policies := map[string]domain.RateLimitPolicy{
+		domain.RateLimitForgotPassword: {Limit: 1, Period: time.Hour},
+	}
+
+	t.Run("requests are counted by key", func(t *testing.T) {
+		rateLimiter := middleware.NewRateLimiter(ratelimit.NewRateLimitStore(), policies)
+
+		assert.True(t, rateLimiter.Allow(context.Background(), domain.RateLimitForgotPassword, "email:johndoe@example.com"))
+		assert.False(t, rateLimiter.Allow(context.Background(), domain.RateLimitForgotPassword, "email:johndoe@example.com"))
+		assert.True(t, rateLimiter.Allow(context.Background(), domain.RateLimitForgotPassword, "email:janedoe@example.com"))
+	})
+
+	t.Run("requests are let through while the store fails", func(t *testing.T) {
+		mockRateLimitStore := new(mocks.RateLimitStore)
+
+		mockRateLimitStore.On("Take", mock.Anything, "users.forgot_password:email:johndoe@example.com", policies[domain.RateLimitForgotPassword]).Return(domain.RateLimitResult{}, errors.New("connection refused"))
+
+		rateLimiter := middleware.NewRateLimiter(mockRateLimitStore, policies)
+
+		assert.True(t, rateLimiter.Allow(context.Background(), domain.RateLimitForgotPassword, "email:johndoe@example.com"))
+	})
+}
@@ -0,0 +1,207 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+var (
+	ErrInvalidCursor = errors.New("the cursor is invalid")
+	ErrInvalidLimit  = errors.New("the limit must be a positive number")
+	ErrInvalidSort   = errors.New("the sort must be asc or desc")
+)
+
+// Page is one page of a list ordered by (created_at, id). It is parsed from
+// the request, narrowed by Query and completed by Finish, which sets the
+// cursors of the pages before and after it.
+type Page struct {
+	Limit         int
+	Sort          string
+	Filters       map[string]string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	NextCursor    string
+	PrevCursor    string
+
+	cursor *cursor
+}
+
+// cursor is the position a page starts after. It carries the sort it was
+// issued for so following it never mixes orders.
+type cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"i"`
+	Sort      string    `json:"s"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// Parse reads limit, sort, cursor, created_after and created_before from the
+// query string, along with the given filters, which must be column names.
+// Limits above MaxLimit are capped.
+func Parse(ctx *gin.Context, filters ...string) (*Page, error) {
+	page := &Page{Limit: DefaultLimit, Sort: SortDesc, Filters: map[string]string{}}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+
+		if err != nil || value < 1 {
+			return nil, ErrInvalidLimit
+		}
+
+		if value > MaxLimit {
+			value = MaxLimit
+		}
+
+		page.Limit = value
+	}
+
+	if sort := ctx.Query("sort"); sort != "" {
+		if sort != SortAsc && sort != SortDesc {
+			return nil, ErrInvalidSort
+		}
+
+		page.Sort = sort
+	}
+
+	if encoded := ctx.Query("cursor"); encoded != "" {
+		decoded, err := decode(encoded)
+
+		if err != nil {
+			return nil, err
+		}
+
+		page.cursor = decoded
+		page.Sort = decoded.Sort
+	}
+
+	for key, field := range map[string]**time.Time{"created_after": &page.CreatedAfter, "created_before": &page.CreatedBefore} {
+		if value := ctx.Query(key); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an RFC 3339 time such as 2023-01-02T15:04:05Z", key)
+			}
+
+			*field = &parsed
+		}
+	}
+
+	for _, filter := range filters {
+		if value := ctx.Query(filter); value != "" {
+			page.Filters[filter] = value
+		}
+	}
+
+	return page, nil
+}
+
+// Query narrows db down to the page, fetching one extra row so Finish can
+// tell whether another page follows.
+func (page *Page) Query(db *gorm.DB) *gorm.DB {
+	for column, value := range page.Filters {
+		db = db.Where(clause.Eq{Column: clause.Column{Name: column}, Value: value})
+	}
+
+	if page.CreatedAfter != nil {
+		db = db.Where("created_at > ?", *page.CreatedAfter)
+	}
+
+	if page.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *page.CreatedBefore)
+	}
+
+	descending := page.Sort == SortDesc
+
+	if page.cursor != nil {
+		descending = descending != page.cursor.Backward
+
+		if descending {
+			db = db.Where("(created_at, id) < (?, ?)", page.cursor.CreatedAt, page.cursor.ID)
+		} else {
+			db = db.Where("(created_at, id) > (?, ?)", page.cursor.CreatedAt, page.cursor.ID)
+		}
+	}
+
+	return db.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: descending}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: descending}).
+		Limit(page.Limit + 1)
+}
+
+// Finish trims the extra row fetched by Query, restores the order of a page
+// fetched backwards and sets NextCursor and PrevCursor. key returns the
+// created_at and id of an item.
+func Finish[T any](page *Page, items *[]T, key func(T) (*time.Time, string)) {
+	more := len(*items) > page.Limit
+
+	if more {
+		*items = (*items)[:page.Limit]
+	}
+
+	backward := page.cursor != nil && page.cursor.Backward
+
+	if backward {
+		for i, j := 0, len(*items)-1; i < j; i, j = i+1, j-1 {
+			(*items)[i], (*items)[j] = (*items)[j], (*items)[i]
+		}
+	}
+
+	if len(*items) == 0 {
+		return
+	}
+
+	first, last := (*items)[0], (*items)[len(*items)-1]
+
+	if more || backward {
+		page.NextCursor = encode(page, last, false, key)
+	}
+
+	if (backward && more) || (!backward && page.cursor != nil) {
+		page.PrevCursor = encode(page, first, true, key)
+	}
+}
+
+func encode[T any](page *Page, item T, backward bool, key func(T) (*time.Time, string)) string {
+	createdAt, id := key(item)
+	position := cursor{ID: id, Sort: page.Sort, Backward: backward}
+
+	if createdAt != nil {
+		position.CreatedAt = *createdAt
+	}
+
+	data, _ := json.Marshal(position)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	decoded := &cursor{}
+
+	if err = json.Unmarshal(data, decoded); err != nil || decoded.ID == "" || (decoded.Sort != SortAsc && decoded.Sort != SortDesc) {
+		return nil, ErrInvalidCursor
+	}
+
+	return decoded, nil
+}
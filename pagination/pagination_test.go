@@ -0,0 +1,194 @@
+package pagination_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mygram-byferdiansyah/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type row struct {
+	ID        string
+	CreatedAt *time.Time
+}
+
+func key(item row) (*time.Time, string) {
+	return item.CreatedAt, item.ID
+}
+
+func parse(t *testing.T, query string, filters ...string) (*pagination.Page, error) {
+	gin.SetMode(gin.TestMode)
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/?"+query, nil)
+
+	return pagination.Parse(ctx, filters...)
+}
+
+// fetch stands in for the database, applying the same ordering Query asks for.
+func fetch(t *testing.T, rows []row, page *pagination.Page, after *row, descending bool) []row {
+	result := []row{}
+
+	for _, candidate := range rows {
+		if after != nil {
+			before := candidate.CreatedAt.Before(*after.CreatedAt) || (candidate.CreatedAt.Equal(*after.CreatedAt) && candidate.ID < after.ID)
+
+			if before != descending || candidate.ID == after.ID {
+				continue
+			}
+		}
+
+		result = append(result, candidate)
+	}
+
+	if !descending {
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
+	}
+
+	if len(result) > page.Limit+1 {
+		result = result[:page.Limit+1]
+	}
+
+	return result
+}
+
+func TestParse(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		page, err := parse(t, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, pagination.DefaultLimit, page.Limit)
+		assert.Equal(t, pagination.SortDesc, page.Sort)
+		assert.Empty(t, page.Filters)
+	})
+
+	t.Run("caps the limit and keeps only allowed filters", func(t *testing.T) {
+		page, err := parse(t, "limit=1000&sort=asc&user_id=user-123&title=x&created_after=2023-01-02T15:04:05Z", "user_id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, pagination.MaxLimit, page.Limit)
+		assert.Equal(t, pagination.SortAsc, page.Sort)
+		assert.Equal(t, map[string]string{"user_id": "user-123"}, page.Filters)
+		assert.Equal(t, time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC), *page.CreatedAfter)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		for query, expected := range map[string]error{
+			"limit=0":      pagination.ErrInvalidLimit,
+			"limit=ten":    pagination.ErrInvalidLimit,
+			"sort=newest":  pagination.ErrInvalidSort,
+			"cursor=abc":   pagination.ErrInvalidCursor,
+			"cursor=e30":   pagination.ErrInvalidCursor,
+			"cursor=!!!!!": pagination.ErrInvalidCursor,
+		} {
+			_, err := parse(t, query)
+
+			assert.ErrorIs(t, err, expected, query)
+		}
+
+		_, err := parse(t, "created_before=yesterday")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestQuery(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+
+	assert.NoError(t, err)
+
+	t.Run("orders the first page newest first", func(t *testing.T) {
+		page, _ := parse(t, "limit=2&user_id=user-123", "user_id")
+
+		statement := page.Query(db.Table("images")).Find(&[]row{}).Statement
+
+		assert.Equal(t, `SELECT * FROM "images" WHERE "user_id" = $1 ORDER BY "created_at" DESC,"id" DESC LIMIT 3`, statement.SQL.String())
+	})
+
+	t.Run("follows a cursor in the order it was issued for", func(t *testing.T) {
+		createdAt := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+		page, _ := parse(t, "sort=asc")
+		items := []row{{ID: "a", CreatedAt: &createdAt}, {ID: "b", CreatedAt: &createdAt}}
+		page.Limit = 1
+		pagination.Finish(page, &items, key)
+
+		page, _ = parse(t, "limit=1&sort=desc&cursor="+page.NextCursor)
+		statement := page.Query(db.Table("images")).Find(&[]row{}).Statement
+
+		assert.Equal(t, pagination.SortAsc, page.Sort)
+		assert.Equal(t, `SELECT * FROM "images" WHERE (created_at, id) > ($1, $2) ORDER BY "created_at","id" LIMIT 2`, statement.SQL.String())
+	})
+}
+
+func TestFinish(t *testing.T) {
+	start := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	rows := []row{}
+
+	// Newest first, with two rows sharing a timestamp to exercise the id tiebreak.
+	for i := 5; i >= 1; i-- {
+		createdAt := start.Add(time.Duration(i/2) * time.Minute)
+		rows = append(rows, row{ID: string(rune('a' + i)), CreatedAt: &createdAt})
+	}
+
+	ids := func(items []row) (result []string) {
+		for _, item := range items {
+			result = append(result, item.ID)
+		}
+
+		return
+	}
+
+	t.Run("walks forward and back through every page", func(t *testing.T) {
+		page, _ := parse(t, "limit=2")
+		items := fetch(t, rows, page, nil, true)
+		pagination.Finish(page, &items, key)
+
+		assert.Equal(t, []string{"f", "e"}, ids(items))
+		assert.Empty(t, page.PrevCursor)
+		assert.NotEmpty(t, page.NextCursor)
+
+		page, _ = parse(t, "limit=2&cursor="+page.NextCursor)
+		items = fetch(t, rows, page, &items[1], true)
+		pagination.Finish(page, &items, key)
+
+		assert.Equal(t, []string{"d", "c"}, ids(items))
+		assert.NotEmpty(t, page.PrevCursor)
+		assert.NotEmpty(t, page.NextCursor)
+
+		next, prev := page.NextCursor, page.PrevCursor
+		last := items[1]
+
+		page, _ = parse(t, "limit=2&cursor="+next)
+		items = fetch(t, rows, page, &last, true)
+		pagination.Finish(page, &items, key)
+
+		assert.Equal(t, []string{"b"}, ids(items))
+		assert.NotEmpty(t, page.PrevCursor)
+		assert.Empty(t, page.NextCursor)
+
+		page, _ = parse(t, "limit=2&cursor="+prev)
+		items = fetch(t, rows, page, &row{ID: "d", CreatedAt: rows[2].CreatedAt}, false)
+		pagination.Finish(page, &items, key)
+
+		assert.Equal(t, []string{"f", "e"}, ids(items))
+		assert.Empty(t, page.PrevCursor)
+		assert.NotEmpty(t, page.NextCursor)
+	})
+
+	t.Run("leaves both cursors empty for an empty page", func(t *testing.T) {
+		page, _ := parse(t, "")
+		items := []row{}
+		pagination.Finish(page, &items, key)
+
+		assert.Empty(t, page.NextCursor)
+		assert.Empty(t, page.PrevCursor)
+	})
+}
@@ -0,0 +1,53 @@
+// Package privacy hides what private accounts post from the users who don't
+// follow them.
+package privacy
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+
+	"gorm.io/gorm"
+)
+
+// Visible narrows a query to the rows whose owner, the user id in column,
+// the principal acting in ctx may see: public accounts, the principal
+// itself, and the private accounts it follows. Admins and moderators see
+// everything, and so do queries made outside a request, without a
+// principal, like the ones of background workers.
+func Visible(ctx context.Context, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		principal, ok := viewer(ctx)
+
+		if !ok {
+			return db
+		}
+
+		return db.Where("("+column+" IN (SELECT id FROM users WHERE private = false) OR "+column+" = ? OR "+column+" IN (SELECT followee_id FROM follows WHERE follower_id = ? AND status = ?))", principal.UserID, principal.UserID, domain.FollowStatusAccepted)
+	}
+}
+
+// VisibleImages narrows a query to the rows whose image, the image id in
+// column, the principal acting in ctx may see, as Visible does for owners.
+func VisibleImages(ctx context.Context, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if _, ok := viewer(ctx); !ok {
+			return db
+		}
+
+		images := db.Session(&gorm.Session{NewDB: true}).Model(&domain.Image{}).Select("id").Scopes(Visible(ctx, "user_id"))
+
+		return db.Where(column+" IN (?)", images)
+	}
+}
+
+// viewer returns the principal acting in ctx, and reports false when there
+// is nothing to hide from it.
+func viewer(ctx context.Context) (domain.Principal, bool) {
+	principal, ok := domain.PrincipalFrom(ctx)
+
+	if !ok || principal.HasRole(domain.RoleAdmin) || principal.HasRole(domain.RoleModerator) {
+		return domain.Principal{}, false
+	}
+
+	return principal, true
+}
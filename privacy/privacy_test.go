@@ -0,0 +1,46 @@
+package privacy_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/privacy"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestVisible(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+
+	assert.NoError(t, err)
+
+	viewer := domain.WithPrincipal(context.Background(), domain.Principal{UserID: "user-123", Roles: []string{domain.RoleUser}})
+
+	t.Run("hide the images of private accounts the viewer doesn't follow", func(t *testing.T) {
+		statement := db.Scopes(privacy.Visible(viewer, "user_id")).Find(&[]domain.Image{}).Statement
+
+		assert.Equal(t, `SELECT * FROM "images" WHERE (user_id IN (SELECT id FROM users WHERE private = false) OR user_id = $1 OR user_id IN (SELECT followee_id FROM follows WHERE follower_id = $2 AND status = $3))`, statement.SQL.String())
+		assert.Equal(t, []interface{}{"user-123", "user-123", domain.FollowStatusAccepted}, statement.Vars)
+	})
+
+	t.Run("hide the comments on images the viewer can't see", func(t *testing.T) {
+		statement := db.Scopes(privacy.VisibleImages(viewer, "image_id")).Find(&[]domain.Comment{}).Statement
+
+		assert.Equal(t, `SELECT * FROM "comments" WHERE image_id IN (SELECT "id" FROM "images" WHERE (user_id IN (SELECT id FROM users WHERE private = false) OR user_id = $1 OR user_id IN (SELECT followee_id FROM follows WHERE follower_id = $2 AND status = $3)))`, statement.SQL.String())
+	})
+
+	for name, ctx := range map[string]context.Context{
+		"show everything to admins":            domain.WithPrincipal(context.Background(), domain.Principal{UserID: "user-234", Roles: []string{domain.RoleAdmin}}),
+		"show everything outside of a request": context.Background(),
+	} {
+		ctx := ctx
+
+		t.Run(name, func(t *testing.T) {
+			statement := db.Scopes(privacy.Visible(ctx, "user_id"), privacy.VisibleImages(ctx, "image_id")).Find(&[]domain.Comment{}).Statement
+
+			assert.Equal(t, `SELECT * FROM "comments"`, statement.SQL.String())
+		})
+	}
+}
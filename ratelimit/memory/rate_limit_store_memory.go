@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets that have filled up again are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens     float64
+	refilledAt time.Time
+	fullAt     time.Time
+}
+
+// rateLimitStore keeps the buckets in process memory. Every replica limits
+// on its own, so a client spreading requests over n replicas gets n times
+// the limit.
+type rateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	sweepAt time.Time
+}
+
+func NewRateLimitStore() *rateLimitStore {
+	return &rateLimitStore{buckets: map[string]*bucket{}}
+}
+
+func (rateLimitStore *rateLimitStore) Take(ctx context.Context, key string, policy domain.RateLimitPolicy) (result domain.RateLimitResult, err error) {
+	rateLimitStore.mu.Lock()
+
+	defer rateLimitStore.mu.Unlock()
+
+	// The clock is read under the lock, so buckets are refilled in the
+	// order requests take from them and never go back in time.
+	now := time.Now()
+
+	if !now.Before(rateLimitStore.sweepAt) {
+		for k, b := range rateLimitStore.buckets {
+			if !now.Before(b.fullAt) {
+				delete(rateLimitStore.buckets, k)
+			}
+		}
+
+		rateLimitStore.sweepAt = now.Add(sweepInterval)
+	}
+
+	b, ok := rateLimitStore.buckets[key]
+
+	if !ok {
+		b = &bucket{tokens: float64(policy.Limit), refilledAt: now}
+		rateLimitStore.buckets[key] = b
+	}
+
+	b.tokens, result = policy.Take(b.tokens, now.Sub(b.refilledAt))
+	b.refilledAt = now
+	b.fullAt = now.Add(result.ResetAfter)
+
+	return result, nil
+}
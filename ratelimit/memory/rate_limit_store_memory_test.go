@@ -0,0 +1,60 @@
+package ratelimit_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"testing"
+	"time"
+
+	ratelimit "mygram-byferdiansyah/ratelimit/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+	policy := domain.RateLimitPolicy{Limit: 3, Period: 300 * time.Millisecond}
+
+	t.Run("take the burst and then one request for every refilled token", func(t *testing.T) {
+		store := ratelimit.NewRateLimitStore()
+
+		for remaining := 2; remaining >= 0; remaining-- {
+			result, err := store.Take(ctx, "ip:192.0.2.1", policy)
+
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, remaining, result.Remaining)
+		}
+
+		result, err := store.Take(ctx, "ip:192.0.2.1", policy)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.InDelta(t, 100*time.Millisecond, result.RetryAfter, float64(10*time.Millisecond))
+		assert.InDelta(t, 300*time.Millisecond, result.ResetAfter, float64(10*time.Millisecond))
+
+		time.Sleep(110 * time.Millisecond)
+
+		result, _ = store.Take(ctx, "ip:192.0.2.1", policy)
+
+		assert.True(t, result.Allowed)
+
+		result, _ = store.Take(ctx, "ip:192.0.2.1", policy)
+
+		assert.False(t, result.Allowed)
+	})
+
+	t.Run("keep a bucket for every key", func(t *testing.T) {
+		store := ratelimit.NewRateLimitStore()
+
+		for i := 0; i < 3; i++ {
+			store.Take(ctx, "ip:192.0.2.1", policy)
+		}
+
+		result, err := store.Take(ctx, "ip:192.0.2.2", policy)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+}
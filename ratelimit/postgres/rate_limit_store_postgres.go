@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sweepInterval is how often buckets that have filled up again are deleted.
+const sweepInterval = time.Minute
+
+// rateLimitStore keeps the buckets in Postgres so every replica takes from
+// the same ones. A bucket is locked while a request is taken out of it.
+type rateLimitStore struct {
+	db      *gorm.DB
+	mu      sync.Mutex
+	sweepAt time.Time
+}
+
+func NewRateLimitStore(db *gorm.DB) *rateLimitStore {
+	return &rateLimitStore{db: db}
+}
+
+func (rateLimitStore *rateLimitStore) Take(ctx context.Context, key string, policy domain.RateLimitPolicy) (result domain.RateLimitResult, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = rateLimitStore.sweep(ctx); err != nil {
+		return result, err
+	}
+
+	err = rateLimitStore.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var now time.Time
+
+		bucket := domain.RateLimitBucket{}
+
+		if err := tx.Model(&bucket).Clauses(clause.OnConflict{DoNothing: true}).Create(map[string]interface{}{
+			"key":         key,
+			"tokens":      float64(policy.Limit),
+			"refilled_at": gorm.Expr("clock_timestamp()"),
+			"full_at":     gorm.Expr("clock_timestamp()"),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).Take(&bucket).Error; err != nil {
+			return err
+		}
+
+		// The clock is read once the bucket is locked and from the database
+		// alone, so neither waiting for the lock nor the clocks of the
+		// replicas drifting apart refills it.
+		if err := tx.Raw("SELECT clock_timestamp()").Row().Scan(&now); err != nil {
+			return err
+		}
+
+		tokens, taken := policy.Take(bucket.Tokens, now.Sub(*bucket.RefilledAt))
+		fullAt := now.Add(taken.ResetAfter)
+		result = taken
+
+		return tx.Model(&domain.RateLimitBucket{}).Where("key = ?", key).Updates(map[string]interface{}{
+			"tokens":      tokens,
+			"refilled_at": now,
+			"full_at":     fullAt,
+		}).Error
+	})
+
+	return result, err
+}
+
+// sweep deletes the buckets that have filled up again, at most once every
+// sweepInterval.
+func (rateLimitStore *rateLimitStore) sweep(ctx context.Context) (err error) {
+	now := time.Now()
+
+	rateLimitStore.mu.Lock()
+
+	if now.Before(rateLimitStore.sweepAt) {
+		rateLimitStore.mu.Unlock()
+
+		return
+	}
+
+	rateLimitStore.sweepAt = now.Add(sweepInterval)
+	rateLimitStore.mu.Unlock()
+
+	if err = rateLimitStore.db.WithContext(ctx).Where("full_at < now()").Delete(&domain.RateLimitBucket{}).Error; err != nil {
+		return err
+	}
+
+	return
+}
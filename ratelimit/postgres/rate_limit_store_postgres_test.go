@@ -0,0 +1,76 @@
+package ratelimit_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/config/database"
+	"mygram-byferdiansyah/domain"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	ratelimit "mygram-byferdiansyah/ratelimit/postgres"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	policy := domain.RateLimitPolicy{Limit: 5, Period: time.Minute}
+	suffix, _ := gonanoid.New(10)
+	key := "test:ip:" + suffix
+
+	t.Cleanup(func() {
+		db.Delete(&domain.RateLimitBucket{}, "key = ?", key)
+	})
+
+	t.Run("let the limit through across concurrent requests", func(t *testing.T) {
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			allowed int
+		)
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				result, err := ratelimit.NewRateLimitStore(db).Take(ctx, key, policy)
+
+				assert.NoError(t, err)
+
+				if result.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 5, allowed)
+	})
+}
@@ -1,30 +1,36 @@
 package delivery
 
 import (
+	"errors"
+	"fmt"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/helpers"
-	"mygram-byferdiansyah/socialmedia/delivery/http/middleware"
+	"mygram-byferdiansyah/middleware"
+	"mygram-byferdiansyah/pagination"
 	"mygram-byferdiansyah/socialmedia/utils"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type socialMediaHandler struct {
 	socialMediaUseCase domain.SocialMediaUseCase
 }
 
-func NewSocialMediaHandler(routers *gin.Engine, socialMediaUseCase domain.SocialMediaUseCase) {
+func NewSocialMediaHandler(routers *gin.Engine, socialMediaUseCase domain.SocialMediaUseCase, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, rateLimiter *middleware.RateLimiter) {
 	handler := &socialMediaHandler{socialMediaUseCase}
 
 	router := routers.Group("/socialmedias")
 	{
-		router.Use(middleware.Authentication())
+		router.Use(rateLimiter.LimitAddress(domain.RateLimitDefault))
+		router.Use(middleware.Authentication(tokenManager, tokenRevocationUseCase))
+		router.Use(rateLimiter.Limit(domain.RateLimitDefault))
 		router.GET("", handler.Get)
 		router.POST("", handler.Create)
-		router.PUT("/:socialMediaId", middleware.Authorization(handler.socialMediaUseCase), handler.Edit)
-		router.DELETE("/:socialMediaId", middleware.Authorization(handler.socialMediaUseCase), handler.Delete)
+		router.GET("/:socialMediaId", handler.GetByID)
+		router.PUT("/:socialMediaId", middleware.RequireOwner[domain.SocialMedia](handler.socialMediaUseCase, "social media", "socialMediaId"), handler.Edit)
+		router.DELETE("/:socialMediaId", middleware.RequireOwner[domain.SocialMedia](handler.socialMediaUseCase, "social media", "socialMediaId"), handler.Delete)
 	}
 }
 
@@ -34,6 +40,11 @@ func NewSocialMediaHandler(routers *gin.Engine, socialMediaUseCase domain.Social
 // @Tags        socialmedias
 // @Accept      json
 // @Produce     json
+// @Param       limit						query			int			false	"Page size, at most 100"	default(20)
+// @Param       cursor					query			string	false	"next_cursor or prev_cursor of a previous page"
+// @Param       sort						query			string	false	"Order by creation time"	Enums(asc, desc)	default(desc)
+// @Param       created_after		query			string	false	"RFC 3339 time"
+// @Param       created_before	query			string	false	"RFC 3339 time"
 // @Success     200	{object}	utils.ResponseDataGetedSocialMedia
 // @Failure     400	{object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
@@ -45,10 +56,20 @@ func (handler *socialMediaHandler) Get(ctx *gin.Context) {
 		err          error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	page, err := pagination.Parse(ctx)
 
-	if err = handler.socialMediaUseCase.Get(ctx.Request.Context(), &socialMedias, userID); err != nil {
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = handler.socialMediaUseCase.Get(ctx.Request.Context(), &socialMedias, principal.UserID, page); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail cant find the id",
 			Message: err.Error(),
@@ -62,6 +83,67 @@ func (handler *socialMediaHandler) Get(ctx *gin.Context) {
 		Data: utils.GetedSocialMedia{
 			SocialMedias: socialMedias,
 		},
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+// GetByID godoc
+// @Summary    	Get a social media
+// @Description	Get a social media by id with authentication user
+// @Tags        socialmedias
+// @Accept      json
+// @Produce     json
+// @Param       socialMediaId	path			string	true	"Social Media ID"
+// @Success     200						{object}	utils.ResponseDataGetedSocialMediaByID
+// @Failure     401						{object}	utils.ResponseMessage
+// @Failure     404						{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Router      /socialmedias/{socialMediaId}	[get]
+func (handler *socialMediaHandler) GetByID(ctx *gin.Context) {
+	var (
+		socialMedia domain.SocialMedia
+		err         error
+	)
+
+	socialMediaID := ctx.Param("socialMediaId")
+
+	if err = handler.socialMediaUseCase.GetByID(ctx.Request.Context(), &socialMedia, socialMediaID); err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("social media with id %s doesn't exist", socialMediaID)
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	geted := utils.SocialMedia{
+		ID:             socialMedia.ID,
+		Name:           socialMedia.Name,
+		SocialMediaUrl: socialMedia.SocialMediaUrl,
+		UserID:         socialMedia.UserID,
+		CreatedAt:      socialMedia.CreatedAt,
+		UpdatedAt:      socialMedia.UpdatedAt,
+	}
+
+	if socialMedia.User != nil {
+		geted.User = &utils.User{
+			ID:       socialMedia.User.ID,
+			Username: socialMedia.User.Username,
+			Email:    socialMedia.User.Email,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   geted,
 	})
 }
 
@@ -83,8 +165,7 @@ func (handler *socialMediaHandler) Create(ctx *gin.Context) {
 		err         error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	principal, _ := middleware.GetPrincipal(ctx)
 
 	if err = ctx.ShouldBindJSON(&socialMedia); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -95,7 +176,7 @@ func (handler *socialMediaHandler) Create(ctx *gin.Context) {
 		return
 	}
 
-	socialMedia.UserID = userID
+	socialMedia.UserID = principal.UserID
 
 	if err = handler.socialMediaUseCase.Create(ctx.Request.Context(), &socialMedia); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -129,6 +210,7 @@ func (handler *socialMediaHandler) Create(ctx *gin.Context) {
 // @Success     200		{object}	utils.ResponseDataEditedSocialMedia
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
 // @Failure     404		{object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /socialmedias/{id} [put]
@@ -139,8 +221,7 @@ func (handler *socialMediaHandler) Edit(ctx *gin.Context) {
 	)
 
 	socialMediaID := ctx.Param("socialMediaId")
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	principal, _ := middleware.GetPrincipal(ctx)
 
 	if err = ctx.ShouldBindJSON(&socialMedia); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -152,7 +233,7 @@ func (handler *socialMediaHandler) Edit(ctx *gin.Context) {
 	}
 
 	editedSocialMedia := domain.SocialMedia{
-		UserID:         userID,
+		UserID:         principal.UserID,
 		Name:           socialMedia.Name,
 		SocialMediaUrl: socialMedia.SocialMediaUrl,
 	}
@@ -185,6 +266,7 @@ func (handler *socialMediaHandler) Edit(ctx *gin.Context) {
 // @Success     200  {object}	utils.ResponseMessageDeletedSocialMedia
 // @Failure     400  {object}	utils.ResponseMessage
 // @Failure     401  {object}	utils.ResponseMessage
+// @Failure     403  {object}	utils.ResponseMessage
 // @Failure     404  {object}	utils.ResponseMessage
 // @Security    Bearer
 // @Router      /socialmedias/{id} [delete]
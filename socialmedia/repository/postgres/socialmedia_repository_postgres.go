@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -18,17 +19,21 @@ func NewSocialMediaRepository(db *gorm.DB) *socialMediaRepository {
 	return &socialMediaRepository{db}
 }
 
-func (socialMediaRepository *socialMediaRepository) Get(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string) (err error) {
+func (socialMediaRepository *socialMediaRepository) Get(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string, page *pagination.Page) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = socialMediaRepository.db.WithContext(ctx).Where("user_id = ?", userID).Preload("User", func(db *gorm.DB) *gorm.DB {
+	if err = page.Query(socialMediaRepository.db.WithContext(ctx).Where("user_id = ?", userID)).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("ID", "Email", "Username", "ProfileImageUrl")
 	}).Find(&socialMedias).Error; err != nil {
 		return err
 	}
 
+	pagination.Finish(page, socialMedias, func(socialMedia domain.SocialMedia) (*time.Time, string) {
+		return socialMedia.CreatedAt, socialMedia.ID
+	})
+
 	return
 }
 
@@ -53,7 +58,9 @@ func (socialMediaRepository *socialMediaRepository) GetByID(ctx context.Context,
 
 	defer cancel()
 
-	if err = socialMediaRepository.db.WithContext(ctx).First(&socialMedia, &id).Error; err != nil {
+	if err = socialMediaRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("ID", "Email", "Username", "ProfileImageUrl")
+	}).First(&socialMedia, &id).Error; err != nil {
 		return err
 	}
 
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/pagination"
 )
 
 type socialMediaUseCase struct {
@@ -13,8 +14,8 @@ func NewSocialMediaUseCase(socialMediaRepository domain.SocialMediaRepository) *
 	return &socialMediaUseCase{socialMediaRepository}
 }
 
-func (socialMediaUseCase *socialMediaUseCase) Get(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string) (err error) {
-	if err = socialMediaUseCase.socialMediaRepository.Get(ctx, socialMedias, userID); err != nil {
+func (socialMediaUseCase *socialMediaUseCase) Get(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string, page *pagination.Page) (err error) {
+	if err = socialMediaUseCase.socialMediaRepository.Get(ctx, socialMedias, userID, page); err != nil {
 		return err
 	}
 
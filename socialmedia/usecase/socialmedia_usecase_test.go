@@ -5,6 +5,7 @@ import (
 	"errors"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/pagination"
 	"testing"
 	"time"
 
@@ -34,9 +35,9 @@ func TestGet(t *testing.T) {
 	socialMediaUseCase := socialMediaUseCase.NewSocialMediaUseCase(mockSocialMediaRepository)
 
 	t.Run("get all social media correctly", func(t *testing.T) {
-		mockSocialMediaRepository.On("Get", mock.Anything, mock.AnythingOfType("*[]domain.SocialMedia"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockSocialMediaRepository.On("Get", mock.Anything, mock.AnythingOfType("*[]domain.SocialMedia"), mock.AnythingOfType("string"), mock.AnythingOfType("*pagination.Page")).Return(nil).Once()
 
-		err := socialMediaUseCase.Get(context.Background(), &mockSocialMedias, mockSocialMedia.UserID)
+		err := socialMediaUseCase.Get(context.Background(), &mockSocialMedias, mockSocialMedia.UserID, &pagination.Page{Limit: pagination.DefaultLimit})
 
 		assert.NoError(t, err)
 	})
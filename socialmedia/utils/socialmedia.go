@@ -27,8 +27,15 @@ type GetedSocialMedia struct {
 }
 
 type ResponseDataGetedSocialMedia struct {
-	Status string       `json:"status" example:"success"`
-	Data   SocialMedias `json:"data"`
+	Status     string       `json:"status" example:"success"`
+	Data       SocialMedias `json:"data"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	PrevCursor string       `json:"prev_cursor,omitempty"`
+}
+
+type ResponseDataGetedSocialMediaByID struct {
+	Status string      `json:"status" example:"success"`
+	Data   SocialMedia `json:"data"`
 }
 
 type AddSocialMedia struct {
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mygram-byferdiansyah/domain"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errInvalidKey = errors.New("the file key is invalid")
+
+type blobStore struct {
+	dir string
+}
+
+func NewBlobStore(dir string) *blobStore {
+	return &blobStore{dir}
+}
+
+// Put writes body to a temporary file first so readers never see a partially
+// written blob.
+func (blobStore *blobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (err error) {
+	path, err := blobStore.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err = io.Copy(file, body); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (blobStore *blobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := blobStore.path(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrBlobNotFound
+	}
+
+	return file, err
+}
+
+func (blobStore *blobStore) Delete(ctx context.Context, key string) (err error) {
+	path, err := blobStore.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path maps key inside the store directory, refusing keys that would escape
+// it.
+func (blobStore *blobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || !fs.ValidPath(key) {
+		return "", errInvalidKey
+	}
+
+	return filepath.Join(blobStore.dir, filepath.FromSlash(key)), nil
+}
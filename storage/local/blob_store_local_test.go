@@ -0,0 +1,50 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"mygram-byferdiansyah/domain"
+	"strings"
+	"testing"
+
+	blobStore "mygram-byferdiansyah/storage/local"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("put, get and delete a blob", func(t *testing.T) {
+		store := blobStore.NewBlobStore(t.TempDir())
+
+		err := store.Put(ctx, "images/user-123/a.png", strings.NewReader("png"), 3, "image/png")
+
+		assert.NoError(t, err)
+
+		body, err := store.Get(ctx, "images/user-123/a.png")
+
+		assert.NoError(t, err)
+
+		content, _ := io.ReadAll(body)
+		body.Close()
+
+		assert.Equal(t, "png", string(content))
+		assert.NoError(t, store.Delete(ctx, "images/user-123/a.png"))
+
+		_, err = store.Get(ctx, "images/user-123/a.png")
+
+		assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+	})
+
+	t.Run("refuse keys outside the store", func(t *testing.T) {
+		store := blobStore.NewBlobStore(t.TempDir())
+
+		assert.Error(t, store.Put(ctx, "../escape.png", strings.NewReader("png"), 3, "image/png"))
+		assert.Error(t, store.Put(ctx, "/etc/escape.png", strings.NewReader("png"), 3, "image/png"))
+
+		_, err := store.Get(ctx, "images/../../escape.png")
+
+		assert.Error(t, err)
+	})
+}
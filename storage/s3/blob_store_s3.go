@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mygram-byferdiansyah/domain"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// blobStore talks to any S3-compatible service over its REST API using
+// path-style addressing, which MinIO and most self-hosted services expect.
+type blobStore struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+func NewBlobStore(endpoint string, region string, bucket string, accessKey string, secretKey string) (*blobStore, error) {
+	parsed, err := url.Parse(endpoint)
+
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+
+	return &blobStore{parsed, region, bucket, accessKey, secretKey, &http.Client{Timeout: time.Minute}}, nil
+}
+
+func (blobStore *blobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (err error) {
+	req, err := blobStore.request(ctx, http.MethodPut, key, body)
+
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := blobStore.do(req)
+
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (blobStore *blobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := blobStore.request(ctx, http.MethodGet, key, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := blobStore.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+func (blobStore *blobStore) Delete(ctx context.Context, key string) (err error) {
+	req, err := blobStore.request(ctx, http.MethodDelete, key, nil)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := blobStore.do(req)
+
+	if err != nil && err != domain.ErrBlobNotFound {
+		return err
+	}
+
+	if res != nil {
+		return res.Body.Close()
+	}
+
+	return nil
+}
+
+func (blobStore *blobStore) request(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	target := *blobStore.endpoint
+
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + blobStore.bucket + "/" + key
+
+	return http.NewRequestWithContext(ctx, method, target.String(), body)
+}
+
+// do signs and sends req, turning a 404 into domain.ErrBlobNotFound and any
+// other non-2xx response into an error.
+func (blobStore *blobStore) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	sign(req, blobStore.region, blobStore.accessKey, blobStore.secretKey, time.Now())
+
+	res, err := blobStore.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrBlobNotFound
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, res.Status, message)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header covering the host
+// and every header already set on req. The payload hash is taken from the
+// X-Amz-Content-Sha256 header.
+func sign(req *http.Request, region string, accessKey string, secretKey string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, encode(name, true)+"="+encode(value, true))
+		}
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+func encodePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return encode(path, false)
+}
+
+// encode percent-encodes everything but the unreserved characters, and the
+// slash too when encodeSlash is set, as Signature Version 4 requires.
+func encode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return encoded.String()
+}
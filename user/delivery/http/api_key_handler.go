@@ -0,0 +1,182 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"mygram-byferdiansyah/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type apiKeyHandler struct {
+	apiKeyUseCase domain.APIKeyUseCase
+}
+
+// NewAPIKeyHandler registers the routes users manage their API keys with.
+// They only accept access tokens, so a leaked key can't be used to issue
+// more keys.
+func NewAPIKeyHandler(routers *gin.Engine, apiKeyUseCase domain.APIKeyUseCase, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, rateLimiter *middleware.RateLimiter) {
+	handler := &apiKeyHandler{apiKeyUseCase}
+
+	router := routers.Group("/users/api-keys")
+	{
+		router.Use(rateLimiter.LimitAddress(domain.RateLimitDefault))
+		router.Use(middleware.Authentication(tokenManager, tokenRevocationUseCase))
+		router.Use(rateLimiter.Limit(domain.RateLimitDefault))
+		router.GET("", handler.Get)
+		router.POST("", handler.Create)
+		router.DELETE("/:apiKeyId", handler.Revoke)
+	}
+}
+
+// Get godoc
+// @Summary			Get the API keys
+// @Description	List the API keys of the authentication user, newest first, including the revoked ones. The keys themselves are never shown again after they are created, only their prefix
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	utils.ResponseDataAPIKeys
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/api-keys		[get]
+func (handler *apiKeyHandler) Get(ctx *gin.Context) {
+	var apiKeys []domain.APIKey
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err := handler.apiKeyUseCase.GetByUser(ctx.Request.Context(), &apiKeys, principal.UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	data := make([]utils.APIKey, 0, len(apiKeys))
+
+	for _, apiKey := range apiKeys {
+		data = append(data, newAPIKey(apiKey))
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   data,
+	})
+}
+
+// Create godoc
+// @Summary			Create an API key
+// @Description	Create an API key for the authentication user to call the API from scripts, sent as "Bearer <key>" in the Authorization header. A key can only call the routes one of its scopes (images:read, images:write, comments:read, comments:write) allows, and works until it is revoked or expires_at passes. The key is only shown once
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.CreateAPIKey	true	"Create API Key"
+// @Success			201		{object}	utils.ResponseDataCreatedAPIKey
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/api-keys		[post]
+func (handler *apiKeyHandler) Create(ctx *gin.Context) {
+	var (
+		create utils.CreateAPIKey
+		err    error
+	)
+
+	if err = ctx.ShouldBindJSON(&create); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	apiKey := domain.APIKey{
+		UserID:    principal.UserID,
+		Name:      create.Name,
+		Scopes:    create.Scopes,
+		ExpiresAt: create.ExpiresAt,
+	}
+
+	if err = handler.apiKeyUseCase.Create(ctx.Request.Context(), &apiKey); err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, domain.ErrUnknownScope) || errors.Is(err, domain.ErrAPIKeyScopes) || errors.Is(err, domain.ErrAPIKeyExpiry) {
+			status = http.StatusBadRequest
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.ResponseData{
+		Status: "success",
+		Data: utils.CreatedAPIKey{
+			APIKey: newAPIKey(apiKey),
+			Key:    apiKey.Key,
+		},
+	})
+}
+
+// Revoke godoc
+// @Summary			Revoke an API key
+// @Description	Revoke an API key of the authentication user, which stops working right away
+// @Tags				users
+// @Produce			json
+// @Param				apiKeyId	path		string	true	"API Key ID"
+// @Success			200		{object}	utils.ResponseMessageAPIKey
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			404		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/api-keys/{apiKeyId}		[delete]
+func (handler *apiKeyHandler) Revoke(ctx *gin.Context) {
+	apiKeyID := ctx.Param("apiKeyId")
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err := handler.apiKeyUseCase.Revoke(ctx.Request.Context(), principal.UserID, apiKeyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("API key with id %s doesn't exist", apiKeyID),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the API key has been successfully revoked",
+	})
+}
+
+func newAPIKey(apiKey domain.APIKey) utils.APIKey {
+	return utils.APIKey{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		LastUsedAt: apiKey.LastUsedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,33 @@
+package delivery
+
+import (
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/middleware"
+	"mygram-byferdiansyah/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type jwksHandler struct {
+	tokenManager *helpers.TokenManager
+}
+
+func NewJWKSHandler(routers *gin.Engine, tokenManager *helpers.TokenManager, rateLimiter *middleware.RateLimiter) {
+	handler := &jwksHandler{tokenManager}
+
+	routers.GET("/.well-known/jwks.json", rateLimiter.Limit(domain.RateLimitDefault), handler.Get)
+}
+
+// Get godoc
+// @Summary			Get the token verification keys
+// @Description	List the public keys access tokens are signed with as a JSON Web Key Set, matched to tokens by the kid header. Keys scheduled to sign tokens later are listed ahead of time, and the list is empty while tokens are signed with the shared HS256 key
+// @Tags        users
+// @Produce     json
+// @Success     200	{object}	utils.JWKS
+// @Router      /.well-known/jwks.json	[get]
+func (handler *jwksHandler) Get(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, utils.JWKS{Keys: handler.tokenManager.JWKS()})
+}
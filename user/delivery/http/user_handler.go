@@ -1,36 +1,73 @@
 package delivery
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/helpers"
-	"mygram-byferdiansyah/user/delivery/http/middleware"
+	"mygram-byferdiansyah/middleware"
 	"mygram-byferdiansyah/user/utils"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type userHandler struct {
-	userUseCase domain.UserUseCase
+	userUseCase              domain.UserUseCase
+	loginAttemptUseCase      domain.LoginAttemptUseCase
+	refreshTokenUseCase      domain.RefreshTokenUseCase
+	passwordUseCase          domain.PasswordUseCase
+	emailVerificationUseCase domain.EmailVerificationUseCase
+	twoFactorUseCase         domain.TwoFactorUseCase
+	followUseCase            domain.FollowUseCase
+	tokenRevocationUseCase   domain.TokenRevocationUseCase
+	tokenManager             *helpers.TokenManager
+	urlSigner                *helpers.URLSigner
+	rateLimiter              *middleware.RateLimiter
+	maxUploadSize            int64
 }
 
-func NewUserHandler(routers *gin.Engine, userUseCase domain.UserUseCase) {
-	handler := &userHandler{userUseCase}
+func NewUserHandler(routers *gin.Engine, userUseCase domain.UserUseCase, loginAttemptUseCase domain.LoginAttemptUseCase, refreshTokenUseCase domain.RefreshTokenUseCase, passwordUseCase domain.PasswordUseCase, emailVerificationUseCase domain.EmailVerificationUseCase, twoFactorUseCase domain.TwoFactorUseCase, followUseCase domain.FollowUseCase, urlSigner *helpers.URLSigner, maxUploadSize int64, tokenManager *helpers.TokenManager, tokenRevocationUseCase domain.TokenRevocationUseCase, rateLimiter *middleware.RateLimiter) {
+	handler := &userHandler{userUseCase, loginAttemptUseCase, refreshTokenUseCase, passwordUseCase, emailVerificationUseCase, twoFactorUseCase, followUseCase, tokenRevocationUseCase, tokenManager, urlSigner, rateLimiter, maxUploadSize}
 
+	authentication := middleware.Authentication(tokenManager, tokenRevocationUseCase)
+	addressLimit := rateLimiter.LimitAddress(domain.RateLimitDefault)
+	rateLimit := rateLimiter.Limit(domain.RateLimitDefault)
+
+	// The signed in routes are limited per address in front of
+	// authentication, so wrong tokens are limited too, and per user behind
+	// it, so the requests of a user are counted together.
 	router := routers.Group("/users")
 	{
-		router.POST("/register", handler.Register)
-		router.POST("/login", handler.Login)
-		router.PUT("", middleware.Authentication(), handler.Edit)
-		router.DELETE("", middleware.Authentication(), handler.Delete)
+		router.POST("/register", rateLimit, handler.Register)
+		router.POST("/login", rateLimit, handler.Login)
+		router.POST("/login/2fa", rateLimit, rateLimiter.Limit(domain.RateLimitLoginTwoFactor), handler.LoginTwoFactor)
+		router.POST("/refresh", rateLimit, handler.Refresh)
+		router.POST("/logout", rateLimit, middleware.OptionalAuthentication(tokenManager, tokenRevocationUseCase), handler.Logout)
+		router.POST("/password/forgot", rateLimit, handler.ForgotPassword)
+		router.POST("/password/reset", rateLimit, handler.ResetPassword)
+		router.PUT("/password", addressLimit, authentication, rateLimit, handler.ChangePassword)
+		router.POST("/verify-email", rateLimit, rateLimiter.Limit(domain.RateLimitVerifyEmail), handler.VerifyEmail)
+		router.POST("/2fa", addressLimit, authentication, rateLimit, handler.EnrollTwoFactor)
+		router.POST("/2fa/confirm", addressLimit, authentication, rateLimit, handler.ConfirmTwoFactor)
+		router.POST("/2fa/disable", addressLimit, authentication, rateLimit, handler.DisableTwoFactor)
+		router.POST("/2fa/recovery-codes", addressLimit, authentication, rateLimit, handler.RegenerateRecoveryCodes)
+		router.POST("/verify-email/resend", addressLimit, authentication, rateLimit, rateLimiter.Limit(domain.RateLimitResendVerification), handler.ResendVerification)
+		router.GET("/:username", addressLimit, authentication, rateLimit, handler.GetProfile)
+		router.PUT("", addressLimit, authentication, rateLimit, handler.Edit)
+		router.DELETE("", addressLimit, authentication, rateLimit, handler.Delete)
 	}
 }
 
 // Register godoc
 // @Summary			Register a user
-// @Description	create and create a user
+// @Description	create and create a user. The account starts unverified and a verification token is emailed to it
 // @Tags				users
 // @Accept			json
 // @Produce			json
@@ -41,11 +78,11 @@ func NewUserHandler(routers *gin.Engine, userUseCase domain.UserUseCase) {
 // @Router			/users/register	[post]
 func (handler *userHandler) Register(ctx *gin.Context) {
 	var (
-		user domain.User
-		err  error
+		register utils.RegisterUser
+		err      error
 	)
 
-	if err = ctx.ShouldBindJSON(&user); err != nil {
+	if err = ctx.ShouldBindJSON(&register); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -54,20 +91,18 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 		return
 	}
 
-	if err = handler.userUseCase.Register(ctx.Request.Context(), &user); err != nil {
-		if strings.Contains(err.Error(), "idx_users_username") {
-			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
-				Status:  "fail",
-				Message: "the username you entered has been used",
-			})
-
-			return
-		}
+	user := domain.User{
+		Username: register.Username,
+		Email:    register.Email,
+		Password: register.Password,
+		Age:      register.Age,
+	}
 
-		if strings.Contains(err.Error(), "idx_users_email") {
+	if err = handler.userUseCase.Register(ctx.Request.Context(), &user); err != nil {
+		if errors.Is(err, domain.ErrUsernameTaken) || errors.Is(err, domain.ErrEmailTaken) {
 			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
 				Status:  "fail",
-				Message: "the email you entered has been used",
+				Message: err.Error(),
 			})
 
 			return
@@ -81,6 +116,8 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 		return
 	}
 
+	handler.sendVerification(ctx, user.ID)
+
 	ctx.JSON(http.StatusCreated, helpers.ResponseData{
 		Status: "success",
 		Data: utils.RegisteredUser{
@@ -94,7 +131,7 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 
 // Login godoc
 // @Summary			Login a user
-// @Description	Authentication a user and retrieve a token
+// @Description	Authentication a user and retrieve an access token and a refresh token. When the user has two-factor authentication enabled, the data is a utils.LoginChallenge to exchange at /users/login/2fa instead. Every failed login makes the next attempt for the account and from the address wait longer, and too many of them lock it out for a while
 // @Tags				users
 // @Accept			json
 // @Produce			json
@@ -102,12 +139,14 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 // @Success			200		{object}	utils.ResponseDataLoggedinUser
 // @Failure			400		{object}	utils.ResponseMessage
 // @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
+// @Failure			500		{object}	utils.ResponseMessage
 // @Router			/users/login		[post]
 func (handler *userHandler) Login(ctx *gin.Context) {
 	var (
-		user  domain.User
-		err   error
-		token string
+		user domain.User
+		err  error
 	)
 
 	if err = ctx.ShouldBindJSON(&user); err != nil {
@@ -119,64 +158,316 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	if err = handler.userUseCase.Login(ctx.Request.Context(), &user); err != nil {
-		if strings.Contains(err.Error(), "the credential you entered are wrong") {
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+	if err = handler.loginAttemptUseCase.Login(ctx.Request.Context(), &user, ctx.ClientIP()); err != nil {
+		var loginLockedError *domain.LoginLockedError
+
+		switch {
+		case errors.Is(err, domain.ErrUserSuspended):
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "forbidden",
+				Message: err.Error(),
+			})
+		case errors.As(err, &loginLockedError):
+			ctx.Header("Retry-After", strconv.Itoa(int(loginLockedError.RetryAfter.Seconds())+1))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
 				Status:  "unauthenticated",
 				Message: err.Error(),
 			})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+		}
+
+		return
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		loginChallenge := domain.LoginChallenge{UserID: user.ID}
+
+		if err = handler.twoFactorUseCase.Challenge(ctx.Request.Context(), &loginChallenge); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
 
 			return
 		}
 
+		ctx.JSON(http.StatusOK, helpers.ResponseData{
+			Status: "success",
+			Data: utils.LoginChallenge{
+				TwoFactorRequired: true,
+				ChallengeToken:    loginChallenge.Token,
+				ExpiresIn:         int64(time.Until(*loginChallenge.ExpiresAt).Seconds()),
+			},
+		})
+
+		return
+	}
+
+	handler.login(ctx, user)
+}
+
+// LoginTwoFactor godoc
+// @Summary			Finish a two-factor login
+// @Description	Exchange the challenge token returned by /users/login and a code from the authenticator app, or an unused recovery code, for an access token and a refresh token
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.LoginTwoFactor	true	"Login Two-Factor"
+// @Success			200		{object}	utils.ResponseDataLoggedinUser
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
+// @Router			/users/login/2fa		[post]
+func (handler *userHandler) LoginTwoFactor(ctx *gin.Context) {
+	var (
+		login  utils.LoginTwoFactor
+		user   domain.User
+		userID string
+		err    error
+	)
+
+	if err = ctx.ShouldBindJSON(&login); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if userID, err = handler.twoFactorUseCase.Verify(ctx.Request.Context(), login.ChallengeToken, login.Code); err != nil {
+		var loginLockedError *domain.LoginLockedError
+
+		if errors.As(err, &loginLockedError) {
+			ctx.Header("Retry-After", strconv.Itoa(int(loginLockedError.RetryAfter.Seconds())+1))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if errors.Is(err, domain.ErrChallengeInvalid) || errors.Is(err, domain.ErrInvalidCode) || errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.userUseCase.GetByID(ctx.Request.Context(), &user, userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
 			Status:  "unauthenticated",
+			Message: "account not found",
+		})
+
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+			Status:  "forbidden",
+			Message: domain.ErrUserSuspended.Error(),
+		})
+
+		return
+	}
+
+	handler.login(ctx, user)
+}
+
+// login starts a new session for user, whose credentials have all been
+// checked.
+func (handler *userHandler) login(ctx *gin.Context, user domain.User) {
+	refreshToken := domain.RefreshToken{UserID: user.ID}
+
+	if err := handler.refreshTokenUseCase.Create(ctx.Request.Context(), &refreshToken); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
 			Message: err.Error(),
 		})
 
 		return
 	}
 
-	if token = helpers.GenerateToken(user.ID, user.Email); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error":   "unauthenticated",
-			"message": err.Error(),
+	handler.respondWithTokens(ctx, user, refreshToken)
+}
+
+// Refresh godoc
+// @Summary			Refresh an access token
+// @Description	Exchange a refresh token for a new access token and a new refresh token. A refresh token can only be used once; reusing it logs the whole session out
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.RefreshUser	true	"Refresh Token"
+// @Success			200		{object}	utils.ResponseDataLoggedinUser
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Router			/users/refresh		[post]
+func (handler *userHandler) Refresh(ctx *gin.Context) {
+	var (
+		refresh      utils.RefreshUser
+		refreshToken domain.RefreshToken
+		user         domain.User
+		err          error
+	)
+
+	if err = ctx.ShouldBindJSON(&refresh); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
 		})
 
 		return
 	}
 
-	ctx.JSON(http.StatusOK, helpers.ResponseData{
-		Status: "success",
-		Data: utils.LoggedinUser{
-			Token: token,
-		},
-	})
+	if err = handler.refreshTokenUseCase.Rotate(ctx.Request.Context(), &refreshToken, refresh.RefreshToken); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenInvalid) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.userUseCase.GetByID(ctx.Request.Context(), &user, refreshToken.UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: "account not found",
+		})
+
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+			Status:  "forbidden",
+			Message: domain.ErrUserSuspended.Error(),
+		})
+
+		return
+	}
+
+	handler.respondWithTokens(ctx, user, refreshToken)
 }
 
-// Edit godoc
-// @Summary			Edit a user
-// @Description	Edit a user with authentication user
+// Logout godoc
+// @Summary			Logout a user
+// @Description	Revoke the refresh token and every token rotated from the same login. When a valid access token is sent as well, it stops working right away instead of when it expires, and an expired or invalid one doesn't stop the logout
 // @Tags				users
 // @Accept			json
 // @Produce			json
-// @Param				json		body			utils.EditUser   true  "Edit User"
-// @Success			200			{object}  utils.ResponseDataEditedUser
-// @Failure			400			{object}	utils.ResponseMessage
-// @Failure			401			{object}	utils.ResponseMessage
-// @Failure			409			{object}	utils.ResponseMessage
+// @Param				json	body			utils.RefreshUser	true	"Refresh Token"
+// @Success			200		{object}	utils.ResponseMessageLoggedoutUser
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
 // @Security		Bearer
-// @Router			/users	[put]
-func (handler *userHandler) Edit(ctx *gin.Context) {
+// @Router			/users/logout		[post]
+func (handler *userHandler) Logout(ctx *gin.Context) {
 	var (
-		user domain.User
-		err  error
+		refresh utils.RefreshUser
+		err     error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	_ = string(userData["id"].(string))
+	if err = ctx.ShouldBindJSON(&refresh); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
 
-	if err = ctx.ShouldBindJSON(&user); err != nil {
+		return
+	}
+
+	if err = handler.refreshTokenUseCase.Revoke(ctx.Request.Context(), refresh.RefreshToken); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenInvalid) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if principal, ok := middleware.GetPrincipal(ctx); ok {
+		if err = handler.tokenRevocationUseCase.Revoke(ctx.Request.Context(), domain.RevokedToken{
+			JTI:       principal.TokenID,
+			UserID:    principal.UserID,
+			ExpiresAt: &principal.ExpiresAt,
+		}); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "you have been successfully logged out",
+	})
+}
+
+// ChangePassword godoc
+// @Summary			Change the password
+// @Description	Change the password of the authentication user after checking the current one. Every session of the user is logged out and a new one is returned
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.ChangePassword	true	"Change Password"
+// @Success			200		{object}	utils.ResponseDataLoggedinUser
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/password		[put]
+func (handler *userHandler) ChangePassword(ctx *gin.Context) {
+	var (
+		change utils.ChangePassword
+		user   domain.User
+		err    error
+	)
+
+	if err = ctx.ShouldBindJSON(&change); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -185,12 +476,58 @@ func (handler *userHandler) Edit(ctx *gin.Context) {
 		return
 	}
 
-	editedUser := domain.User{
-		Username: user.Username,
-		Email:    user.Email,
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = handler.passwordUseCase.Change(ctx.Request.Context(), principal.UserID, change.CurrentPassword, change.NewPassword); err != nil {
+		status := http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, domain.ErrPasswordTooShort):
+			status = http.StatusBadRequest
+		case errors.Is(err, domain.ErrWrongPassword):
+			status = http.StatusForbidden
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusUnauthorized
+			err = errors.New("account not found")
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
 	}
 
-	if user, err = handler.userUseCase.Edit(ctx.Request.Context(), editedUser); err != nil {
+	if err = handler.userUseCase.GetByID(ctx.Request.Context(), &user, principal.UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: "account not found",
+		})
+
+		return
+	}
+
+	handler.login(ctx, user)
+}
+
+// ForgotPassword godoc
+// @Summary			Request a password reset
+// @Description	Email a single-use password reset token to the user with the email. Resets asked for an email are limited, and the response is the same whether or not the email belongs to an account or was limited
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.ForgotPassword	true	"Forgot Password"
+// @Success			202		{object}	utils.ResponseMessagePassword
+// @Failure			400		{object}	utils.ResponseMessage
+// @Router			/users/password/forgot		[post]
+func (handler *userHandler) ForgotPassword(ctx *gin.Context) {
+	var (
+		forgot utils.ForgotPassword
+		err    error
+	)
+
+	if err = ctx.ShouldBindJSON(&forgot); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -199,35 +536,676 @@ func (handler *userHandler) Edit(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, helpers.ResponseData{
-		Status: "success",
-		Data: utils.EditedUser{
-			ID:        user.ID,
-			Email:     user.Email,
-			Username:  user.Username,
-			Age:       user.Age,
-			UpdatedAt: user.UpdatedAt,
-		},
+	if handler.rateLimiter.Allow(ctx.Request.Context(), domain.RateLimitForgotPassword, "email:"+strings.ToLower(strings.TrimSpace(forgot.Email))) {
+		go handler.requestReset(forgot.Email)
+	}
+
+	ctx.JSON(http.StatusAccepted, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "if the email belongs to an account, a reset token has been sent to it",
 	})
 }
 
-// Delete godoc
-// @Summary			Delete a user
-// @Description	Delete a user with authentication user
+// ResetPassword godoc
+// @Summary			Reset the password
+// @Description	Set a new password with a reset token sent by email. The token can only be used once and every session of the user is logged out
 // @Tags				users
 // @Accept			json
 // @Produce			json
-// @Success			200			{object}	utils.ResponseMessageDeletedUser
-// @Failure			400			{object}	utils.ResponseMessage
-// @Failure			401			{object}	utils.ResponseMessage
-// @Failure			404			{object}	utils.ResponseMessage
-// @Security		Bearer
-// @Router			/users	[delete]
-func (handler *userHandler) Delete(ctx *gin.Context) {
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+// @Param				json	body			utils.ResetPassword	true	"Reset Password"
+// @Success			200		{object}	utils.ResponseMessagePassword
+// @Failure			400		{object}	utils.ResponseMessage
+// @Router			/users/password/reset		[post]
+func (handler *userHandler) ResetPassword(ctx *gin.Context) {
+	var (
+		reset utils.ResetPassword
+		err   error
+	)
+
+	if err = ctx.ShouldBindJSON(&reset); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.passwordUseCase.Reset(ctx.Request.Context(), reset.Token, reset.Password); err != nil {
+		if errors.Is(err, domain.ErrPasswordTooShort) || errors.Is(err, domain.ErrPasswordResetInvalid) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "your password has been successfully reset",
+	})
+}
+
+// EnrollTwoFactor godoc
+// @Summary			Enroll in two-factor authentication
+// @Description	Generate a TOTP secret and recovery codes for the authentication user. Add the otpauth URI to an authenticator app and confirm a code from it to turn two-factor authentication on. The recovery codes are only shown once
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Success			201		{object}	utils.ResponseDataTwoFactorEnrollment
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/2fa		[post]
+func (handler *userHandler) EnrollTwoFactor(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	enrollment, err := handler.twoFactorUseCase.Enroll(ctx.Request.Context(), principal.UserID)
+
+	if err != nil {
+		handler.abortTwoFactor(ctx, err, http.StatusBadRequest)
+
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.ResponseData{
+		Status: "success",
+		Data: utils.TwoFactorEnrollment{
+			Secret:        enrollment.Secret,
+			OtpauthURI:    enrollment.URI,
+			RecoveryCodes: enrollment.RecoveryCodes,
+		},
+	})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary			Confirm two-factor authentication
+// @Description	Turn two-factor authentication on for the authentication user with a code from the authenticator app it was enrolled in
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.ConfirmTwoFactor	true	"Confirm Two-Factor"
+// @Success			200		{object}	utils.ResponseMessageTwoFactor
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/2fa/confirm		[post]
+func (handler *userHandler) ConfirmTwoFactor(ctx *gin.Context) {
+	var (
+		confirm utils.ConfirmTwoFactor
+		err     error
+	)
+
+	if err = ctx.ShouldBindJSON(&confirm); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = handler.twoFactorUseCase.Confirm(ctx.Request.Context(), principal.UserID, confirm.Code); err != nil {
+		handler.abortTwoFactor(ctx, err, http.StatusBadRequest)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "two-factor authentication has been successfully enabled",
+	})
+}
+
+// DisableTwoFactor godoc
+// @Summary			Disable two-factor authentication
+// @Description	Turn two-factor authentication off for the authentication user, who has to enter the password and a code again
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.Reauthenticate	true	"Reauthenticate"
+// @Success			200		{object}	utils.ResponseMessageTwoFactor
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/2fa/disable		[post]
+func (handler *userHandler) DisableTwoFactor(ctx *gin.Context) {
+	var (
+		reauthenticate utils.Reauthenticate
+		err            error
+	)
+
+	if err = ctx.ShouldBindJSON(&reauthenticate); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err = handler.twoFactorUseCase.Disable(ctx.Request.Context(), principal.UserID, reauthenticate.Password, reauthenticate.Code); err != nil {
+		handler.abortTwoFactor(ctx, err, http.StatusForbidden)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "two-factor authentication has been successfully disabled",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary			Regenerate the recovery codes
+// @Description	Replace every recovery code of the authentication user, who has to enter the password and a code again. The new codes are only shown once
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.Reauthenticate	true	"Reauthenticate"
+// @Success			200		{object}	utils.ResponseDataRecoveryCodes
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/2fa/recovery-codes		[post]
+func (handler *userHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var (
+		reauthenticate utils.Reauthenticate
+		codes          []string
+		err            error
+	)
+
+	if err = ctx.ShouldBindJSON(&reauthenticate); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if codes, err = handler.twoFactorUseCase.RegenerateRecoveryCodes(ctx.Request.Context(), principal.UserID, reauthenticate.Password, reauthenticate.Code); err != nil {
+		handler.abortTwoFactor(ctx, err, http.StatusForbidden)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   utils.RecoveryCodes{RecoveryCodes: codes},
+	})
+}
+
+// abortTwoFactor responds to a failed two-factor request of a signed in
+// user, answering a wrong code with wrongCode.
+func (handler *userHandler) abortTwoFactor(ctx *gin.Context, err error, wrongCode int) {
+	var loginLockedError *domain.LoginLockedError
+
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.As(err, &loginLockedError):
+		status = http.StatusTooManyRequests
+		ctx.Header("Retry-After", strconv.Itoa(int(loginLockedError.RetryAfter.Seconds())+1))
+	case errors.Is(err, domain.ErrTwoFactorEnabled), errors.Is(err, domain.ErrTwoFactorNotEnabled), errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidCode):
+		status = wrongCode
+	case errors.Is(err, domain.ErrWrongPassword):
+		status = http.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		status = http.StatusUnauthorized
+		err = errors.New("account not found")
+	}
+
+	ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+		Status:  "fail",
+		Message: err.Error(),
+	})
+}
+
+// VerifyEmail godoc
+// @Summary			Verify an email
+// @Description	Mark the email a verification token was sent to as verified. The token stops working once it is used or the email is changed
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.VerifyEmail	true	"Verify Email"
+// @Success			200		{object}	utils.ResponseMessageVerification
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
+// @Router			/users/verify-email		[post]
+func (handler *userHandler) VerifyEmail(ctx *gin.Context) {
+	var (
+		verify utils.VerifyEmail
+		err    error
+	)
+
+	if err = ctx.ShouldBindJSON(&verify); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.emailVerificationUseCase.Verify(ctx.Request.Context(), verify.Token); err != nil {
+		if errors.Is(err, domain.ErrEmailVerificationInvalid) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "your email has been successfully verified",
+	})
+}
+
+// ResendVerification godoc
+// @Summary			Resend the verification email
+// @Description	Email a new verification token to the authentication user. Only a few are sent every hour
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Success			202		{object}	utils.ResponseMessageVerification
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/verify-email/resend		[post]
+func (handler *userHandler) ResendVerification(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err := handler.emailVerificationUseCase.Send(ctx.Request.Context(), principal.UserID); err != nil {
+		status := http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, domain.ErrEmailAlreadyVerified):
+			status = http.StatusConflict
+		case errors.Is(err, domain.ErrVerificationRateLimited):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusUnauthorized
+			err = errors.New("account not found")
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "a verification token has been sent to your email",
+	})
+}
+
+// requestReset emails a reset token outside the request, so neither how long
+// the response takes nor whether sending failed tells if the email belongs
+// to an account.
+func (handler *userHandler) requestReset(email string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+
+	defer cancel()
+
+	if err := handler.passwordUseCase.RequestReset(ctx, email); err != nil {
+		log.Printf("sending the password reset email: %s", err)
+	}
+}
+
+// sendVerification emails a verification token to a user who just registered
+// or changed email. A failure doesn't fail the request, since the user can
+// ask for another token.
+func (handler *userHandler) sendVerification(ctx *gin.Context, userID string) {
+	if err := handler.emailVerificationUseCase.Send(ctx.Request.Context(), userID); err != nil {
+		log.Printf("sending the verification email of user %s: %s", userID, err)
+	}
+}
+
+func (handler *userHandler) respondWithTokens(ctx *gin.Context, user domain.User, refreshToken domain.RefreshToken) {
+	token, err := handler.tokenManager.GenerateToken(user.ID, user.Email, user.Roles())
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.LoggedinUser{
+			Token:        token,
+			RefreshToken: refreshToken.Token,
+			ExpiresIn:    int64(handler.tokenManager.AccessTTL().Seconds()),
+		},
+	})
+}
+
+// GetProfile godoc
+// @Summary			Get the profile of a user
+// @Description	Get the public profile of a user by username with authentication user. The email and age of the user are never shown, and the bio and social medias of a private account are only shown to the account and its followers
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				username	path			string	true	"Username"
+// @Success			200				{object}	utils.ResponseDataProfile
+// @Failure			401				{object}	utils.ResponseMessage
+// @Failure			404				{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/{username}	[get]
+func (handler *userHandler) GetProfile(ctx *gin.Context) {
+	var (
+		user domain.User
+		err  error
+	)
+
+	username := ctx.Param("username")
+
+	if err = handler.userUseCase.GetProfile(ctx.Request.Context(), &user, username); err != nil {
+		status := http.StatusInternalServerError
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+			err = fmt.Errorf("user with username %s doesn't exist", username)
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	socialMedias := []*utils.SocialMedia{}
+
+	if user.SocialMedias != nil {
+		for _, socialMedia := range *user.SocialMedias {
+			socialMedias = append(socialMedias, &utils.SocialMedia{
+				ID:             socialMedia.ID,
+				Name:           socialMedia.Name,
+				SocialMediaUrl: socialMedia.SocialMediaUrl,
+			})
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.Profile{
+			ID:              user.ID,
+			Username:        user.Username,
+			DisplayName:     user.DisplayName,
+			Bio:             user.Bio,
+			ProfileImageUrl: handler.avatarURL(user),
+			Private:         user.Private,
+			ImageCount:      user.ImageCount,
+			FollowerCount:   user.FollowerCount,
+			FollowingCount:  user.FollowingCount,
+			SocialMedias:    socialMedias,
+			CreatedAt:       user.CreatedAt,
+		},
+	})
+}
+
+// Edit godoc
+// @Summary			Edit a user
+// @Description	Edit a user with authentication user. Empty fields are left unchanged. A multipart form can also carry a new avatar, which is stored once the other fields are. Changing the email makes it unverified and emails a verification token to the new one
+// @Tags				users
+// @Accept			json,mpfd
+// @Produce			json
+// @Param				json		body			utils.EditUser   true  "Edit User"
+// @Param				avatar	formData	file						false	"JPEG, PNG, GIF or WebP image"
+// @Success			200			{object}  utils.ResponseDataEditedUser
+// @Failure			400			{object}	utils.ResponseMessage
+// @Failure			401			{object}	utils.ResponseMessage
+// @Failure			404			{object}	utils.ResponseMessage
+// @Failure			409			{object}	utils.ResponseMessage
+// @Failure			413			{object}	utils.ResponseMessage
+// @Failure			415			{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users	[put]
+func (handler *userHandler) Edit(ctx *gin.Context) {
+	var (
+		edit utils.EditUser
+		user domain.User
+		err  error
+	)
+
+	principal, _ := middleware.GetPrincipal(ctx)
+	avatar := domain.User{ID: principal.UserID}
+	multipart := ctx.ContentType() == "multipart/form-data"
+
+	if multipart {
+		// Leave room for the other form fields and the multipart boundaries.
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, handler.maxUploadSize+1<<20)
+	}
+
+	if err = ctx.ShouldBind(&edit); err != nil {
+		status := http.StatusBadRequest
+
+		var maxBytesError *http.MaxBytesError
+
+		if errors.As(err, &maxBytesError) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	editedUser := domain.User{
+		ID:          principal.UserID,
+		Username:    edit.Username,
+		Email:       edit.Email,
+		DisplayName: edit.DisplayName,
+		Bio:         edit.Bio,
+	}
+
+	if user, err = handler.userUseCase.Edit(ctx.Request.Context(), editedUser); err != nil {
+		status := http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, domain.ErrUsernameTaken), errors.Is(err, domain.ErrEmailTaken):
+			status = http.StatusConflict
+		case errors.Is(err, domain.ErrDisplayNameTooLong), errors.Is(err, domain.ErrBioTooLong):
+			status = http.StatusBadRequest
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusNotFound
+			err = errors.New("account not found")
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if edit.Email != "" && user.EmailVerifiedAt == nil {
+		handler.sendVerification(ctx, user.ID)
+	}
+
+	if edit.Private != nil {
+		if err = handler.followUseCase.SetPrivate(ctx.Request.Context(), principal.UserID, *edit.Private); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		user.Private = *edit.Private
+	}
+
+	// The avatar is only stored once the rest of the edit is, so a refused
+	// edit doesn't leave a new avatar behind.
+	if multipart {
+		if !handler.setAvatar(ctx, &avatar) {
+			return
+		}
+	}
+
+	if avatar.ProfileImageUrl != "" {
+		user.ProfileImageUrl = avatar.ProfileImageUrl
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.EditedUser{
+			ID:              user.ID,
+			Email:           user.Email,
+			Username:        user.Username,
+			DisplayName:     user.DisplayName,
+			Bio:             user.Bio,
+			ProfileImageUrl: handler.avatarURL(user),
+			Private:         user.Private,
+			EmailVerified:   user.EmailVerifiedAt != nil,
+			Age:             user.Age,
+			UpdatedAt:       user.UpdatedAt,
+		},
+	})
+}
+
+// setAvatar stores the avatar file of the form, if there is one, as the
+// profile image of user. It answers the request and returns false when the
+// avatar can't be stored.
+func (handler *userHandler) setAvatar(ctx *gin.Context, user *domain.User) bool {
+	fileHeader, err := ctx.FormFile("avatar")
+
+	if errors.Is(err, http.ErrMissingFile) {
+		return true
+	}
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return false
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return false
+	}
+
+	defer file.Close()
+
+	if err = handler.userUseCase.SetAvatar(ctx.Request.Context(), user, file); err != nil {
+		status := http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, domain.ErrImageTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, domain.ErrUnsupportedImageType):
+			status = http.StatusUnsupportedMediaType
+		}
+
+		ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return false
+	}
+
+	return true
+}
+
+// avatarURL signs the link of an uploaded avatar and leaves one hosted
+// elsewhere as it is. Links to stored files other than the avatars of the
+// user are dropped.
+func (handler *userHandler) avatarURL(user domain.User) string {
+	if _, ok := user.AvatarKey(); ok {
+		return handler.urlSigner.Sign(user.ProfileImageUrl)
+	}
+
+	if _, ok := domain.FileKey(user.ProfileImageUrl); ok {
+		return ""
+	}
+
+	return user.ProfileImageUrl
+}
+
+// Delete godoc
+// @Summary			Delete a user
+// @Description	Delete a user with authentication user
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Success			200			{object}	utils.ResponseMessageDeletedUser
+// @Failure			400			{object}	utils.ResponseMessage
+// @Failure			401			{object}	utils.ResponseMessage
+// @Failure			404			{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users	[delete]
+func (handler *userHandler) Delete(ctx *gin.Context) {
+	principal, _ := middleware.GetPrincipal(ctx)
+
+	if err := handler.tokenRevocationUseCase.RevokeAll(ctx.Request.Context(), principal.UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
 
-	if err := handler.userUseCase.Delete(ctx, userID); err != nil {
+	if err := handler.userUseCase.Delete(ctx.Request.Context(), principal.UserID); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: "account not found",
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often attempts whose lockout has passed are dropped.
+const sweepInterval = time.Minute
+
+// loginAttemptRepository counts failed logins in process memory. Every
+// replica keeps its own counts, so the limits apply per instance, and the
+// counts are lost on restart.
+type loginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+	sweepAt  time.Time
+}
+
+func NewLoginAttemptRepository() *loginAttemptRepository {
+	return &loginAttemptRepository{attempts: map[string]domain.LoginAttempt{}}
+}
+
+func (loginAttemptRepository *loginAttemptRepository) Reserve(ctx context.Context, key string, policy domain.LoginAttemptPolicy) (retryAfter time.Duration, err error) {
+	loginAttemptRepository.mu.Lock()
+
+	defer loginAttemptRepository.mu.Unlock()
+
+	now := time.Now()
+
+	if !now.Before(loginAttemptRepository.sweepAt) {
+		for k, loginAttempt := range loginAttemptRepository.attempts {
+			if loginAttempt.LastFailedAt.Add(policy.Lockout).Before(now) {
+				delete(loginAttemptRepository.attempts, k)
+			}
+		}
+
+		loginAttemptRepository.sweepAt = now.Add(sweepInterval)
+	}
+
+	loginAttempt := loginAttemptRepository.attempts[key]
+	loginAttempt.Key = key
+
+	if retryAfter = policy.Reserve(&loginAttempt, now); retryAfter > 0 {
+		return retryAfter, nil
+	}
+
+	loginAttemptRepository.attempts[key] = loginAttempt
+
+	return 0, nil
+}
+
+func (loginAttemptRepository *loginAttemptRepository) Release(ctx context.Context, key string) (err error) {
+	loginAttemptRepository.mu.Lock()
+
+	defer loginAttemptRepository.mu.Unlock()
+
+	if loginAttempt, ok := loginAttemptRepository.attempts[key]; ok && loginAttempt.Failures > 0 {
+		loginAttempt.Failures--
+		loginAttemptRepository.attempts[key] = loginAttempt
+	}
+
+	return
+}
+
+func (loginAttemptRepository *loginAttemptRepository) Reset(ctx context.Context, key string) (err error) {
+	loginAttemptRepository.mu.Lock()
+
+	defer loginAttemptRepository.mu.Unlock()
+
+	delete(loginAttemptRepository.attempts, key)
+
+	return
+}
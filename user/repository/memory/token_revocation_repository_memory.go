@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"sync"
+	"time"
+)
+
+// tokenRevocationRepository keeps revocations in process memory. It is meant
+// for tests and single-instance development setups; revocations are lost on
+// restart and are not shared between replicas.
+type tokenRevocationRepository struct {
+	mu      sync.RWMutex
+	tokens  map[string]time.Time
+	cutoffs map[string]time.Time
+}
+
+func NewTokenRevocationRepository() *tokenRevocationRepository {
+	return &tokenRevocationRepository{
+		tokens:  map[string]time.Time{},
+		cutoffs: map[string]time.Time{},
+	}
+}
+
+func (tokenRevocationRepository *tokenRevocationRepository) Revoke(ctx context.Context, revokedToken domain.RevokedToken) (err error) {
+	tokenRevocationRepository.mu.Lock()
+
+	defer tokenRevocationRepository.mu.Unlock()
+
+	now := time.Now()
+
+	for jti, expiresAt := range tokenRevocationRepository.tokens {
+		if expiresAt.Before(now) {
+			delete(tokenRevocationRepository.tokens, jti)
+		}
+	}
+
+	expiresAt := now
+
+	if revokedToken.ExpiresAt != nil {
+		expiresAt = *revokedToken.ExpiresAt
+	}
+
+	tokenRevocationRepository.tokens[revokedToken.JTI] = expiresAt
+
+	return
+}
+
+func (tokenRevocationRepository *tokenRevocationRepository) RevokeAll(ctx context.Context, userID string, validAfter time.Time) (err error) {
+	tokenRevocationRepository.mu.Lock()
+
+	defer tokenRevocationRepository.mu.Unlock()
+
+	tokenRevocationRepository.cutoffs[userID] = validAfter.Truncate(domain.TokenTimePrecision)
+
+	return
+}
+
+func (tokenRevocationRepository *tokenRevocationRepository) IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (revoked bool, err error) {
+	tokenRevocationRepository.mu.RLock()
+
+	defer tokenRevocationRepository.mu.RUnlock()
+
+	if _, ok := tokenRevocationRepository.tokens[jti]; ok {
+		return true, nil
+	}
+
+	if validAfter, ok := tokenRevocationRepository.cutoffs[userID]; ok && validAfter.After(issuedAt) {
+		return true, nil
+	}
+
+	return false, nil
+}
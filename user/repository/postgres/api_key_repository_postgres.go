@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *apiKeyRepository {
+	return &apiKeyRepository{db}
+}
+
+func (apiKeyRepository *apiKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	apiKey.ID = fmt.Sprintf("apikey-%s", ID)
+
+	if err = apiKeyRepository.db.WithContext(ctx).Create(&apiKey).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) GetByUser(ctx context.Context, apiKeys *[]domain.APIKey, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&apiKeys).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) GetByHash(ctx context.Context, apiKey *domain.APIKey, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Where("key_hash = ?", hash).Take(&apiKey).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Revoke revokes the API key with the id when it belongs to the user. Keys
+// that are already revoked keep the time they were revoked at, and
+// gorm.ErrRecordNotFound is returned when the user has no such key.
+func (apiKeyRepository *apiKeyRepository) Revoke(ctx context.Context, userID string, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := apiKeyRepository.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ? AND user_id = ?", id, userID).Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", time.Now()))
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) Use(ctx context.Context, id string, usedAt time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) *emailVerificationRepository {
+	return &emailVerificationRepository{db}
+}
+
+func (emailVerificationRepository *emailVerificationRepository) Create(ctx context.Context, emailVerification *domain.EmailVerification) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	emailVerification.ID = fmt.Sprintf("emailverification-%s", ID)
+
+	if err = emailVerificationRepository.db.WithContext(ctx).Create(&emailVerification).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (emailVerificationRepository *emailVerificationRepository) GetByHash(ctx context.Context, emailVerification *domain.EmailVerification, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = emailVerificationRepository.db.WithContext(ctx).Where("token_hash = ?", hash).Take(&emailVerification).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// CountSince counts the verifications issued to the user since since.
+func (emailVerificationRepository *emailVerificationRepository) CountSince(ctx context.Context, userID string, since time.Time) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = emailVerificationRepository.db.WithContext(ctx).Model(&domain.EmailVerification{}).Where("user_id = ? AND created_at >= ?", userID, since).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Use marks emailVerification used and its user verified in the same
+// transaction, along with every other verification of the user still
+// outstanding. When the verification has been used in the meantime or the
+// user no longer has its email, domain.ErrEmailVerificationInvalid is
+// returned and nothing changes.
+func (emailVerificationRepository *emailVerificationRepository) Use(ctx context.Context, emailVerification domain.EmailVerification) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return emailVerificationRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&domain.EmailVerification{}).Where("id = ? AND used_at IS NULL AND expires_at > ?", emailVerification.ID, now).Update("used_at", now)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrEmailVerificationInvalid
+		}
+
+		result = tx.Model(&domain.User{}).Where("id = ? AND email = ?", emailVerification.UserID, emailVerification.Email).Update("email_verified_at", now)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrEmailVerificationInvalid
+		}
+
+		return tx.Model(&domain.EmailVerification{}).Where("user_id = ? AND used_at IS NULL", emailVerification.UserID).Update("used_at", now).Error
+	})
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *loginAttemptRepository {
+	return &loginAttemptRepository{db}
+}
+
+// Reserve counts an attempt under key with the row locked, so parallel
+// attempts see each other. Counts that have gone stale are cleared on the
+// way.
+func (loginAttemptRepository *loginAttemptRepository) Reserve(ctx context.Context, key string, policy domain.LoginAttemptPolicy) (retryAfter time.Duration, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	db := loginAttemptRepository.db.WithContext(ctx)
+
+	if err = db.Where("last_failed_at < ?", time.Now().Add(-policy.Lockout)).Delete(&domain.LoginAttempt{}).Error; err != nil {
+		return 0, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var now time.Time
+
+		placeholder := time.Now()
+		loginAttempt := domain.LoginAttempt{Key: key, LastFailedAt: &placeholder}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&loginAttempt).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).Take(&loginAttempt).Error; err != nil {
+			return err
+		}
+
+		// The clock is read once the row is locked, so waiting for the lock
+		// doesn't count towards the backoff.
+		if err := tx.Raw("SELECT clock_timestamp()").Row().Scan(&now); err != nil {
+			return err
+		}
+
+		if retryAfter = policy.Reserve(&loginAttempt, now); retryAfter > 0 {
+			return nil
+		}
+
+		return tx.Model(&domain.LoginAttempt{}).Where("key = ?", key).Updates(map[string]interface{}{
+			"failures":       loginAttempt.Failures,
+			"last_failed_at": loginAttempt.LastFailedAt,
+		}).Error
+	})
+
+	return retryAfter, err
+}
+
+func (loginAttemptRepository *loginAttemptRepository) Release(ctx context.Context, key string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = loginAttemptRepository.db.WithContext(ctx).Model(&domain.LoginAttempt{}).Where("key = ? AND failures > 0", key).Update("failures", gorm.Expr("failures - 1")).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (loginAttemptRepository *loginAttemptRepository) Reset(ctx context.Context, key string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = loginAttemptRepository.db.WithContext(ctx).Where("key = ?", key).Delete(&domain.LoginAttempt{}).Error; err != nil {
+		return err
+	}
+
+	return
+}
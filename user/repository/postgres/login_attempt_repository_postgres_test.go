@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	repository "mygram-byferdiansyah/user/repository/postgres"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttempt(t *testing.T) {
+	db := testDB(t)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	ctx := context.Background()
+
+	suffix, _ := gonanoid.New(10)
+	key := "account:" + suffix
+
+	t.Cleanup(func() {
+		db.Delete(&domain.LoginAttempt{}, "key = ?", key)
+	})
+
+	policy := domain.LoginAttemptPolicy{MaxAttempts: 5, Lockout: time.Minute}
+
+	t.Run("reserve an attempt", func(t *testing.T) {
+		retryAfter, err := loginAttemptRepository.Reserve(ctx, key, policy)
+
+		assert.NoError(t, err)
+		assert.Zero(t, retryAfter)
+
+		retryAfter, err = loginAttemptRepository.Reserve(ctx, key, policy)
+
+		assert.NoError(t, err)
+		assert.Greater(t, retryAfter, time.Duration(0))
+	})
+
+	t.Run("reserve again once the attempt is released", func(t *testing.T) {
+		assert.NoError(t, loginAttemptRepository.Release(ctx, key))
+
+		retryAfter, err := loginAttemptRepository.Reserve(ctx, key, policy)
+
+		assert.NoError(t, err)
+		assert.Zero(t, retryAfter)
+	})
+
+	t.Run("reserve attempts in parallel", func(t *testing.T) {
+		assert.NoError(t, loginAttemptRepository.Reset(ctx, key))
+
+		var (
+			wg       sync.WaitGroup
+			reserved int32
+		)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if retryAfter, err := loginAttemptRepository.Reserve(ctx, key, policy); err == nil && retryAfter == 0 {
+					atomic.AddInt32(&reserved, 1)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(1), reserved)
+	})
+
+	t.Run("reset attempts", func(t *testing.T) {
+		assert.NoError(t, loginAttemptRepository.Reset(ctx, key))
+
+		retryAfter, err := loginAttemptRepository.Reserve(ctx, key, policy)
+
+		assert.NoError(t, err)
+		assert.Zero(t, retryAfter)
+	})
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *passwordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+func (passwordResetRepository *passwordResetRepository) Create(ctx context.Context, passwordReset *domain.PasswordReset) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	passwordReset.ID = fmt.Sprintf("passwordreset-%s", ID)
+
+	if err = passwordResetRepository.db.WithContext(ctx).Create(&passwordReset).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (passwordResetRepository *passwordResetRepository) GetByHash(ctx context.Context, passwordReset *domain.PasswordReset, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = passwordResetRepository.db.WithContext(ctx).Where("token_hash = ?", hash).Take(&passwordReset).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Use marks passwordReset used and sets the password of its user to
+// password, which must already be hashed, in the same transaction. Every
+// other reset of the user still outstanding is used up with it. When the
+// reset has been used in the meantime, domain.ErrPasswordResetInvalid is
+// returned and the password is left unchanged.
+func (passwordResetRepository *passwordResetRepository) Use(ctx context.Context, passwordReset domain.PasswordReset, password string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return passwordResetRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&domain.PasswordReset{}).Where("id = ? AND used_at IS NULL AND expires_at > ?", passwordReset.ID, now).Update("used_at", now)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrPasswordResetInvalid
+		}
+
+		if err := tx.Model(&domain.User{}).Where("id = ?", passwordReset.UserID).Update("password", password).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.PasswordReset{}).Where("user_id = ? AND used_at IS NULL", passwordReset.UserID).Update("used_at", now).Error
+	})
+}
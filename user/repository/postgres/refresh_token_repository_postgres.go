@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *refreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+func (refreshTokenRepository *refreshTokenRepository) Create(ctx context.Context, refreshToken *domain.RefreshToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return createRefreshToken(refreshTokenRepository.db.WithContext(ctx), refreshToken)
+}
+
+func (refreshTokenRepository *refreshTokenRepository) GetByHash(ctx context.Context, refreshToken *domain.RefreshToken, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Where("token_hash = ?", hash).Take(&refreshToken).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Rotate revokes the token with the given id and stores next in the same
+// transaction. When the token has already been revoked, which happens when two
+// requests race with the same refresh token, domain.ErrRefreshTokenReused is
+// returned and nothing is stored.
+func (refreshTokenRepository *refreshTokenRepository) Rotate(ctx context.Context, id string, next *domain.RefreshToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return refreshTokenRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now())
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrRefreshTokenReused
+		}
+
+		return createRefreshToken(tx, next)
+	})
+}
+
+func (refreshTokenRepository *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("family_id = ? AND revoked_at IS NULL", familyID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (refreshTokenRepository *refreshTokenRepository) RevokeAll(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func createRefreshToken(db *gorm.DB, refreshToken *domain.RefreshToken) (err error) {
+	ID, _ := gonanoid.New(16)
+
+	refreshToken.ID = fmt.Sprintf("refreshtoken-%s", ID)
+
+	if err = db.Create(&refreshToken).Error; err != nil {
+		return err
+	}
+
+	return
+}
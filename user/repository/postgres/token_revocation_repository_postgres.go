@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRevocationRepository(db *gorm.DB) *tokenRevocationRepository {
+	return &tokenRevocationRepository{db}
+}
+
+// Revoke blocks the token until it expires. The tokens that have expired
+// since are dropped on the way, as they can't be used anymore anyway.
+func (tokenRevocationRepository *tokenRevocationRepository) Revoke(ctx context.Context, revokedToken domain.RevokedToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = tokenRevocationRepository.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&domain.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	if err = tokenRevocationRepository.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&revokedToken).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (tokenRevocationRepository *tokenRevocationRepository) RevokeAll(ctx context.Context, userID string, validAfter time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	validAfter = validAfter.Truncate(domain.TokenTimePrecision)
+	cutoff := domain.TokenCutoff{UserID: userID, ValidAfter: &validAfter}
+
+	if err = tokenRevocationRepository.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"valid_after"}),
+	}).Create(&cutoff).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (tokenRevocationRepository *tokenRevocationRepository) IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (revoked bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	var count int64
+
+	if err = tokenRevocationRepository.db.WithContext(ctx).Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	if count > 0 {
+		return true, nil
+	}
+
+	if err = tokenRevocationRepository.db.WithContext(ctx).Model(&domain.TokenCutoff{}).Where("user_id = ? AND valid_after > ?", userID, issuedAt).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) *twoFactorRepository {
+	return &twoFactorRepository{db}
+}
+
+// Enroll stores secret as the not yet confirmed TOTP secret of the user along
+// with the hashes of new recovery codes. It fails with
+// domain.ErrTwoFactorEnabled once two-factor authentication is enabled, so an
+// enrollment never replaces a confirmed secret.
+func (twoFactorRepository *twoFactorRepository) Enroll(ctx context.Context, userID string, secret string, codeHashes []string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return twoFactorRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.User{}).Where("id = ? AND two_factor_enabled_at IS NULL", userID).Updates(map[string]interface{}{
+			"totp_secret":    secret,
+			"totp_last_step": 0,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			if err := tx.Where("id = ?", userID).Take(&domain.User{}).Error; err != nil {
+				return err
+			}
+
+			return domain.ErrTwoFactorEnabled
+		}
+
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Enable turns two-factor authentication on for the user, recording step as
+// the last TOTP step used.
+func (twoFactorRepository *twoFactorRepository) Enable(ctx context.Context, userID string, step int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := twoFactorRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ? AND two_factor_enabled_at IS NULL AND totp_secret <> ''", userID).Updates(map[string]interface{}{
+		"two_factor_enabled_at": time.Now(),
+		"totp_last_step":        step,
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+
+	return
+}
+
+// Disable turns two-factor authentication off for the user and forgets the
+// secret, the recovery codes and the pending login challenges.
+func (twoFactorRepository *twoFactorRepository) Disable(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return twoFactorRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":           "",
+			"totp_last_step":        0,
+			"two_factor_enabled_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.LoginChallenge{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+	})
+}
+
+func (twoFactorRepository *twoFactorRepository) SetRecoveryCodes(ctx context.Context, userID string, codeHashes []string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return twoFactorRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseStep records step as the last TOTP step the user signed in with. It
+// fails with domain.ErrInvalidCode when that step or a later one has already
+// been used, so a code can't be replayed.
+func (twoFactorRepository *twoFactorRepository) UseStep(ctx context.Context, userID string, step int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := twoFactorRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ? AND totp_last_step < ?", userID, step).Update("totp_last_step", step)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidCode
+	}
+
+	return
+}
+
+// UseRecoveryCode marks the unused recovery code of the user with the hash
+// used, or fails with domain.ErrInvalidCode when there is none.
+func (twoFactorRepository *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := twoFactorRepository.db.WithContext(ctx).Model(&domain.RecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidCode
+	}
+
+	return
+}
+
+func (twoFactorRepository *twoFactorRepository) CreateChallenge(ctx context.Context, loginChallenge *domain.LoginChallenge) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	loginChallenge.ID = fmt.Sprintf("challenge-%s", ID)
+
+	if err = twoFactorRepository.db.WithContext(ctx).Create(&loginChallenge).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (twoFactorRepository *twoFactorRepository) GetChallenge(ctx context.Context, loginChallenge *domain.LoginChallenge, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = twoFactorRepository.db.WithContext(ctx).Where("token_hash = ?", hash).Take(&loginChallenge).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// FailChallenge counts an attempt against the challenge with the id, in the
+// same statement that checks the challenge has attempts left and hasn't
+// expired, so parallel attempts can't go over maxAttempts. It fails with
+// domain.ErrChallengeInvalid when the attempt isn't allowed.
+func (twoFactorRepository *twoFactorRepository) FailChallenge(ctx context.Context, id string, maxAttempts int) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := twoFactorRepository.db.WithContext(ctx).Model(&domain.LoginChallenge{}).Where("id = ? AND attempts < ? AND expires_at > now()", id, maxAttempts).Update("attempts", gorm.Expr("attempts + 1"))
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrChallengeInvalid
+	}
+
+	return
+}
+
+// DeleteChallenge deletes the challenge with the id, failing with
+// domain.ErrChallengeInvalid when another request already did, so a
+// challenge is only ever exchanged once.
+func (twoFactorRepository *twoFactorRepository) DeleteChallenge(ctx context.Context, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := twoFactorRepository.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.LoginChallenge{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrChallengeInvalid
+	}
+
+	return
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string, codeHashes []string) (err error) {
+	if err = tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	recoveryCodes := make([]domain.RecoveryCode, 0, len(codeHashes))
+
+	for _, codeHash := range codeHashes {
+		ID, _ := gonanoid.New(16)
+
+		recoveryCodes = append(recoveryCodes, domain.RecoveryCode{
+			ID:       fmt.Sprintf("recoverycode-%s", ID),
+			UserID:   userID,
+			CodeHash: codeHash,
+		})
+	}
+
+	if len(recoveryCodes) == 0 {
+		return
+	}
+
+	if err = tx.Create(&recoveryCodes).Error; err != nil {
+		return err
+	}
+
+	return
+}
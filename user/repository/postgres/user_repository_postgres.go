@@ -6,12 +6,23 @@ import (
 	"fmt"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/helpers"
+	"mygram-byferdiansyah/privacy"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// uniqueViolation is the SQLSTATE Postgres fails an insert or update with
+// when it would duplicate a unique index.
+const uniqueViolation = "23505"
+
+// dummyPassword is compared against when no user has the email a login is
+// for. It is hashed like stored passwords so the comparison costs the same.
+var dummyPassword = helpers.Hash("mygram-dummy-password")
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -30,7 +41,7 @@ func (userRepository *userRepository) Register(ctx context.Context, user *domain
 	user.ID = fmt.Sprintf("user-%s", ID)
 
 	if err = userRepository.db.WithContext(ctx).Create(&user).Error; err != nil {
-		return err
+		return taken(err)
 	}
 
 	return
@@ -44,35 +55,203 @@ func (userRepository *userRepository) Login(ctx context.Context, user *domain.Us
 	password := user.Password
 
 	if err = userRepository.db.WithContext(ctx).Where("email = ?", user.Email).Take(&user).Error; err != nil {
-		return errors.New("the email you entered are not found")
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Spend as long as checking a real password would, so the response
+		// time doesn't give away that the email is unknown.
+		helpers.Compare([]byte(dummyPassword), []byte(password))
+
+		return domain.ErrInvalidCredentials
 	}
 
 	if isValid := helpers.Compare([]byte(user.Password), []byte(password)); !isValid {
-		return errors.New("the credential you entered are wrong")
+		return domain.ErrInvalidCredentials
+	}
+
+	if user.SuspendedAt != nil {
+		return domain.ErrUserSuspended
+	}
+
+	return
+}
+
+func (userRepository *userRepository) Get(ctx context.Context, users *[]domain.User) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).Omit("password").Order("created_at").Find(&users).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userRepository *userRepository) GetByID(ctx context.Context, user *domain.User, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).First(&user, &id).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userRepository *userRepository) GetByUsername(ctx context.Context, user *domain.User, username string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).Where("username = ?", username).Take(&user).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userRepository *userRepository) GetByEmail(ctx context.Context, user *domain.User, email string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).Where("email = ?", email).Take(&user).Error; err != nil {
+		return err
 	}
 
 	return
 }
 
-func (userRepository *userRepository) Update(ctx context.Context, user domain.User) (u domain.User, err error) {
+// GetProfile gets the user with the username along with the social medias
+// of the user and the number of images the user has posted. The bio and the
+// social medias of a private account are left out for users who don't
+// follow it.
+func (userRepository *userRepository) GetProfile(ctx context.Context, user *domain.User, username string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	u = domain.User{}
+	db := userRepository.db.WithContext(ctx)
+
+	if err = db.Omit("password").Where("username = ?", username).Preload("SocialMedias", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).Take(&user).Error; err != nil {
+		return err
+	}
+
+	var count int64
+
+	if err = db.Model(&domain.Image{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+		return err
+	}
+
+	user.ImageCount = int(count)
+
+	if err = db.Model(&domain.User{}).Where("id = ?", user.ID).Scopes(privacy.Visible(ctx, "id")).Count(&count).Error; err != nil {
+		return err
+	}
 
-	if err = userRepository.db.WithContext(ctx).First(&u).Error; err != nil {
-		return u, err
+	if count == 0 {
+		user.Bio = ""
+		user.SocialMedias = nil
 	}
 
-	if err = userRepository.db.WithContext(ctx).Model(&u).Updates(user).Error; err != nil {
-		return u, err
+	return
+}
+
+// Edit changes the username, email, display name and bio of the user with
+// the ID of user to the ones set in user and returns the user as stored.
+// Changing the email marks the user unverified until the new email is
+// verified.
+func (userRepository *userRepository) Edit(ctx context.Context, user domain.User) (u domain.User, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	err = userRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", user.ID).Take(&u).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+
+		if user.Username != "" && user.Username != u.Username {
+			updates["username"] = user.Username
+		}
+
+		if user.Email != "" && user.Email != u.Email {
+			updates["email"] = user.Email
+			updates["email_verified_at"] = nil
+		}
+
+		if user.DisplayName != "" {
+			updates["display_name"] = user.DisplayName
+		}
+
+		if user.Bio != "" {
+			updates["bio"] = user.Bio
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&domain.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return taken(err)
+		}
+
+		return tx.Where("id = ?", user.ID).Take(&u).Error
+	})
+
+	if err != nil {
+		return domain.User{}, err
 	}
 
 	return u, nil
 }
 
-func (userRepository *userRepository) Delete(ctx context.Context, id string) (err error) {
+// SetPassword replaces the password of the user with password, which must
+// already be hashed.
+func (userRepository *userRepository) SetPassword(ctx context.Context, id string, password string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := userRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("password", password)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+func (userRepository *userRepository) SetProfileImage(ctx context.Context, id string, url string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := userRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("profile_image_url", url)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+func (userRepository *userRepository) SetRole(ctx context.Context, id string, role string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
@@ -81,13 +260,143 @@ func (userRepository *userRepository) Delete(ctx context.Context, id string) (er
 		return err
 	}
 
-	if err = userRepository.db.WithContext(ctx).Where("user_id = ?", id).Delete(&domain.SocialMedia{}).Error; err != nil {
+	if err = userRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("role", role).Error; err != nil {
 		return err
 	}
 
-	if err = userRepository.db.WithContext(ctx).Delete(&domain.User{}, &id).Error; err != nil {
+	return
+}
+
+// SetSuspended suspends the user when suspendedAt is set and restores the
+// user when it is nil.
+func (userRepository *userRepository) SetSuspended(ctx context.Context, id string, suspendedAt *time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).First(&domain.User{}, &id).Error; err != nil {
+		return err
+	}
+
+	if err = userRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("suspended_at", suspendedAt).Error; err != nil {
 		return err
 	}
 
 	return
 }
+
+// Delete deletes the user with the id, along with everything of theirs. The
+// like counts of the images and comments they liked, and the follow counts
+// of the users they followed or were followed by, are taken down in the same
+// transaction, since their likes and follows go away with them. Their
+// comments that others replied to are kept as tombstones without an author.
+func (userRepository *userRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	err = userRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&domain.User{}, &id).Error; err != nil {
+			return err
+		}
+
+		for table, column := range map[string]string{"images": "image_id", "comments": "comment_id"} {
+			liked := tx.Model(&domain.Like{}).Select(column).Where("user_id = ? AND "+column+" IS NOT NULL", id)
+
+			if err := tx.Table(table).Where("id IN (?)", liked).UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		followees := tx.Model(&domain.Follow{}).Select("followee_id").Where("follower_id = ? AND status = ?", id, domain.FollowStatusAccepted)
+
+		if err := tx.Model(&domain.User{}).Where("id IN (?)", followees).UpdateColumn("follower_count", gorm.Expr("follower_count - 1")).Error; err != nil {
+			return err
+		}
+
+		followers := tx.Model(&domain.Follow{}).Select("follower_id").Where("followee_id = ? AND status = ?", id, domain.FollowStatusAccepted)
+
+		if err := tx.Model(&domain.User{}).Where("id IN (?)", followers).UpdateColumn("following_count", gorm.Expr("following_count - 1")).Error; err != nil {
+			return err
+		}
+
+		if err := deleteComments(tx, id); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", id).Delete(&domain.SocialMedia{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.User{}, &id).Error
+	})
+
+	return err
+}
+
+// deleteComments deletes the comments of the user with the id. The ones on
+// the images of the user go with every reply on them, as they would with the
+// images. The others are deleted the deepest first, so a comment whose
+// replies all go with it is deleted rather than kept, taking down the reply
+// counts of the comments they replied to. Tombstones left without replies go
+// as well, as they would when the last reply is deleted on its own.
+func deleteComments(tx *gorm.DB, id string) error {
+	var maxDepth *int
+
+	images := tx.Model(&domain.Image{}).Select("id").Where("user_id = ?", id)
+
+	if err := tx.Where("image_id IN (?)", images).Delete(&domain.Comment{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&domain.Comment{}).Select("MAX(depth)").Where("user_id = ?", id).Scan(&maxDepth).Error; err != nil {
+		return err
+	}
+
+	if maxDepth == nil {
+		return nil
+	}
+
+	for depth := *maxDepth; depth >= 0; depth-- {
+		gone := tx.Model(&domain.Comment{}).Select("id").Where("depth = ? AND reply_count = 0 AND (user_id = ? OR deleted_at IS NOT NULL)", depth, id)
+		parents := tx.Model(&domain.Comment{}).Select("parent_id").Where("id IN (?)", gone)
+		replies := tx.Table("comments AS replies").Select("COUNT(*)").Where("replies.parent_id = comments.id AND replies.id IN (?)", gone)
+
+		if err := tx.Model(&domain.Comment{}).Where("id IN (?)", parents).UpdateColumn("reply_count", gorm.Expr("reply_count - (?)", replies)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("id IN (?)", gone).Delete(&domain.Comment{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.Comment{}).Where("depth = ? AND user_id = ?", depth, id).UpdateColumns(map[string]interface{}{
+			"message":    domain.DeletedCommentMessage,
+			"deleted_at": gorm.Expr("COALESCE(deleted_at, ?)", time.Now()),
+			"user_id":    nil,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// taken turns the unique violation of the username or the email of a user
+// into the error saying which one has been used.
+func taken(err error) error {
+	var pgError *pgconn.PgError
+
+	if !errors.As(err, &pgError) || pgError.Code != uniqueViolation {
+		return err
+	}
+
+	switch pgError.ConstraintName {
+	case "idx_users_username":
+		return domain.ErrUsernameTaken
+	case "idx_users_email":
+		return domain.ErrEmailTaken
+	}
+
+	return err
+}
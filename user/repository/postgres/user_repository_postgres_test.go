@@ -0,0 +1,333 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/config/database"
+	"mygram-byferdiansyah/domain"
+	"os"
+	"testing"
+	"time"
+
+	repository "mygram-byferdiansyah/user/repository/postgres"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB connects to the database named by TEST_DATABASE_DSN and migrates it.
+// The tests are skipped when it isn't set.
+func testDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// register stores a user with a random username and email and removes it
+// when the test ends.
+func register(t *testing.T, db *gorm.DB) domain.User {
+	suffix, _ := gonanoid.Generate("abcdefghijklmnopqrstuvwxyz0123456789", 10)
+
+	user := domain.User{
+		Username: fmt.Sprintf("test%s", suffix),
+		Email:    fmt.Sprintf("test%s@example.com", suffix),
+		Password: "secret",
+		Age:      8,
+	}
+
+	if err := repository.NewUserRepository(db).Register(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Delete(&domain.User{}, "id = ?", user.ID)
+	})
+
+	return user
+}
+
+func TestMigrate(t *testing.T) {
+	db := testDB(t)
+	user := register(t, db)
+
+	t.Run("migrate again without verifying new users", func(t *testing.T) {
+		assert.NoError(t, database.Migrate(db))
+
+		migrated := domain.User{}
+
+		assert.NoError(t, db.Take(&migrated, "id = ?", user.ID).Error)
+		assert.Nil(t, migrated.EmailVerifiedAt)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	db := testDB(t)
+	userRepository := repository.NewUserRepository(db)
+	existing := register(t, db)
+
+	t.Run("register a user with a used username", func(t *testing.T) {
+		user := domain.User{
+			Username: existing.Username,
+			Email:    fmt.Sprintf("other%s", existing.Email),
+			Password: "secret",
+			Age:      8,
+		}
+
+		err := userRepository.Register(context.Background(), &user)
+
+		assert.ErrorIs(t, err, domain.ErrUsernameTaken)
+	})
+
+	t.Run("register a user with a used email", func(t *testing.T) {
+		user := domain.User{
+			Username: fmt.Sprintf("other%s", existing.Username),
+			Email:    existing.Email,
+			Password: "secret",
+			Age:      8,
+		}
+
+		err := userRepository.Register(context.Background(), &user)
+
+		assert.ErrorIs(t, err, domain.ErrEmailTaken)
+	})
+}
+
+func TestEdit(t *testing.T) {
+	db := testDB(t)
+	userRepository := repository.NewUserRepository(db)
+
+	t.Run("edit only the user with the ID", func(t *testing.T) {
+		user := register(t, db)
+		other := register(t, db)
+
+		edited, err := userRepository.Edit(context.Background(), domain.User{
+			ID:          user.ID,
+			Username:    fmt.Sprintf("new%s", user.Username),
+			DisplayName: "John Doe",
+			Bio:         "Taking pictures of cats",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, edited.ID)
+		assert.Equal(t, fmt.Sprintf("new%s", user.Username), edited.Username)
+		assert.Equal(t, user.Email, edited.Email)
+		assert.Equal(t, "John Doe", edited.DisplayName)
+		assert.Equal(t, "Taking pictures of cats", edited.Bio)
+
+		var stored domain.User
+
+		assert.NoError(t, db.Where("id = ?", other.ID).Take(&stored).Error)
+		assert.Equal(t, other.Username, stored.Username)
+		assert.Empty(t, stored.DisplayName)
+		assert.Empty(t, stored.Bio)
+	})
+
+	t.Run("edit the email of a verified user", func(t *testing.T) {
+		user := register(t, db)
+
+		assert.NoError(t, db.Model(&domain.User{}).Where("id = ?", user.ID).Update("email_verified_at", time.Now()).Error)
+
+		edited, err := userRepository.Edit(context.Background(), domain.User{
+			ID:    user.ID,
+			Email: fmt.Sprintf("new%s", user.Email),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("new%s", user.Email), edited.Email)
+		assert.Nil(t, edited.EmailVerifiedAt)
+	})
+
+	t.Run("edit a user with the same email", func(t *testing.T) {
+		user := register(t, db)
+		verifiedAt := time.Now()
+
+		assert.NoError(t, db.Model(&domain.User{}).Where("id = ?", user.ID).Update("email_verified_at", verifiedAt).Error)
+
+		edited, err := userRepository.Edit(context.Background(), domain.User{
+			ID:    user.ID,
+			Email: user.Email,
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, edited.EmailVerifiedAt)
+	})
+
+	t.Run("edit a user with a used username", func(t *testing.T) {
+		user := register(t, db)
+		other := register(t, db)
+
+		edited, err := userRepository.Edit(context.Background(), domain.User{
+			ID:       user.ID,
+			Username: other.Username,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrUsernameTaken)
+		assert.Empty(t, edited.ID)
+	})
+
+	t.Run("edit a user with a used email", func(t *testing.T) {
+		user := register(t, db)
+		other := register(t, db)
+
+		_, err := userRepository.Edit(context.Background(), domain.User{
+			ID:    user.ID,
+			Email: other.Email,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrEmailTaken)
+	})
+
+	t.Run("edit a user that doesn't exist", func(t *testing.T) {
+		_, err := userRepository.Edit(context.Background(), domain.User{
+			ID:       "user-unknown",
+			Username: "unknown",
+		})
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	db := testDB(t)
+	userRepository := repository.NewUserRepository(db)
+	existing := register(t, db)
+
+	t.Run("login correctly", func(t *testing.T) {
+		user := domain.User{Email: existing.Email, Password: "secret"}
+
+		err := userRepository.Login(context.Background(), &user)
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing.ID, user.ID)
+	})
+
+	for name, user := range map[string]domain.User{
+		"login with a wrong password": {Email: existing.Email, Password: "wrong"},
+		"login with an unknown email": {Email: fmt.Sprintf("unknown%s", existing.Email), Password: "secret"},
+	} {
+		user := user
+
+		t.Run(name, func(t *testing.T) {
+			err := userRepository.Login(context.Background(), &user)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	db := testDB(t)
+	userRepository := repository.NewUserRepository(db)
+	owner := register(t, db)
+	liker := register(t, db)
+
+	suffix, _ := gonanoid.New(10)
+	image := domain.Image{ID: "image-" + suffix, Title: "title", ImageUrl: "https://www.example.com/image.jpg", UserID: owner.ID, LikeCount: 1}
+
+	assert.NoError(t, db.Create(&image).Error)
+	assert.NoError(t, db.Create(&domain.Like{ID: "like-" + suffix, UserID: liker.ID, ImageID: &image.ID}).Error)
+	assert.NoError(t, db.Create(&domain.Follow{ID: "follow-" + suffix, FollowerID: liker.ID, FolloweeID: owner.ID, Status: domain.FollowStatusAccepted}).Error)
+	assert.NoError(t, db.Create(&domain.Follow{ID: "follow-back-" + suffix, FollowerID: owner.ID, FolloweeID: liker.ID, Status: domain.FollowStatusAccepted}).Error)
+	assert.NoError(t, db.Model(&domain.User{}).Where("id = ?", owner.ID).Updates(map[string]interface{}{"follower_count": 1, "following_count": 1}).Error)
+
+	t.Run("delete a user and take their likes off the like counts", func(t *testing.T) {
+		assert.NoError(t, userRepository.Delete(context.Background(), liker.ID))
+
+		liked := domain.Image{}
+
+		assert.NoError(t, db.Take(&liked, "id = ?", image.ID).Error)
+		assert.Equal(t, 0, liked.LikeCount)
+	})
+
+	t.Run("delete a user and take their follows off the counts", func(t *testing.T) {
+		followed := domain.User{}
+
+		assert.NoError(t, db.Take(&followed, "id = ?", owner.ID).Error)
+		assert.Equal(t, 0, followed.FollowerCount)
+		assert.Equal(t, 0, followed.FollowingCount)
+	})
+}
+
+func TestDeleteComments(t *testing.T) {
+	db := testDB(t)
+	userRepository := repository.NewUserRepository(db)
+	owner := register(t, db)
+	commenter := register(t, db)
+
+	suffix, _ := gonanoid.New(10)
+	image := domain.Image{ID: "image-" + suffix, Title: "title", ImageUrl: "https://www.example.com/image.jpg", UserID: owner.ID}
+	ownImage := domain.Image{ID: "own-image-" + suffix, Title: "title", ImageUrl: "https://www.example.com/image.jpg", UserID: commenter.ID}
+
+	assert.NoError(t, db.Create(&image).Error)
+	assert.NoError(t, db.Create(&ownImage).Error)
+
+	t.Cleanup(func() {
+		db.Delete(&domain.Image{}, "id = ?", image.ID)
+	})
+
+	comment := func(id string, userID string, imageID string, parent *domain.Comment, replies int) domain.Comment {
+		c := domain.Comment{ID: id + "-" + suffix, UserID: userID, ImageID: imageID, Message: "message", ReplyCount: replies}
+
+		if parent != nil {
+			c.ParentID = &parent.ID
+			c.Depth = parent.Depth + 1
+		}
+
+		assert.NoError(t, db.Create(&c).Error)
+
+		return c
+	}
+
+	replied := comment("replied", commenter.ID, image.ID, nil, 1)
+	reply := comment("reply", owner.ID, image.ID, &replied, 0)
+	question := comment("question", owner.ID, image.ID, nil, 1)
+	answer := comment("answer", commenter.ID, image.ID, &question, 1)
+	comment("follow-up", commenter.ID, image.ID, &answer, 0)
+	onOwnImage := comment("on-own-image", commenter.ID, ownImage.ID, nil, 1)
+	comment("reply-on-own-image", owner.ID, ownImage.ID, &onOwnImage, 0)
+
+	t.Run("delete a user and keep the comments others replied to as tombstones", func(t *testing.T) {
+		assert.NoError(t, userRepository.Delete(context.Background(), commenter.ID))
+
+		tombstone := domain.Comment{}
+
+		assert.NoError(t, db.Take(&tombstone, "id = ?", replied.ID).Error)
+		assert.Equal(t, domain.DeletedCommentMessage, tombstone.Message)
+		assert.NotNil(t, tombstone.DeletedAt)
+		assert.Empty(t, tombstone.UserID)
+		assert.Equal(t, 1, tombstone.ReplyCount)
+		assert.NoError(t, db.Take(&domain.Comment{}, "id = ?", reply.ID).Error)
+	})
+
+	t.Run("delete a user and take their replies off the reply counts", func(t *testing.T) {
+		replied := domain.Comment{}
+
+		assert.NoError(t, db.Take(&replied, "id = ?", question.ID).Error)
+		assert.Equal(t, 0, replied.ReplyCount)
+		assert.ErrorIs(t, db.Take(&domain.Comment{}, "id = ?", answer.ID).Error, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("delete a user along with the comments on their images", func(t *testing.T) {
+		var count int64
+
+		assert.NoError(t, db.Model(&domain.Comment{}).Where("image_id = ?", ownImage.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
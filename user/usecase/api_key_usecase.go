@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"time"
+)
+
+const (
+	// apiKeyPrefixLength is how much of a key is kept in the clear to tell
+	// the keys of a user apart.
+	apiKeyPrefixLength = 12
+
+	// lastUsedInterval is how stale the last use of a key gets before it is
+	// written again, so busy scripts don't write on every request.
+	lastUsedInterval = time.Minute
+)
+
+type apiKeyUseCase struct {
+	userRepository   domain.UserRepository
+	apiKeyRepository domain.APIKeyRepository
+}
+
+func NewAPIKeyUseCase(userRepository domain.UserRepository, apiKeyRepository domain.APIKeyRepository) *apiKeyUseCase {
+	return &apiKeyUseCase{userRepository, apiKeyRepository}
+}
+
+// Create issues an API key for apiKey.UserID with apiKey.Scopes. The plain
+// key is only available in apiKey.Key afterwards.
+func (apiKeyUseCase *apiKeyUseCase) Create(ctx context.Context, apiKey *domain.APIKey) (err error) {
+	if apiKey.Scopes, err = normalizeScopes(apiKey.Scopes); err != nil {
+		return err
+	}
+
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(time.Now()) {
+		return domain.ErrAPIKeyExpiry
+	}
+
+	token, err := helpers.GenerateOpaqueToken()
+
+	if err != nil {
+		return err
+	}
+
+	apiKey.Key = domain.APIKeyPrefix + token
+	apiKey.Prefix = apiKey.Key[:apiKeyPrefixLength]
+	apiKey.KeyHash = helpers.HashToken(apiKey.Key)
+
+	if err = apiKeyUseCase.apiKeyRepository.Create(ctx, apiKey); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyUseCase *apiKeyUseCase) GetByUser(ctx context.Context, apiKeys *[]domain.APIKey, userID string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.GetByUser(ctx, apiKeys, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyUseCase *apiKeyUseCase) Revoke(ctx context.Context, userID string, id string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.Revoke(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Authenticate loads the API key key is, along with its user into
+// apiKey.User, and records that it was used. Keys that are revoked or
+// expired, and the keys of suspended users, are refused.
+func (apiKeyUseCase *apiKeyUseCase) Authenticate(ctx context.Context, apiKey *domain.APIKey, key string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.GetByHash(ctx, apiKey, helpers.HashToken(key)); err != nil {
+		return domain.ErrAPIKeyInvalid
+	}
+
+	now := time.Now()
+
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return domain.ErrAPIKeyInvalid
+	}
+
+	user := domain.User{}
+
+	if err = apiKeyUseCase.userRepository.GetByID(ctx, &user, apiKey.UserID); err != nil {
+		return domain.ErrAPIKeyInvalid
+	}
+
+	if user.SuspendedAt != nil {
+		return domain.ErrUserSuspended
+	}
+
+	apiKey.User = &user
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedInterval {
+		if err = apiKeyUseCase.apiKeyRepository.Use(ctx, apiKey.ID, now); err != nil {
+			return err
+		}
+
+		apiKey.LastUsedAt = &now
+	}
+
+	return
+}
+
+// normalizeScopes drops repeated scopes and refuses unknown ones and an
+// empty list.
+func normalizeScopes(scopes []string) (normalized []string, err error) {
+	seen := map[string]bool{}
+
+	for _, scope := range scopes {
+		if !isScope(scope) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnknownScope, scope)
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, domain.ErrAPIKeyScopes
+	}
+
+	return normalized, nil
+}
+
+func isScope(scope string) bool {
+	for _, known := range domain.Scopes {
+		if scope == known {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/helpers"
+	"strings"
+	"testing"
+	"time"
+
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockUserRepository, mockAPIKeyRepository)
+
+	t.Run("create API key correctly", func(t *testing.T) {
+		apiKey := domain.APIKey{UserID: "user-123", Name: "backup", Scopes: []string{domain.ScopeImagesRead, domain.ScopeImagesRead, domain.ScopeCommentsWrite}}
+
+		mockAPIKeyRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Return(nil).Once()
+
+		err := apiKeyUseCase.Create(context.Background(), &apiKey)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(apiKey.Key, domain.APIKeyPrefix))
+		assert.True(t, strings.HasPrefix(apiKey.Key, apiKey.Prefix))
+		assert.Equal(t, helpers.HashToken(apiKey.Key), apiKey.KeyHash)
+		assert.Equal(t, []string{domain.ScopeImagesRead, domain.ScopeCommentsWrite}, apiKey.Scopes)
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("create API key with an unknown scope", func(t *testing.T) {
+		err := apiKeyUseCase.Create(context.Background(), &domain.APIKey{UserID: "user-123", Scopes: []string{"users:write"}})
+
+		assert.ErrorIs(t, err, domain.ErrUnknownScope)
+	})
+
+	t.Run("create API key without scopes", func(t *testing.T) {
+		err := apiKeyUseCase.Create(context.Background(), &domain.APIKey{UserID: "user-123"})
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyScopes)
+	})
+
+	t.Run("create API key that has already expired", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Minute)
+
+		err := apiKeyUseCase.Create(context.Background(), &domain.APIKey{UserID: "user-123", Scopes: []string{domain.ScopeImagesRead}, ExpiresAt: &expiresAt})
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyExpiry)
+	})
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	key := domain.APIKeyPrefix + "abcdefghijklmnop"
+	expiredAt := time.Now().Add(-time.Hour)
+	usedAt := time.Now().Add(-time.Second)
+
+	mockUserRepository := new(mocks.UserRepository)
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockUserRepository, mockAPIKeyRepository)
+
+	getByHash := func(apiKey domain.APIKey) {
+		mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), helpers.HashToken(key)).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.APIKey) = apiKey
+		}).Return(nil).Once()
+	}
+
+	getUser := func(user domain.User) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = user
+		}).Return(nil).Once()
+	}
+
+	t.Run("authenticate API key correctly", func(t *testing.T) {
+		getByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123", Scopes: []string{domain.ScopeImagesRead}})
+		getUser(domain.User{ID: "user-123", Email: "johndoe@example.com"})
+		mockAPIKeyRepository.On("Use", mock.Anything, "apikey-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		apiKey := domain.APIKey{}
+
+		err := apiKeyUseCase.Authenticate(context.Background(), &apiKey, key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "johndoe@example.com", apiKey.User.Email)
+		assert.NotNil(t, apiKey.LastUsedAt)
+		mockAPIKeyRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("authenticate recently used API key without recording the use", func(t *testing.T) {
+		getByHash(domain.APIKey{ID: "apikey-234", UserID: "user-123", LastUsedAt: &usedAt})
+		getUser(domain.User{ID: "user-123"})
+
+		err := apiKeyUseCase.Authenticate(context.Background(), &domain.APIKey{}, key)
+
+		assert.NoError(t, err)
+		mockAPIKeyRepository.AssertNotCalled(t, "Use", mock.Anything, "apikey-234", mock.Anything)
+	})
+
+	t.Run("authenticate unknown API key", func(t *testing.T) {
+		mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), mock.AnythingOfType("string")).Return(errors.New("record not found")).Once()
+
+		err := apiKeyUseCase.Authenticate(context.Background(), &domain.APIKey{}, domain.APIKeyPrefix+"unknown")
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyInvalid)
+	})
+
+	t.Run("authenticate revoked API key", func(t *testing.T) {
+		revokedAt := time.Now().Add(-time.Minute)
+
+		getByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123", RevokedAt: &revokedAt})
+
+		err := apiKeyUseCase.Authenticate(context.Background(), &domain.APIKey{}, key)
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyInvalid)
+	})
+
+	t.Run("authenticate expired API key", func(t *testing.T) {
+		getByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123", ExpiresAt: &expiredAt})
+
+		err := apiKeyUseCase.Authenticate(context.Background(), &domain.APIKey{}, key)
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyInvalid)
+	})
+
+	t.Run("authenticate API key of a suspended user", func(t *testing.T) {
+		suspendedAt := time.Now()
+
+		getByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123"})
+		getUser(domain.User{ID: "user-123", SuspendedAt: &suspendedAt})
+
+		err := apiKeyUseCase.Authenticate(context.Background(), &domain.APIKey{}, key)
+
+		assert.ErrorIs(t, err, domain.ErrUserSuspended)
+	})
+}
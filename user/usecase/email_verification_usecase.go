@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"net/url"
+	"time"
+)
+
+// resendWindow is the period the number of verification emails a user can
+// be sent is counted over.
+const resendWindow = time.Hour
+
+type emailVerificationUseCase struct {
+	userRepository              domain.UserRepository
+	emailVerificationRepository domain.EmailVerificationRepository
+	mailer                      domain.Mailer
+	ttl                         time.Duration
+	verifyURL                   string
+	resendLimit                 int
+}
+
+// NewEmailVerificationUseCase builds the email verification usecase. At most
+// resendLimit verification emails are sent to a user every hour. verifyURL is
+// the page the emails link to with the token in the query, and may be empty
+// to send the token alone.
+func NewEmailVerificationUseCase(userRepository domain.UserRepository, emailVerificationRepository domain.EmailVerificationRepository, mailer domain.Mailer, ttl time.Duration, verifyURL string, resendLimit int) *emailVerificationUseCase {
+	return &emailVerificationUseCase{userRepository, emailVerificationRepository, mailer, ttl, verifyURL, resendLimit}
+}
+
+// Send emails a verification token for the current email of the user.
+func (emailVerificationUseCase *emailVerificationUseCase) Send(ctx context.Context, userID string) (err error) {
+	user := domain.User{}
+
+	if err = emailVerificationUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	sent, err := emailVerificationUseCase.emailVerificationRepository.CountSince(ctx, userID, time.Now().Add(-resendWindow))
+
+	if err != nil {
+		return err
+	}
+
+	if sent >= int64(emailVerificationUseCase.resendLimit) {
+		return domain.ErrVerificationRateLimited
+	}
+
+	emailVerification := domain.EmailVerification{UserID: user.ID, Email: user.Email}
+
+	if emailVerification.Token, err = helpers.GenerateOpaqueToken(); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(emailVerificationUseCase.ttl)
+
+	emailVerification.TokenHash = helpers.HashToken(emailVerification.Token)
+	emailVerification.ExpiresAt = &expiresAt
+
+	if err = emailVerificationUseCase.emailVerificationRepository.Create(ctx, &emailVerification); err != nil {
+		return err
+	}
+
+	return emailVerificationUseCase.mailer.Send(ctx, domain.Email{
+		To:      user.Email,
+		Subject: "Verify your MyGram email",
+		Body:    emailVerificationUseCase.body(user.Username, emailVerification.Token),
+	})
+}
+
+// Verify marks the email token was sent to as verified.
+func (emailVerificationUseCase *emailVerificationUseCase) Verify(ctx context.Context, token string) (err error) {
+	emailVerification := domain.EmailVerification{}
+
+	if err = emailVerificationUseCase.emailVerificationRepository.GetByHash(ctx, &emailVerification, helpers.HashToken(token)); err != nil {
+		return domain.ErrEmailVerificationInvalid
+	}
+
+	if emailVerification.UsedAt != nil || emailVerification.ExpiresAt == nil || time.Now().After(*emailVerification.ExpiresAt) {
+		return domain.ErrEmailVerificationInvalid
+	}
+
+	if err = emailVerificationUseCase.emailVerificationRepository.Use(ctx, emailVerification); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (emailVerificationUseCase *emailVerificationUseCase) body(username string, token string) string {
+	link := token
+
+	if emailVerificationUseCase.verifyURL != "" {
+		link = fmt.Sprintf("%s?token=%s", emailVerificationUseCase.verifyURL, url.QueryEscape(token))
+	}
+
+	return fmt.Sprintf(
+		"Hi %s,\n\nConfirm this is your email to finish setting up your MyGram account:\n\n%s\n\nThe token expires in %d hours. If you didn't sign up for MyGram, ignore this email.\n",
+		username,
+		link,
+		int(emailVerificationUseCase.ttl.Hours()),
+	)
+}
@@ -0,0 +1,126 @@
+package usecase_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/helpers"
+	"testing"
+	"time"
+
+	mail "mygram-byferdiansyah/mail/outbox"
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSendVerification(t *testing.T) {
+	unverified := domain.User{ID: "user-123", Username: "johndoe", Email: "johndoe@example.com"}
+
+	t.Run("send verification email", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockEmailVerificationRepository := new(mocks.EmailVerificationRepository)
+		mailer := mail.NewMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mockEmailVerificationRepository, mailer, 24*time.Hour, "https://mygram.example.com/verify", 3)
+
+		var created *domain.EmailVerification
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = unverified
+		}).Return(nil).Once()
+		mockEmailVerificationRepository.On("CountSince", mock.Anything, "user-123", mock.AnythingOfType("time.Time")).Return(int64(2), nil).Once()
+		mockEmailVerificationRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.EmailVerification")).Run(func(args mock.Arguments) {
+			created = args.Get(1).(*domain.EmailVerification)
+		}).Return(nil).Once()
+
+		err := emailVerificationUseCase.Send(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		mockEmailVerificationRepository.AssertExpectations(t)
+
+		email, ok := mailer.Last("johndoe@example.com")
+
+		assert.True(t, ok)
+		assert.Equal(t, "johndoe@example.com", created.Email)
+		assert.Equal(t, helpers.HashToken(created.Token), created.TokenHash)
+		assert.Contains(t, email.Body, "https://mygram.example.com/verify?token="+created.Token)
+	})
+
+	t.Run("send verification email over the limit", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockEmailVerificationRepository := new(mocks.EmailVerificationRepository)
+		mailer := mail.NewMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mockEmailVerificationRepository, mailer, 24*time.Hour, "", 3)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = unverified
+		}).Return(nil).Once()
+		mockEmailVerificationRepository.On("CountSince", mock.Anything, "user-123", mock.AnythingOfType("time.Time")).Return(int64(3), nil).Once()
+
+		err := emailVerificationUseCase.Send(context.Background(), "user-123")
+
+		assert.ErrorIs(t, err, domain.ErrVerificationRateLimited)
+		assert.Empty(t, mailer.Emails())
+		mockEmailVerificationRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("send verification email to a verified user", func(t *testing.T) {
+		verifiedAt := time.Now()
+		mockUserRepository := new(mocks.UserRepository)
+		mockEmailVerificationRepository := new(mocks.EmailVerificationRepository)
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mockEmailVerificationRepository, mail.NewMailer(), 24*time.Hour, "", 3)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123", Email: "johndoe@example.com", EmailVerifiedAt: &verifiedAt}
+		}).Return(nil).Once()
+
+		err := emailVerificationUseCase.Send(context.Background(), "user-123")
+
+		assert.ErrorIs(t, err, domain.ErrEmailAlreadyVerified)
+		mockEmailVerificationRepository.AssertNotCalled(t, "CountSince", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Minute)
+
+	mockEmailVerificationRepository := new(mocks.EmailVerificationRepository)
+	emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(new(mocks.UserRepository), mockEmailVerificationRepository, mail.NewMailer(), 24*time.Hour, "", 3)
+
+	t.Run("verify email correctly", func(t *testing.T) {
+		emailVerification := domain.EmailVerification{ID: "emailverification-123", UserID: "user-123", Email: "johndoe@example.com", ExpiresAt: &expiresAt}
+
+		mockEmailVerificationRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.EmailVerification"), helpers.HashToken("token")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.EmailVerification) = emailVerification
+		}).Return(nil).Once()
+		mockEmailVerificationRepository.On("Use", mock.Anything, emailVerification).Return(nil).Once()
+
+		err := emailVerificationUseCase.Verify(context.Background(), "token")
+
+		assert.NoError(t, err)
+		mockEmailVerificationRepository.AssertExpectations(t)
+	})
+
+	t.Run("verify email with an expired token", func(t *testing.T) {
+		mockEmailVerificationRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.EmailVerification"), helpers.HashToken("expired")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.EmailVerification) = domain.EmailVerification{ID: "emailverification-234", ExpiresAt: &expiredAt}
+		}).Return(nil).Once()
+
+		err := emailVerificationUseCase.Verify(context.Background(), "expired")
+
+		assert.ErrorIs(t, err, domain.ErrEmailVerificationInvalid)
+		mockEmailVerificationRepository.AssertExpectations(t)
+	})
+
+	t.Run("verify email with an unknown token", func(t *testing.T) {
+		mockEmailVerificationRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.EmailVerification"), helpers.HashToken("unknown")).Return(gorm.ErrRecordNotFound).Once()
+
+		err := emailVerificationUseCase.Verify(context.Background(), "unknown")
+
+		assert.ErrorIs(t, err, domain.ErrEmailVerificationInvalid)
+		mockEmailVerificationRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"strings"
+	"time"
+)
+
+type loginAttemptUseCase struct {
+	userRepository         domain.UserRepository
+	loginAttemptRepository domain.LoginAttemptRepository
+	maxAttempts            int
+	ipMaxAttempts          int
+	lockout                time.Duration
+}
+
+// NewLoginAttemptUseCase builds the usecase that guards logins. An account is
+// locked out for lockout after maxAttempts failed logins in a row, and a
+// client address after ipMaxAttempts, whichever accounts they were for.
+func NewLoginAttemptUseCase(userRepository domain.UserRepository, loginAttemptRepository domain.LoginAttemptRepository, maxAttempts int, ipMaxAttempts int, lockout time.Duration) *loginAttemptUseCase {
+	return &loginAttemptUseCase{userRepository, loginAttemptRepository, maxAttempts, ipMaxAttempts, lockout}
+}
+
+// Login checks the credentials in user for a client at ip. Every attempt
+// is counted against the account and the address before the password is
+// checked, so parallel attempts can't all get in under the limit. A
+// successful one takes its count back and clears the count of the account.
+func (loginAttemptUseCase *loginAttemptUseCase) Login(ctx context.Context, user *domain.User, ip string) (err error) {
+	accountKey := "account:" + strings.ToLower(strings.TrimSpace(user.Email))
+	ipKey := "ip:" + ip
+
+	retryAfter, err := loginAttemptUseCase.loginAttemptRepository.Reserve(ctx, accountKey, domain.LoginAttemptPolicy{
+		MaxAttempts: loginAttemptUseCase.maxAttempts,
+		Lockout:     loginAttemptUseCase.lockout,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if retryAfter > 0 {
+		return &domain.LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	if retryAfter, err = loginAttemptUseCase.loginAttemptRepository.Reserve(ctx, ipKey, domain.LoginAttemptPolicy{
+		MaxAttempts: loginAttemptUseCase.ipMaxAttempts,
+		Lockout:     loginAttemptUseCase.lockout,
+	}); err != nil || retryAfter > 0 {
+		if releaseErr := loginAttemptUseCase.loginAttemptRepository.Release(ctx, accountKey); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return &domain.LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	if err = loginAttemptUseCase.userRepository.Login(ctx, user); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			return domain.ErrInvalidCredentials
+		}
+
+		// Only wrong passwords count, not the database failing.
+		for _, key := range []string{accountKey, ipKey} {
+			_ = loginAttemptUseCase.loginAttemptRepository.Release(ctx, key)
+		}
+
+		return err
+	}
+
+	if err = loginAttemptUseCase.loginAttemptRepository.Reset(ctx, accountKey); err != nil {
+		return err
+	}
+
+	if err = loginAttemptUseCase.loginAttemptRepository.Release(ctx, ipKey); err != nil {
+		return err
+	}
+
+	return
+}
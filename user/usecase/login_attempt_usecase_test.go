@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"sync"
+	"testing"
+	"time"
+
+	loginAttemptRepository "mygram-byferdiansyah/user/repository/memory"
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginAttempt(t *testing.T) {
+	accountPolicy := domain.LoginAttemptPolicy{MaxAttempts: 5, Lockout: 15 * time.Minute}
+	ipPolicy := domain.LoginAttemptPolicy{MaxAttempts: 20, Lockout: 15 * time.Minute}
+
+	newLoginAttemptUseCase := func() (domain.LoginAttemptUseCase, *mocks.UserRepository, *mocks.LoginAttemptRepository) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockLoginAttemptRepository := new(mocks.LoginAttemptRepository)
+
+		return userUseCase.NewLoginAttemptUseCase(mockUserRepository, mockLoginAttemptRepository, 5, 20, 15*time.Minute), mockUserRepository, mockLoginAttemptRepository
+	}
+
+	t.Run("login and clear the failed logins of the account", func(t *testing.T) {
+		loginAttemptUseCase, mockUserRepository, mockLoginAttemptRepository := newLoginAttemptUseCase()
+
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "account:johndoe@example.com", accountPolicy).Return(time.Duration(0), nil).Once()
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "ip:203.0.113.7", ipPolicy).Return(time.Duration(0), nil).Once()
+		mockUserRepository.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+		mockLoginAttemptRepository.On("Reset", mock.Anything, "account:johndoe@example.com").Return(nil).Once()
+		mockLoginAttemptRepository.On("Release", mock.Anything, "ip:203.0.113.7").Return(nil).Once()
+
+		err := loginAttemptUseCase.Login(context.Background(), &domain.User{Email: "JohnDoe@example.com", Password: "secret"}, "203.0.113.7")
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockLoginAttemptRepository.AssertExpectations(t)
+	})
+
+	t.Run("login with wrong credentials", func(t *testing.T) {
+		loginAttemptUseCase, mockUserRepository, mockLoginAttemptRepository := newLoginAttemptUseCase()
+
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "account:johndoe@example.com", accountPolicy).Return(time.Duration(0), nil).Once()
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "ip:203.0.113.7", ipPolicy).Return(time.Duration(0), nil).Once()
+		mockUserRepository.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(domain.ErrInvalidCredentials).Once()
+
+		err := loginAttemptUseCase.Login(context.Background(), &domain.User{Email: "johndoe@example.com", Password: "wrong"}, "203.0.113.7")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		mockLoginAttemptRepository.AssertExpectations(t)
+		mockLoginAttemptRepository.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+		mockLoginAttemptRepository.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("login to a locked out account", func(t *testing.T) {
+		loginAttemptUseCase, mockUserRepository, mockLoginAttemptRepository := newLoginAttemptUseCase()
+
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "account:johndoe@example.com", accountPolicy).Return(14*time.Minute, nil).Once()
+
+		err := loginAttemptUseCase.Login(context.Background(), &domain.User{Email: "johndoe@example.com", Password: "secret"}, "203.0.113.7")
+
+		var loginLockedError *domain.LoginLockedError
+
+		assert.True(t, errors.As(err, &loginLockedError))
+		assert.Equal(t, 14*time.Minute, loginLockedError.RetryAfter)
+		mockUserRepository.AssertNotCalled(t, "Login", mock.Anything, mock.Anything)
+	})
+
+	t.Run("login from a locked out address", func(t *testing.T) {
+		loginAttemptUseCase, mockUserRepository, mockLoginAttemptRepository := newLoginAttemptUseCase()
+
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "account:johndoe@example.com", accountPolicy).Return(time.Duration(0), nil).Once()
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "ip:203.0.113.7", ipPolicy).Return(14*time.Minute, nil).Once()
+		mockLoginAttemptRepository.On("Release", mock.Anything, "account:johndoe@example.com").Return(nil).Once()
+
+		err := loginAttemptUseCase.Login(context.Background(), &domain.User{Email: "johndoe@example.com", Password: "secret"}, "203.0.113.7")
+
+		var loginLockedError *domain.LoginLockedError
+
+		assert.True(t, errors.As(err, &loginLockedError))
+		assert.Equal(t, 14*time.Minute, loginLockedError.RetryAfter)
+		mockLoginAttemptRepository.AssertExpectations(t)
+		mockUserRepository.AssertNotCalled(t, "Login", mock.Anything, mock.Anything)
+	})
+
+	t.Run("login while the database fails", func(t *testing.T) {
+		loginAttemptUseCase, mockUserRepository, mockLoginAttemptRepository := newLoginAttemptUseCase()
+
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "account:johndoe@example.com", accountPolicy).Return(time.Duration(0), nil).Once()
+		mockLoginAttemptRepository.On("Reserve", mock.Anything, "ip:203.0.113.7", ipPolicy).Return(time.Duration(0), nil).Once()
+		mockUserRepository.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("connection refused")).Once()
+		mockLoginAttemptRepository.On("Release", mock.Anything, "account:johndoe@example.com").Return(nil).Once()
+		mockLoginAttemptRepository.On("Release", mock.Anything, "ip:203.0.113.7").Return(nil).Once()
+
+		err := loginAttemptUseCase.Login(context.Background(), &domain.User{Email: "johndoe@example.com", Password: "secret"}, "203.0.113.7")
+
+		assert.EqualError(t, err, "connection refused")
+		mockLoginAttemptRepository.AssertExpectations(t)
+	})
+
+	t.Run("login in parallel with wrong credentials", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockUserRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 20, 15*time.Minute)
+
+		mockUserRepository.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(domain.ErrInvalidCredentials)
+
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			locked int
+		)
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				err := loginAttemptUseCase.Login(context.Background(), &domain.User{Email: "johndoe@example.com", Password: "wrong"}, "203.0.113.7")
+
+				var loginLockedError *domain.LoginLockedError
+
+				if errors.As(err, &loginLockedError) {
+					mu.Lock()
+					locked++
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 19, locked)
+		mockUserRepository.AssertNumberOfCalls(t, "Login", 1)
+	})
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+type passwordUseCase struct {
+	userRepository            domain.UserRepository
+	passwordResetRepository   domain.PasswordResetRepository
+	refreshTokenRepository    domain.RefreshTokenRepository
+	tokenRevocationRepository domain.TokenRevocationRepository
+	mailer                    domain.Mailer
+	resetTTL                  time.Duration
+	resetURL                  string
+}
+
+// NewPasswordUseCase builds the password usecase. resetURL is the page the
+// reset emails link to with the token in the query, and may be empty to
+// send the token alone.
+func NewPasswordUseCase(userRepository domain.UserRepository, passwordResetRepository domain.PasswordResetRepository, refreshTokenRepository domain.RefreshTokenRepository, tokenRevocationRepository domain.TokenRevocationRepository, mailer domain.Mailer, resetTTL time.Duration, resetURL string) *passwordUseCase {
+	return &passwordUseCase{userRepository, passwordResetRepository, refreshTokenRepository, tokenRevocationRepository, mailer, resetTTL, resetURL}
+}
+
+// Change sets the password of the user to password once current matches the
+// one stored, then signs the user out everywhere.
+func (passwordUseCase *passwordUseCase) Change(ctx context.Context, userID string, current string, password string) (err error) {
+	if utf8.RuneCountInString(password) < domain.MinPasswordLength {
+		return domain.ErrPasswordTooShort
+	}
+
+	user := domain.User{}
+
+	if err = passwordUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return err
+	}
+
+	if !helpers.Compare([]byte(user.Password), []byte(current)) {
+		return domain.ErrWrongPassword
+	}
+
+	if err = passwordUseCase.userRepository.SetPassword(ctx, userID, helpers.Hash(password)); err != nil {
+		return err
+	}
+
+	return passwordUseCase.signOut(ctx, userID)
+}
+
+// RequestReset emails a reset token to the user with the email. Nothing is
+// sent to an email without an account, or to a suspended account, and no
+// error tells the caller so.
+func (passwordUseCase *passwordUseCase) RequestReset(ctx context.Context, email string) (err error) {
+	user := domain.User{}
+
+	if err = passwordUseCase.userRepository.GetByEmail(ctx, &user, email); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if user.SuspendedAt != nil {
+		return nil
+	}
+
+	passwordReset := domain.PasswordReset{UserID: user.ID}
+
+	if passwordReset.Token, err = helpers.GenerateOpaqueToken(); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(passwordUseCase.resetTTL)
+
+	passwordReset.TokenHash = helpers.HashToken(passwordReset.Token)
+	passwordReset.ExpiresAt = &expiresAt
+
+	if err = passwordUseCase.passwordResetRepository.Create(ctx, &passwordReset); err != nil {
+		return err
+	}
+
+	return passwordUseCase.mailer.Send(ctx, domain.Email{
+		To:      user.Email,
+		Subject: "Reset your MyGram password",
+		Body:    passwordUseCase.resetBody(passwordReset.Token),
+	})
+}
+
+// Reset sets a new password for the user token was issued to. The token is
+// used up and every session of the user is signed out.
+func (passwordUseCase *passwordUseCase) Reset(ctx context.Context, token string, password string) (err error) {
+	if utf8.RuneCountInString(password) < domain.MinPasswordLength {
+		return domain.ErrPasswordTooShort
+	}
+
+	passwordReset := domain.PasswordReset{}
+
+	if err = passwordUseCase.passwordResetRepository.GetByHash(ctx, &passwordReset, helpers.HashToken(token)); err != nil {
+		return domain.ErrPasswordResetInvalid
+	}
+
+	if passwordReset.UsedAt != nil || passwordReset.ExpiresAt == nil || time.Now().After(*passwordReset.ExpiresAt) {
+		return domain.ErrPasswordResetInvalid
+	}
+
+	if err = passwordUseCase.passwordResetRepository.Use(ctx, passwordReset, helpers.Hash(password)); err != nil {
+		return err
+	}
+
+	return passwordUseCase.signOut(ctx, passwordReset.UserID)
+}
+
+func (passwordUseCase *passwordUseCase) signOut(ctx context.Context, userID string) (err error) {
+	if err = passwordUseCase.tokenRevocationRepository.RevokeAll(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+
+	if err = passwordUseCase.refreshTokenRepository.RevokeAll(ctx, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (passwordUseCase *passwordUseCase) resetBody(token string) string {
+	link := token
+
+	if passwordUseCase.resetURL != "" {
+		link = fmt.Sprintf("%s?token=%s", passwordUseCase.resetURL, url.QueryEscape(token))
+	}
+
+	return fmt.Sprintf(
+		"Someone asked to reset the password of your MyGram account. To choose a new one, use\n\n%s\n\nIt expires in %d minutes and works once. If it wasn't you, ignore this email and your password stays the same.\n",
+		link,
+		int(passwordUseCase.resetTTL.Minutes()),
+	)
+}
@@ -0,0 +1,217 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/helpers"
+	"strings"
+	"testing"
+	"time"
+
+	mail "mygram-byferdiansyah/mail/outbox"
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type passwordMocks struct {
+	userRepository            *mocks.UserRepository
+	passwordResetRepository   *mocks.PasswordResetRepository
+	refreshTokenRepository    *mocks.RefreshTokenRepository
+	tokenRevocationRepository *mocks.TokenRevocationRepository
+}
+
+func newPasswordUseCase(mailer domain.Mailer) (domain.PasswordUseCase, passwordMocks) {
+	m := passwordMocks{
+		new(mocks.UserRepository),
+		new(mocks.PasswordResetRepository),
+		new(mocks.RefreshTokenRepository),
+		new(mocks.TokenRevocationRepository),
+	}
+
+	return userUseCase.NewPasswordUseCase(m.userRepository, m.passwordResetRepository, m.refreshTokenRepository, m.tokenRevocationRepository, mailer, time.Hour, "https://mygram.example.com/reset"), m
+}
+
+func (m passwordMocks) expectSignOut(userID string) {
+	m.tokenRevocationRepository.On("RevokeAll", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	m.refreshTokenRepository.On("RevokeAll", mock.Anything, userID).Return(nil).Once()
+}
+
+func TestChangePassword(t *testing.T) {
+	stored := domain.User{ID: "user-123", Password: helpers.Hash("secret")}
+
+	t.Run("change password and sign out everywhere", func(t *testing.T) {
+		passwordUseCase, m := newPasswordUseCase(mail.NewMailer())
+
+		m.userRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = stored
+		}).Return(nil).Once()
+		m.userRepository.On("SetPassword", mock.Anything, "user-123", mock.MatchedBy(func(password string) bool {
+			return helpers.Compare([]byte(password), []byte("newsecret"))
+		})).Return(nil).Once()
+		m.expectSignOut("user-123")
+
+		err := passwordUseCase.Change(context.Background(), "user-123", "secret", "newsecret")
+
+		assert.NoError(t, err)
+		m.userRepository.AssertExpectations(t)
+		m.tokenRevocationRepository.AssertExpectations(t)
+		m.refreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("change password with a wrong current password", func(t *testing.T) {
+		passwordUseCase, m := newPasswordUseCase(mail.NewMailer())
+
+		m.userRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = stored
+		}).Return(nil).Once()
+
+		err := passwordUseCase.Change(context.Background(), "user-123", "wrong", "newsecret")
+
+		assert.ErrorIs(t, err, domain.ErrWrongPassword)
+		m.userRepository.AssertNotCalled(t, "SetPassword", mock.Anything, mock.Anything, mock.Anything)
+		m.tokenRevocationRepository.AssertNotCalled(t, "RevokeAll", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("change password to a short one", func(t *testing.T) {
+		passwordUseCase, m := newPasswordUseCase(mail.NewMailer())
+
+		err := passwordUseCase.Change(context.Background(), "user-123", "secret", "short")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordTooShort)
+		m.userRepository.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRequestReset(t *testing.T) {
+	t.Run("request reset and email the token", func(t *testing.T) {
+		mailer := mail.NewMailer()
+		passwordUseCase, m := newPasswordUseCase(mailer)
+
+		var created *domain.PasswordReset
+
+		m.userRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "johndoe@example.com").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123", Email: "johndoe@example.com"}
+		}).Return(nil).Once()
+		m.passwordResetRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.PasswordReset")).Run(func(args mock.Arguments) {
+			created = args.Get(1).(*domain.PasswordReset)
+		}).Return(nil).Once()
+
+		err := passwordUseCase.RequestReset(context.Background(), "johndoe@example.com")
+
+		assert.NoError(t, err)
+		m.passwordResetRepository.AssertExpectations(t)
+
+		email, ok := mailer.Last("johndoe@example.com")
+
+		assert.True(t, ok)
+		assert.Equal(t, "user-123", created.UserID)
+		assert.Equal(t, helpers.HashToken(created.Token), created.TokenHash)
+		assert.True(t, created.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+		assert.Contains(t, email.Body, "https://mygram.example.com/reset?token="+created.Token)
+		assert.NotContains(t, email.Body, created.TokenHash)
+	})
+
+	t.Run("request reset for an unknown email", func(t *testing.T) {
+		mailer := mail.NewMailer()
+		passwordUseCase, m := newPasswordUseCase(mailer)
+
+		m.userRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "nobody@example.com").Return(gorm.ErrRecordNotFound).Once()
+
+		err := passwordUseCase.RequestReset(context.Background(), "nobody@example.com")
+
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.Emails())
+		m.passwordResetRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("request reset when the email can't be sent", func(t *testing.T) {
+		mockMailer := new(mocks.Mailer)
+		passwordUseCase, m := newPasswordUseCase(mockMailer)
+
+		m.userRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "johndoe@example.com").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123", Email: "johndoe@example.com"}
+		}).Return(nil).Once()
+		m.passwordResetRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.PasswordReset")).Return(nil).Once()
+		mockMailer.On("Send", mock.Anything, mock.AnythingOfType("domain.Email")).Return(errors.New("connection refused")).Once()
+
+		err := passwordUseCase.RequestReset(context.Background(), "johndoe@example.com")
+
+		assert.Error(t, err)
+		mockMailer.AssertExpectations(t)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Minute)
+	usedAt := time.Now().Add(-time.Minute)
+
+	t.Run("reset password and sign out everywhere", func(t *testing.T) {
+		passwordUseCase, m := newPasswordUseCase(mail.NewMailer())
+		passwordReset := domain.PasswordReset{ID: "passwordreset-123", UserID: "user-123", ExpiresAt: &expiresAt}
+
+		m.passwordResetRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), helpers.HashToken("token")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.PasswordReset) = passwordReset
+		}).Return(nil).Once()
+		m.passwordResetRepository.On("Use", mock.Anything, passwordReset, mock.MatchedBy(func(password string) bool {
+			return strings.HasPrefix(password, "$2") && helpers.Compare([]byte(password), []byte("newsecret"))
+		})).Return(nil).Once()
+		m.expectSignOut("user-123")
+
+		err := passwordUseCase.Reset(context.Background(), "token", "newsecret")
+
+		assert.NoError(t, err)
+		m.passwordResetRepository.AssertExpectations(t)
+		m.tokenRevocationRepository.AssertExpectations(t)
+		m.refreshTokenRepository.AssertExpectations(t)
+	})
+
+	for name, passwordReset := range map[string]domain.PasswordReset{
+		"reset password with an expired token": {ID: "passwordreset-123", UserID: "user-123", ExpiresAt: &expiredAt},
+		"reset password with a used token":     {ID: "passwordreset-123", UserID: "user-123", ExpiresAt: &expiresAt, UsedAt: &usedAt},
+	} {
+		passwordReset := passwordReset
+
+		t.Run(name, func(t *testing.T) {
+			passwordUseCase, m := newPasswordUseCase(mail.NewMailer())
+
+			m.passwordResetRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+				*args.Get(1).(*domain.PasswordReset) = passwordReset
+			}).Return(nil).Once()
+
+			err := passwordUseCase.Reset(context.Background(), "token", "newsecret")
+
+			assert.ErrorIs(t, err, domain.ErrPasswordResetInvalid)
+			m.passwordResetRepository.AssertNotCalled(t, "Use", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("reset password with an unknown token", func(t *testing.T) {
+		passwordUseCase, m := newPasswordUseCase(mail.NewMailer())
+
+		m.passwordResetRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), mock.AnythingOfType("string")).Return(gorm.ErrRecordNotFound).Once()
+
+		err := passwordUseCase.Reset(context.Background(), "unknown", "newsecret")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordResetInvalid)
+	})
+
+	t.Run("reset password with a token used in the meantime", func(t *testing.T) {
+		passwordUseCase, m := newPasswordUseCase(mail.NewMailer())
+
+		m.passwordResetRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.PasswordReset) = domain.PasswordReset{ID: "passwordreset-123", UserID: "user-123", ExpiresAt: &expiresAt}
+		}).Return(nil).Once()
+		m.passwordResetRepository.On("Use", mock.Anything, mock.AnythingOfType("domain.PasswordReset"), mock.AnythingOfType("string")).Return(domain.ErrPasswordResetInvalid).Once()
+
+		err := passwordUseCase.Reset(context.Background(), "token", "newsecret")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordResetInvalid)
+		m.tokenRevocationRepository.AssertNotCalled(t, "RevokeAll", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
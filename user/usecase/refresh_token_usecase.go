@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type refreshTokenUseCase struct {
+	refreshTokenRepository domain.RefreshTokenRepository
+	refreshTTL             time.Duration
+}
+
+func NewRefreshTokenUseCase(refreshTokenRepository domain.RefreshTokenRepository, refreshTTL time.Duration) *refreshTokenUseCase {
+	return &refreshTokenUseCase{refreshTokenRepository, refreshTTL}
+}
+
+// Create issues a refresh token for refreshToken.UserID, starting a new
+// family unless refreshToken.FamilyID is already set.
+func (refreshTokenUseCase *refreshTokenUseCase) Create(ctx context.Context, refreshToken *domain.RefreshToken) (err error) {
+	if refreshToken.FamilyID == "" {
+		ID, _ := gonanoid.New(16)
+
+		refreshToken.FamilyID = fmt.Sprintf("family-%s", ID)
+	}
+
+	if err = refreshTokenUseCase.fill(refreshToken); err != nil {
+		return err
+	}
+
+	if err = refreshTokenUseCase.refreshTokenRepository.Create(ctx, refreshToken); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Rotate exchanges token for next. Presenting a token that has already been
+// rotated means it leaked, so its whole family is revoked.
+func (refreshTokenUseCase *refreshTokenUseCase) Rotate(ctx context.Context, next *domain.RefreshToken, token string) (err error) {
+	current := domain.RefreshToken{}
+
+	if err = refreshTokenUseCase.refreshTokenRepository.GetByHash(ctx, &current, helpers.HashToken(token)); err != nil {
+		return domain.ErrRefreshTokenInvalid
+	}
+
+	if current.RevokedAt != nil {
+		return refreshTokenUseCase.reused(ctx, current.FamilyID)
+	}
+
+	if current.ExpiresAt == nil || time.Now().After(*current.ExpiresAt) {
+		return domain.ErrRefreshTokenInvalid
+	}
+
+	next.UserID = current.UserID
+	next.FamilyID = current.FamilyID
+
+	if err = refreshTokenUseCase.fill(next); err != nil {
+		return err
+	}
+
+	if err = refreshTokenUseCase.refreshTokenRepository.Rotate(ctx, current.ID, next); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			return refreshTokenUseCase.reused(ctx, current.FamilyID)
+		}
+
+		return err
+	}
+
+	return
+}
+
+// Revoke logs out the session token belongs to by revoking its family.
+func (refreshTokenUseCase *refreshTokenUseCase) Revoke(ctx context.Context, token string) (err error) {
+	current := domain.RefreshToken{}
+
+	if err = refreshTokenUseCase.refreshTokenRepository.GetByHash(ctx, &current, helpers.HashToken(token)); err != nil {
+		return domain.ErrRefreshTokenInvalid
+	}
+
+	if err = refreshTokenUseCase.refreshTokenRepository.RevokeFamily(ctx, current.FamilyID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (refreshTokenUseCase *refreshTokenUseCase) fill(refreshToken *domain.RefreshToken) (err error) {
+	if refreshToken.Token, err = helpers.GenerateOpaqueToken(); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(refreshTokenUseCase.refreshTTL)
+
+	refreshToken.TokenHash = helpers.HashToken(refreshToken.Token)
+	refreshToken.ExpiresAt = &expiresAt
+
+	return
+}
+
+func (refreshTokenUseCase *refreshTokenUseCase) reused(ctx context.Context, familyID string) (err error) {
+	if err = refreshTokenUseCase.refreshTokenRepository.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	return domain.ErrRefreshTokenReused
+}
@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/helpers"
+	"testing"
+	"time"
+
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateRefreshToken(t *testing.T) {
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	refreshTokenUseCase := userUseCase.NewRefreshTokenUseCase(mockRefreshTokenRepository, time.Hour)
+
+	t.Run("create refresh token correctly", func(t *testing.T) {
+		refreshToken := domain.RefreshToken{UserID: "user-123"}
+
+		mockRefreshTokenRepository.On("Create", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+
+		err := refreshTokenUseCase.Create(context.Background(), &refreshToken)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, refreshToken.Token)
+		assert.NotEmpty(t, refreshToken.FamilyID)
+		assert.Equal(t, helpers.HashToken(refreshToken.Token), refreshToken.TokenHash)
+		assert.True(t, refreshToken.ExpiresAt.After(time.Now()))
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Hour)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	refreshTokenUseCase := userUseCase.NewRefreshTokenUseCase(mockRefreshTokenRepository, time.Hour)
+
+	t.Run("rotate refresh token correctly", func(t *testing.T) {
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), helpers.HashToken("old")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{ID: "refreshtoken-123", UserID: "user-123", FamilyID: "family-123", ExpiresAt: &expiresAt}
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("Rotate", mock.Anything, "refreshtoken-123", mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+
+		next := domain.RefreshToken{}
+
+		err := refreshTokenUseCase.Rotate(context.Background(), &next, "old")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", next.UserID)
+		assert.Equal(t, "family-123", next.FamilyID)
+		assert.NotEqual(t, "old", next.Token)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("rotate unknown refresh token", func(t *testing.T) {
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Return(errors.New("record not found")).Once()
+
+		err := refreshTokenUseCase.Rotate(context.Background(), &domain.RefreshToken{}, "unknown")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("rotate expired refresh token", func(t *testing.T) {
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{ID: "refreshtoken-123", FamilyID: "family-123", ExpiresAt: &expiredAt}
+		}).Return(nil).Once()
+
+		err := refreshTokenUseCase.Rotate(context.Background(), &domain.RefreshToken{}, "expired")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("rotate already used refresh token revokes the family", func(t *testing.T) {
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{ID: "refreshtoken-123", FamilyID: "family-123", ExpiresAt: &expiresAt, RevokedAt: &revokedAt}
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "family-123").Return(nil).Once()
+
+		err := refreshTokenUseCase.Rotate(context.Background(), &domain.RefreshToken{}, "reused")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	refreshTokenUseCase := userUseCase.NewRefreshTokenUseCase(mockRefreshTokenRepository, time.Hour)
+
+	t.Run("revoke refresh token correctly", func(t *testing.T) {
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{ID: "refreshtoken-123", FamilyID: "family-123"}
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "family-123").Return(nil).Once()
+
+		err := refreshTokenUseCase.Revoke(context.Background(), "token")
+
+		assert.NoError(t, err)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+}
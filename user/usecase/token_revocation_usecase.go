@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"time"
+)
+
+type tokenRevocationUseCase struct {
+	tokenRevocationRepository domain.TokenRevocationRepository
+}
+
+func NewTokenRevocationUseCase(tokenRevocationRepository domain.TokenRevocationRepository) *tokenRevocationUseCase {
+	return &tokenRevocationUseCase{tokenRevocationRepository}
+}
+
+func (tokenRevocationUseCase *tokenRevocationUseCase) Revoke(ctx context.Context, revokedToken domain.RevokedToken) (err error) {
+	if err = tokenRevocationUseCase.tokenRevocationRepository.Revoke(ctx, revokedToken); err != nil {
+		return err
+	}
+
+	return
+}
+
+// RevokeAll invalidates every access token the user holds right now.
+func (tokenRevocationUseCase *tokenRevocationUseCase) RevokeAll(ctx context.Context, userID string) (err error) {
+	if err = tokenRevocationUseCase.tokenRevocationRepository.RevokeAll(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (tokenRevocationUseCase *tokenRevocationUseCase) IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (revoked bool, err error) {
+	if revoked, err = tokenRevocationUseCase.tokenRevocationRepository.IsRevoked(ctx, jti, userID, issuedAt); err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,67 @@
+package usecase_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"testing"
+	"time"
+
+	tokenRevocationRepository "mygram-byferdiansyah/user/repository/memory"
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRevoked(t *testing.T) {
+	t.Run("token is not revoked", func(t *testing.T) {
+		tokenRevocationUseCase := userUseCase.NewTokenRevocationUseCase(tokenRevocationRepository.NewTokenRevocationRepository())
+
+		revoked, err := tokenRevocationUseCase.IsRevoked(context.Background(), "jti-123", "user-123", time.Now())
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("token is revoked by jti", func(t *testing.T) {
+		tokenRevocationUseCase := userUseCase.NewTokenRevocationUseCase(tokenRevocationRepository.NewTokenRevocationRepository())
+		expiresAt := time.Now().Add(time.Hour)
+
+		err := tokenRevocationUseCase.Revoke(context.Background(), domain.RevokedToken{JTI: "jti-123", UserID: "user-123", ExpiresAt: &expiresAt})
+
+		assert.NoError(t, err)
+
+		revoked, err := tokenRevocationUseCase.IsRevoked(context.Background(), "jti-123", "user-123", time.Now())
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = tokenRevocationUseCase.IsRevoked(context.Background(), "jti-234", "user-123", time.Now())
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("tokens issued before revoke all are revoked", func(t *testing.T) {
+		tokenRevocationUseCase := userUseCase.NewTokenRevocationUseCase(tokenRevocationRepository.NewTokenRevocationRepository())
+		issuedBefore := time.Now().Add(-time.Minute)
+
+		err := tokenRevocationUseCase.RevokeAll(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+
+		revoked, err := tokenRevocationUseCase.IsRevoked(context.Background(), "jti-123", "user-123", issuedBefore)
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = tokenRevocationUseCase.IsRevoked(context.Background(), "jti-234", "user-123", time.Now().Add(time.Second))
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+
+		revoked, err = tokenRevocationUseCase.IsRevoked(context.Background(), "jti-345", "user-234", issuedBefore)
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
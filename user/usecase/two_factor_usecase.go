@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/helpers"
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	totpIssuer = "MyGram"
+
+	// challengeTTL is how long a user has to enter the code after the
+	// password, and maxChallengeAttempts how many wrong codes they get.
+	challengeTTL         = 5 * time.Minute
+	maxChallengeAttempts = 5
+
+	// Recovery codes are written as two groups of five characters that
+	// can't be mistaken for one another.
+	recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+	recoveryCodeLength   = 10
+)
+
+type twoFactorUseCase struct {
+	userRepository         domain.UserRepository
+	twoFactorRepository    domain.TwoFactorRepository
+	loginAttemptRepository domain.LoginAttemptRepository
+	maxAttempts            int
+	lockout                time.Duration
+}
+
+// NewTwoFactorUseCase builds the two-factor usecase. Wrong codes are counted
+// against the account across login challenges and reauthentications, which
+// are locked out for lockout after maxAttempts of them in a row.
+func NewTwoFactorUseCase(userRepository domain.UserRepository, twoFactorRepository domain.TwoFactorRepository, loginAttemptRepository domain.LoginAttemptRepository, maxAttempts int, lockout time.Duration) *twoFactorUseCase {
+	return &twoFactorUseCase{userRepository, twoFactorRepository, loginAttemptRepository, maxAttempts, lockout}
+}
+
+// Enroll generates a TOTP secret and recovery codes for the user. Two-factor
+// authentication stays off until a code from the secret is confirmed, and
+// enrolling again replaces an unconfirmed secret.
+func (twoFactorUseCase *twoFactorUseCase) Enroll(ctx context.Context, userID string) (enrollment domain.TwoFactorEnrollment, err error) {
+	user := domain.User{}
+
+	if err = twoFactorUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return enrollment, err
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		return enrollment, domain.ErrTwoFactorEnabled
+	}
+
+	secret, err := helpers.GenerateTOTPSecret()
+
+	if err != nil {
+		return enrollment, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+
+	if err != nil {
+		return enrollment, err
+	}
+
+	if err = twoFactorUseCase.twoFactorRepository.Enroll(ctx, userID, secret, hashes); err != nil {
+		return enrollment, err
+	}
+
+	return domain.TwoFactorEnrollment{
+		Secret:        secret,
+		URI:           helpers.TOTPURI(totpIssuer, user.Email, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Confirm turns two-factor authentication on once code matches the secret
+// the user enrolled with.
+func (twoFactorUseCase *twoFactorUseCase) Confirm(ctx context.Context, userID string, code string) (err error) {
+	user := domain.User{}
+
+	if err = twoFactorUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return err
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		return domain.ErrTwoFactorEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := helpers.ValidateTOTP(user.TOTPSecret, code, time.Now())
+
+	if !ok {
+		return domain.ErrInvalidCode
+	}
+
+	if err = twoFactorUseCase.twoFactorRepository.Enable(ctx, userID, step); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Disable turns two-factor authentication off after checking the password
+// of the user and a current code.
+func (twoFactorUseCase *twoFactorUseCase) Disable(ctx context.Context, userID string, password string, code string) (err error) {
+	if err = twoFactorUseCase.reauthenticate(ctx, userID, password, code); err != nil {
+		return err
+	}
+
+	if err = twoFactorUseCase.twoFactorRepository.Disable(ctx, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user after
+// checking the password and a current code.
+func (twoFactorUseCase *twoFactorUseCase) RegenerateRecoveryCodes(ctx context.Context, userID string, password string, code string) (codes []string, err error) {
+	if err = twoFactorUseCase.reauthenticate(ctx, userID, password, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = twoFactorUseCase.twoFactorRepository.SetRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Challenge issues a login challenge for loginChallenge.UserID, whose
+// password has just been checked.
+func (twoFactorUseCase *twoFactorUseCase) Challenge(ctx context.Context, loginChallenge *domain.LoginChallenge) (err error) {
+	if loginChallenge.Token, err = helpers.GenerateOpaqueToken(); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(challengeTTL)
+
+	loginChallenge.TokenHash = helpers.HashToken(loginChallenge.Token)
+	loginChallenge.ExpiresAt = &expiresAt
+
+	if err = twoFactorUseCase.twoFactorRepository.CreateChallenge(ctx, loginChallenge); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Verify exchanges the challenge token and a TOTP or recovery code for the
+// id of the user signing in. The challenge can only be exchanged once.
+func (twoFactorUseCase *twoFactorUseCase) Verify(ctx context.Context, token string, code string) (userID string, err error) {
+	loginChallenge := domain.LoginChallenge{}
+
+	if err = twoFactorUseCase.twoFactorRepository.GetChallenge(ctx, &loginChallenge, helpers.HashToken(token)); err != nil {
+		return "", domain.ErrChallengeInvalid
+	}
+
+	if loginChallenge.Attempts >= maxChallengeAttempts || loginChallenge.ExpiresAt == nil || time.Now().After(*loginChallenge.ExpiresAt) {
+		return "", domain.ErrChallengeInvalid
+	}
+
+	user := domain.User{}
+
+	if err = twoFactorUseCase.userRepository.GetByID(ctx, &user, loginChallenge.UserID); err != nil {
+		return "", domain.ErrChallengeInvalid
+	}
+
+	// Every attempt is counted before the code is checked, so parallel
+	// guesses can't get past maxChallengeAttempts.
+	if err = twoFactorUseCase.twoFactorRepository.FailChallenge(ctx, loginChallenge.ID, maxChallengeAttempts); err != nil {
+		return "", err
+	}
+
+	if err = twoFactorUseCase.attempt(ctx, user.ID, func() error {
+		return twoFactorUseCase.check(ctx, user, code)
+	}); err != nil {
+		return "", err
+	}
+
+	if err = twoFactorUseCase.twoFactorRepository.DeleteChallenge(ctx, loginChallenge.ID); err != nil {
+		return "", err
+	}
+
+	return user.ID, nil
+}
+
+func (twoFactorUseCase *twoFactorUseCase) reauthenticate(ctx context.Context, userID string, password string, code string) (err error) {
+	user := domain.User{}
+
+	if err = twoFactorUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return err
+	}
+
+	if user.TwoFactorEnabledAt == nil {
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	return twoFactorUseCase.attempt(ctx, user.ID, func() error {
+		if !helpers.Compare([]byte(user.Password), []byte(password)) {
+			return domain.ErrWrongPassword
+		}
+
+		return twoFactorUseCase.check(ctx, user, code)
+	})
+}
+
+// attempt counts a try at the second factor of the user against the account
+// before running it, the same way failed logins are counted, so a new login
+// challenge doesn't bring more guesses. Only a successful one clears the
+// count, and only wrong passwords and codes keep theirs.
+func (twoFactorUseCase *twoFactorUseCase) attempt(ctx context.Context, userID string, try func() error) (err error) {
+	key := "2fa:" + userID
+
+	retryAfter, err := twoFactorUseCase.loginAttemptRepository.Reserve(ctx, key, domain.LoginAttemptPolicy{
+		MaxAttempts: twoFactorUseCase.maxAttempts,
+		Lockout:     twoFactorUseCase.lockout,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if retryAfter > 0 {
+		return &domain.LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	if err = try(); err != nil {
+		if !errors.Is(err, domain.ErrInvalidCode) && !errors.Is(err, domain.ErrWrongPassword) {
+			_ = twoFactorUseCase.loginAttemptRepository.Release(ctx, key)
+		}
+
+		return err
+	}
+
+	return twoFactorUseCase.loginAttemptRepository.Reset(ctx, key)
+}
+
+// check accepts code when it is the current TOTP code of the user, not used
+// before, or one of the unused recovery codes of the user, which it uses up.
+func (twoFactorUseCase *twoFactorUseCase) check(ctx context.Context, user domain.User, code string) (err error) {
+	if user.TwoFactorEnabledAt == nil {
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := helpers.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		return twoFactorUseCase.twoFactorRepository.UseStep(ctx, user.ID, step)
+	}
+
+	return twoFactorUseCase.twoFactorRepository.UseRecoveryCode(ctx, user.ID, helpers.HashToken(normalizeRecoveryCode(code)))
+}
+
+// newRecoveryCodes returns recovery codes to show the user and the hashes of
+// them to store.
+func newRecoveryCodes() (codes []string, hashes []string, err error) {
+	for i := 0; i < domain.RecoveryCodeCount; i++ {
+		code, err := gonanoid.Generate(recoveryCodeAlphabet, recoveryCodeLength)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, helpers.HashToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+}
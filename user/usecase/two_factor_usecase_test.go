@@ -0,0 +1,275 @@
+package usecase_test
+
+import (
+	"context"
+	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/domain/mocks"
+	"mygram-byferdiansyah/helpers"
+	"strings"
+	"testing"
+	"time"
+
+	loginAttemptRepository "mygram-byferdiansyah/user/repository/memory"
+	userUseCase "mygram-byferdiansyah/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const totpSecret = "JBSWY3DPEHPK3PXP"
+
+func TestEnrollTwoFactor(t *testing.T) {
+	t.Run("enroll two-factor authentication", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockTwoFactorRepository := new(mocks.TwoFactorRepository)
+		twoFactorUseCase := userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute)
+
+		var hashes []string
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123", Email: "johndoe@example.com"}
+		}).Return(nil).Once()
+		mockTwoFactorRepository.On("Enroll", mock.Anything, "user-123", mock.AnythingOfType("string"), mock.AnythingOfType("[]string")).Run(func(args mock.Arguments) {
+			hashes = args.Get(3).([]string)
+		}).Return(nil).Once()
+
+		enrollment, err := twoFactorUseCase.Enroll(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		mockTwoFactorRepository.AssertExpectations(t)
+		assert.NotEmpty(t, enrollment.Secret)
+		assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+		assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+		assert.Len(t, enrollment.RecoveryCodes, domain.RecoveryCodeCount)
+		assert.Len(t, hashes, domain.RecoveryCodeCount)
+
+		for i, code := range enrollment.RecoveryCodes {
+			assert.Equal(t, helpers.HashToken(strings.ReplaceAll(code, "-", "")), hashes[i])
+		}
+	})
+
+	t.Run("enroll two-factor authentication when it is enabled", func(t *testing.T) {
+		enabledAt := time.Now()
+		mockUserRepository := new(mocks.UserRepository)
+		mockTwoFactorRepository := new(mocks.TwoFactorRepository)
+		twoFactorUseCase := userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123", TOTPSecret: totpSecret, TwoFactorEnabledAt: &enabledAt}
+		}).Return(nil).Once()
+
+		_, err := twoFactorUseCase.Enroll(context.Background(), "user-123")
+
+		assert.ErrorIs(t, err, domain.ErrTwoFactorEnabled)
+		mockTwoFactorRepository.AssertNotCalled(t, "Enroll", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	enrolled := domain.User{ID: "user-123", TOTPSecret: totpSecret}
+
+	t.Run("confirm two-factor authentication", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockTwoFactorRepository := new(mocks.TwoFactorRepository)
+		twoFactorUseCase := userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute)
+		code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = enrolled
+		}).Return(nil).Once()
+		mockTwoFactorRepository.On("Enable", mock.Anything, "user-123", mock.AnythingOfType("int64")).Return(nil).Once()
+
+		err := twoFactorUseCase.Confirm(context.Background(), "user-123", code)
+
+		assert.NoError(t, err)
+		mockTwoFactorRepository.AssertExpectations(t)
+	})
+
+	t.Run("confirm two-factor authentication with a wrong code", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockTwoFactorRepository := new(mocks.TwoFactorRepository)
+		twoFactorUseCase := userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = enrolled
+		}).Return(nil).Once()
+
+		err := twoFactorUseCase.Confirm(context.Background(), "user-123", "abcdef")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCode)
+		mockTwoFactorRepository.AssertNotCalled(t, "Enable", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	enabledAt := time.Now()
+	mockUserRepository := new(mocks.UserRepository)
+	mockTwoFactorRepository := new(mocks.TwoFactorRepository)
+	twoFactorUseCase := userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute)
+
+	mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+		*args.Get(1).(*domain.User) = domain.User{ID: "user-123", Password: helpers.Hash("secret"), TOTPSecret: totpSecret, TwoFactorEnabledAt: &enabledAt}
+	}).Return(nil)
+
+	t.Run("disable two-factor authentication", func(t *testing.T) {
+		code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+		mockTwoFactorRepository.On("UseStep", mock.Anything, "user-123", mock.AnythingOfType("int64")).Return(nil).Once()
+		mockTwoFactorRepository.On("Disable", mock.Anything, "user-123").Return(nil).Once()
+
+		err := twoFactorUseCase.Disable(context.Background(), "user-123", "secret", code)
+
+		assert.NoError(t, err)
+		mockTwoFactorRepository.AssertExpectations(t)
+	})
+
+	t.Run("disable two-factor authentication with a wrong password", func(t *testing.T) {
+		code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+		err := twoFactorUseCase.Disable(context.Background(), "user-123", "wrong", code)
+
+		assert.ErrorIs(t, err, domain.ErrWrongPassword)
+		mockTwoFactorRepository.AssertNumberOfCalls(t, "Disable", 1)
+	})
+
+	t.Run("disable two-factor authentication right after a wrong code", func(t *testing.T) {
+		twoFactorUseCase := userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute)
+		code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+		mockTwoFactorRepository.On("UseRecoveryCode", mock.Anything, "user-123", mock.AnythingOfType("string")).Return(domain.ErrInvalidCode).Once()
+
+		err := twoFactorUseCase.Disable(context.Background(), "user-123", "secret", "wrong")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCode)
+
+		err = twoFactorUseCase.Disable(context.Background(), "user-123", "secret", code)
+
+		var loginLockedError *domain.LoginLockedError
+
+		assert.ErrorAs(t, err, &loginLockedError)
+		mockTwoFactorRepository.AssertNumberOfCalls(t, "Disable", 1)
+	})
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	enabledAt := time.Now()
+	expiresAt := time.Now().Add(time.Minute)
+	expiredAt := time.Now().Add(-time.Minute)
+	user := domain.User{ID: "user-123", TOTPSecret: totpSecret, TwoFactorEnabledAt: &enabledAt}
+	loginChallenge := domain.LoginChallenge{ID: "challenge-123", UserID: "user-123", ExpiresAt: &expiresAt}
+
+	newTwoFactorUseCase := func(loginChallenge domain.LoginChallenge) (domain.TwoFactorUseCase, *mocks.TwoFactorRepository) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockTwoFactorRepository := new(mocks.TwoFactorRepository)
+
+		mockTwoFactorRepository.On("GetChallenge", mock.Anything, mock.AnythingOfType("*domain.LoginChallenge"), helpers.HashToken("token")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.LoginChallenge) = loginChallenge
+		}).Return(nil).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = user
+		}).Return(nil).Maybe()
+
+		return userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute), mockTwoFactorRepository
+	}
+
+	t.Run("verify with a TOTP code", func(t *testing.T) {
+		twoFactorUseCase, mockTwoFactorRepository := newTwoFactorUseCase(loginChallenge)
+		code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+		mockTwoFactorRepository.On("FailChallenge", mock.Anything, "challenge-123", 5).Return(nil).Once()
+		mockTwoFactorRepository.On("UseStep", mock.Anything, "user-123", mock.AnythingOfType("int64")).Return(nil).Once()
+		mockTwoFactorRepository.On("DeleteChallenge", mock.Anything, "challenge-123").Return(nil).Once()
+
+		userID, err := twoFactorUseCase.Verify(context.Background(), "token", code)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", userID)
+		mockTwoFactorRepository.AssertExpectations(t)
+	})
+
+	t.Run("verify with a recovery code", func(t *testing.T) {
+		twoFactorUseCase, mockTwoFactorRepository := newTwoFactorUseCase(loginChallenge)
+
+		mockTwoFactorRepository.On("FailChallenge", mock.Anything, "challenge-123", 5).Return(nil).Once()
+		mockTwoFactorRepository.On("UseRecoveryCode", mock.Anything, "user-123", helpers.HashToken("abcde23456")).Return(nil).Once()
+		mockTwoFactorRepository.On("DeleteChallenge", mock.Anything, "challenge-123").Return(nil).Once()
+
+		userID, err := twoFactorUseCase.Verify(context.Background(), "token", "ABCDE-23456")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", userID)
+		mockTwoFactorRepository.AssertExpectations(t)
+	})
+
+	t.Run("verify with a wrong code", func(t *testing.T) {
+		twoFactorUseCase, mockTwoFactorRepository := newTwoFactorUseCase(loginChallenge)
+
+		mockTwoFactorRepository.On("FailChallenge", mock.Anything, "challenge-123", 5).Return(nil).Once()
+		mockTwoFactorRepository.On("UseRecoveryCode", mock.Anything, "user-123", mock.AnythingOfType("string")).Return(domain.ErrInvalidCode).Once()
+
+		_, err := twoFactorUseCase.Verify(context.Background(), "token", "wrong")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCode)
+		mockTwoFactorRepository.AssertExpectations(t)
+		mockTwoFactorRepository.AssertNotCalled(t, "DeleteChallenge", mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify once parallel attempts used up the challenge", func(t *testing.T) {
+		twoFactorUseCase, mockTwoFactorRepository := newTwoFactorUseCase(loginChallenge)
+		code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+		mockTwoFactorRepository.On("FailChallenge", mock.Anything, "challenge-123", 5).Return(domain.ErrChallengeInvalid).Once()
+
+		_, err := twoFactorUseCase.Verify(context.Background(), "token", code)
+
+		assert.ErrorIs(t, err, domain.ErrChallengeInvalid)
+		mockTwoFactorRepository.AssertNotCalled(t, "UseStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify right after a wrong code on another challenge", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockTwoFactorRepository := new(mocks.TwoFactorRepository)
+		twoFactorUseCase := userUseCase.NewTwoFactorUseCase(mockUserRepository, mockTwoFactorRepository, loginAttemptRepository.NewLoginAttemptRepository(), 5, 15*time.Minute)
+		code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+		for _, token := range []string{"token", "another-token"} {
+			mockTwoFactorRepository.On("GetChallenge", mock.Anything, mock.AnythingOfType("*domain.LoginChallenge"), helpers.HashToken(token)).Run(func(args mock.Arguments) {
+				*args.Get(1).(*domain.LoginChallenge) = loginChallenge
+			}).Return(nil).Once()
+		}
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = user
+		}).Return(nil)
+		mockTwoFactorRepository.On("FailChallenge", mock.Anything, "challenge-123", 5).Return(nil).Twice()
+		mockTwoFactorRepository.On("UseRecoveryCode", mock.Anything, "user-123", mock.AnythingOfType("string")).Return(domain.ErrInvalidCode).Once()
+
+		_, err := twoFactorUseCase.Verify(context.Background(), "token", "wrong")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCode)
+
+		_, err = twoFactorUseCase.Verify(context.Background(), "another-token", code)
+
+		var loginLockedError *domain.LoginLockedError
+
+		assert.ErrorAs(t, err, &loginLockedError)
+		mockTwoFactorRepository.AssertNotCalled(t, "UseStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	for name, loginChallenge := range map[string]domain.LoginChallenge{
+		"verify with an expired challenge":         {ID: "challenge-123", UserID: "user-123", ExpiresAt: &expiredAt},
+		"verify with too many wrong codes entered": {ID: "challenge-123", UserID: "user-123", ExpiresAt: &expiresAt, Attempts: 5},
+	} {
+		loginChallenge := loginChallenge
+
+		t.Run(name, func(t *testing.T) {
+			twoFactorUseCase, mockTwoFactorRepository := newTwoFactorUseCase(loginChallenge)
+			code, _ := helpers.TOTPCode(totpSecret, time.Now())
+
+			_, err := twoFactorUseCase.Verify(context.Background(), "token", code)
+
+			assert.ErrorIs(t, err, domain.ErrChallengeInvalid)
+			mockTwoFactorRepository.AssertNotCalled(t, "UseStep", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
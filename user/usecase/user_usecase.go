@@ -1,16 +1,26 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"mygram-byferdiansyah/domain"
+	"mygram-byferdiansyah/image/imaging"
+	"net/http"
+	"unicode/utf8"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
 type userUseCase struct {
 	userRepository domain.UserRepository
+	blobStore      domain.BlobStore
+	maxUploadSize  int64
 }
 
-func NewUserUseCase(userRepository domain.UserRepository) *userUseCase {
-	return &userUseCase{userRepository}
+func NewUserUseCase(userRepository domain.UserRepository, blobStore domain.BlobStore, maxUploadSize int64) *userUseCase {
+	return &userUseCase{userRepository, blobStore, maxUploadSize}
 }
 
 func (userUseCase *userUseCase) Register(ctx context.Context, user *domain.User) (err error) {
@@ -29,7 +39,47 @@ func (userUseCase *userUseCase) Login(ctx context.Context, user *domain.User) (e
 	return
 }
 
+func (userUseCase *userUseCase) Get(ctx context.Context, users *[]domain.User) (err error) {
+	if err = userUseCase.userRepository.Get(ctx, users); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) GetByID(ctx context.Context, user *domain.User, id string) (err error) {
+	if err = userUseCase.userRepository.GetByID(ctx, user, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) GetByUsername(ctx context.Context, user *domain.User, username string) (err error) {
+	if err = userUseCase.userRepository.GetByUsername(ctx, user, username); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) GetProfile(ctx context.Context, user *domain.User, username string) (err error) {
+	if err = userUseCase.userRepository.GetProfile(ctx, user, username); err != nil {
+		return err
+	}
+
+	return
+}
+
 func (userUseCase *userUseCase) Edit(ctx context.Context, user domain.User) (u domain.User, err error) {
+	if utf8.RuneCountInString(user.DisplayName) > domain.MaxDisplayNameLength {
+		return u, domain.ErrDisplayNameTooLong
+	}
+
+	if utf8.RuneCountInString(user.Bio) > domain.MaxBioLength {
+		return u, domain.ErrBioTooLong
+	}
+
 	if u, err = userUseCase.userRepository.Edit(ctx, user); err != nil {
 		return u, err
 	}
@@ -37,6 +87,59 @@ func (userUseCase *userUseCase) Edit(ctx context.Context, user domain.User) (u d
 	return u, nil
 }
 
+// SetAvatar stores file, without its GPS location, as the profile image of
+// the user and removes the one it replaces. The type is sniffed from the
+// content rather than trusted from the client.
+func (userUseCase *userUseCase) SetAvatar(ctx context.Context, user *domain.User, file io.Reader) (err error) {
+	content, err := io.ReadAll(io.LimitReader(file, userUseCase.maxUploadSize+1))
+
+	if err != nil {
+		return err
+	}
+
+	if int64(len(content)) > userUseCase.maxUploadSize {
+		return domain.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(content)
+	extension, ok := imaging.Extension(contentType)
+
+	if !ok {
+		return domain.ErrUnsupportedImageType
+	}
+
+	if _, _, err = imaging.Decode(content); err != nil {
+		return domain.ErrUnsupportedImageType
+	}
+
+	content = imaging.StripGPS(content)
+
+	if err = userUseCase.userRepository.GetByID(ctx, user, user.ID); err != nil {
+		return err
+	}
+
+	ID, _ := gonanoid.New(16)
+	key := fmt.Sprintf("avatars/%s/%s%s", user.ID, ID, extension)
+
+	if err = userUseCase.blobStore.Put(ctx, key, bytes.NewReader(content), int64(len(content)), contentType); err != nil {
+		return err
+	}
+
+	if err = userUseCase.userRepository.SetProfileImage(ctx, user.ID, domain.FileURLPath(key)); err != nil {
+		userUseCase.blobStore.Delete(ctx, key)
+
+		return err
+	}
+
+	if previous, ok := user.AvatarKey(); ok {
+		userUseCase.blobStore.Delete(ctx, previous)
+	}
+
+	user.ProfileImageUrl = domain.FileURLPath(key)
+
+	return
+}
+
 func (userUseCase *userUseCase) Delete(ctx context.Context, id string) (err error) {
 	if err = userUseCase.userRepository.Delete(ctx, id); err != nil {
 		return err
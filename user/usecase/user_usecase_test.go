@@ -1,11 +1,15 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"image"
+	pngEncoder "image/png"
 	"mygram-byferdiansyah/domain"
 	"mygram-byferdiansyah/domain/mocks"
 	"mygram-byferdiansyah/helpers"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,7 +30,7 @@ func TestRegister(t *testing.T) {
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.BlobStore), 1024)
 
 	t.Run("register user correctly", func(t *testing.T) {
 		tempMockRegisterUser := domain.User{
@@ -254,7 +258,7 @@ func TestLogin(t *testing.T) {
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.BlobStore), 1024)
 
 	t.Run("login user correctly", func(t *testing.T) {
 		tempMockLoginUser := domain.User{
@@ -369,7 +373,7 @@ func TestEdit(t *testing.T) {
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.BlobStore), 1024)
 
 	t.Run("edit user correctly", func(t *testing.T) {
 		tempMockEditUser := domain.User{
@@ -526,6 +530,117 @@ func TestEdit(t *testing.T) {
 	})
 }
 
+func TestEditProfile(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.BlobStore), 1024)
+
+	t.Run("edit user with a bio too long", func(t *testing.T) {
+		_, err := userUseCase.Edit(context.Background(), domain.User{Bio: strings.Repeat("a", domain.MaxBioLength+1)})
+
+		assert.ErrorIs(t, err, domain.ErrBioTooLong)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("edit user with a display name too long", func(t *testing.T) {
+		_, err := userUseCase.Edit(context.Background(), domain.User{DisplayName: strings.Repeat("é", domain.MaxDisplayNameLength+1)})
+
+		assert.ErrorIs(t, err, domain.ErrDisplayNameTooLong)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestGetProfile(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.BlobStore), 1024)
+
+	t.Run("get profile correctly", func(t *testing.T) {
+		mockUserRepository.On("GetProfile", mock.Anything, mock.AnythingOfType("*domain.User"), "johndoe").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-123", Username: "johndoe", ImageCount: 2}
+		}).Return(nil).Once()
+
+		user := domain.User{}
+
+		err := userUseCase.GetProfile(context.Background(), &user, "johndoe")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, user.ImageCount)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestSetAvatar(t *testing.T) {
+	var encoded bytes.Buffer
+
+	_ = pngEncoder.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+
+	png := encoded.Bytes()
+
+	mockUserRepository := new(mocks.UserRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, mockBlobStore, 1024)
+
+	t.Run("set avatar and remove the previous one", func(t *testing.T) {
+		user := domain.User{ID: "user-123"}
+
+		mockUserRepository.On("GetByID", mock.Anything, &user, "user-123").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.User).ProfileImageUrl = domain.FileURLPath("avatars/user-123/old.png")
+		}).Return(nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "avatars/user-123/") && strings.HasSuffix(key, ".png")
+		}), mock.Anything, int64(len(png)), "image/png").Return(nil).Once()
+		mockUserRepository.On("SetProfileImage", mock.Anything, "user-123", mock.AnythingOfType("string")).Return(nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "avatars/user-123/old.png").Return(nil).Once()
+
+		err := userUseCase.SetAvatar(context.Background(), &user, bytes.NewReader(png))
+
+		assert.NoError(t, err)
+
+		key, ok := domain.FileKey(user.ProfileImageUrl)
+
+		assert.True(t, ok)
+		assert.True(t, strings.HasPrefix(key, "avatars/user-123/"))
+		mockUserRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("set avatar without removing a stored file that isn't an avatar of the user", func(t *testing.T) {
+		user := domain.User{ID: "user-123"}
+
+		mockUserRepository.On("GetByID", mock.Anything, &user, "user-123").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.User).ProfileImageUrl = domain.FileURLPath("images/user-234/photo.png")
+		}).Return(nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, int64(len(png)), "image/png").Return(nil).Once()
+		mockUserRepository.On("SetProfileImage", mock.Anything, "user-123", mock.AnythingOfType("string")).Return(nil).Once()
+
+		err := userUseCase.SetAvatar(context.Background(), &user, bytes.NewReader(png))
+
+		assert.NoError(t, err)
+		mockBlobStore.AssertNotCalled(t, "Delete", mock.Anything, "images/user-234/photo.png")
+		mockUserRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("set avatar with a file that isn't an image", func(t *testing.T) {
+		user := domain.User{ID: "user-123"}
+
+		err := userUseCase.SetAvatar(context.Background(), &user, strings.NewReader("<html><script>alert(1)</script></html>"))
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedImageType)
+		mockUserRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("set avatar larger than the upload limit", func(t *testing.T) {
+		user := domain.User{ID: "user-123"}
+
+		err := userUseCase.SetAvatar(context.Background(), &user, bytes.NewReader(append(png, make([]byte, 1024)...)))
+
+		assert.ErrorIs(t, err, domain.ErrImageTooLarge)
+		mockUserRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+}
+
 func TestDelete(t *testing.T) {
 	mockUser := domain.User{
 		ID:       "user-123",
@@ -536,7 +651,7 @@ func TestDelete(t *testing.T) {
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.BlobStore), 1024)
 
 	t.Run("delete user correctly", func(t *testing.T) {
 		mockUserRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
@@ -1,6 +1,9 @@
 package utils
 
-import "time"
+import (
+	"mygram-byferdiansyah/helpers"
+	"time"
+)
 
 type RegisterUser struct {
 	Age      uint   `json:"age" example:"8"`
@@ -27,7 +30,9 @@ type LoginUser struct {
 }
 
 type LoggedinUser struct {
-	Token string `json:"token" example:"the token generated here"`
+	Token        string `json:"token" example:"the access token generated here"`
+	RefreshToken string `json:"refresh_token" example:"the refresh token generated here"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"`
 }
 
 type ResponseDataLoggedinUser struct {
@@ -35,17 +40,149 @@ type ResponseDataLoggedinUser struct {
 	Data   LoggedinUser `json:"data"`
 }
 
+type LoginChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required" example:"true"`
+	ChallengeToken    string `json:"challenge_token" example:"the challenge token generated here"`
+	ExpiresIn         int64  `json:"expires_in" example:"300"`
+}
+
+type ResponseDataLoginChallenge struct {
+	Status string         `json:"status" example:"success"`
+	Data   LoginChallenge `json:"data"`
+}
+
+type LoginTwoFactor struct {
+	ChallengeToken string `json:"challenge_token" binding:"required" example:"the challenge token generated here"`
+	Code           string `json:"code" binding:"required" example:"123456"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret        string   `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OtpauthURI    string   `json:"otpauth_uri" example:"otpauth://totp/MyGram:johndoe@example.com?secret=JBSWY3DPEHPK3PXP&issuer=MyGram"`
+	RecoveryCodes []string `json:"recovery_codes" example:"k3m9p-x7q2r"`
+}
+
+type ResponseDataTwoFactorEnrollment struct {
+	Status string              `json:"status" example:"success"`
+	Data   TwoFactorEnrollment `json:"data"`
+}
+
+type ConfirmTwoFactor struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type Reauthenticate struct {
+	Password string `json:"password" binding:"required" example:"secret"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3m9p-x7q2r"`
+}
+
+type ResponseDataRecoveryCodes struct {
+	Status string        `json:"status" example:"success"`
+	Data   RecoveryCodes `json:"data"`
+}
+
+type ResponseMessageTwoFactor struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"two-factor authentication has been successfully enabled"`
+}
+
+type CreateAPIKey struct {
+	Name      string     `json:"name" binding:"required,max=50" example:"backup script"`
+	Scopes    []string   `json:"scopes" binding:"required" example:"images:read"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2030-01-01T00:00:00Z"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id" example:"the API key id generated here"`
+	Name       string     `json:"name" example:"backup script"`
+	Prefix     string     `json:"prefix" example:"mgk_Ab3dE6gH"`
+	Scopes     []string   `json:"scopes" example:"images:read"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at"`
+}
+
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"the API key generated here"`
+}
+
+type ResponseDataCreatedAPIKey struct {
+	Status string        `json:"status" example:"success"`
+	Data   CreatedAPIKey `json:"data"`
+}
+
+type ResponseDataAPIKeys struct {
+	Status string   `json:"status" example:"success"`
+	Data   []APIKey `json:"data"`
+}
+
+type ResponseMessageAPIKey struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"the API key has been successfully revoked"`
+}
+
+type RefreshUser struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"the refresh token generated here"`
+}
+
+type ResponseMessageLoggedoutUser struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"you have been successfully logged out"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"secret"`
+	NewPassword     string `json:"new_password" binding:"required" example:"newsecret"`
+}
+
+type ForgotPassword struct {
+	Email string `json:"email" binding:"required,email" example:"johndoe@example.com"`
+}
+
+type ResetPassword struct {
+	Token    string `json:"token" binding:"required" example:"the reset token sent by email"`
+	Password string `json:"password" binding:"required" example:"newsecret"`
+}
+
+type ResponseMessagePassword struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"your password has been successfully reset"`
+}
+
+type VerifyEmail struct {
+	Token string `json:"token" binding:"required" example:"the verification token sent by email"`
+}
+
+type ResponseMessageVerification struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"your email has been successfully verified"`
+}
+
 type EditUser struct {
-	Email    string `json:"email" example:"newjohndoe@example.com"`
-	Username string `json:"username" example:"newjohndoe"`
+	Email       string `json:"email" form:"email" binding:"omitempty,email" example:"newjohndoe@example.com"`
+	Username    string `json:"username" form:"username" example:"newjohndoe"`
+	DisplayName string `json:"display_name" form:"display_name" example:"John Doe"`
+	Bio         string `json:"bio" form:"bio" example:"Taking pictures of cats"`
+	Private     *bool  `json:"private" form:"private" example:"false"`
 }
 
 type EditedUser struct {
-	ID        string     `json:"id" example:"here is the generated user id"`
-	Email     string     `json:"email" example:"newjohndoe@example.com"`
-	Username  string     `json:"username" example:"newjohndoe"`
-	Age       uint       `json:"age" example:"8"`
-	UpdatedAt *time.Time `json:"updated_at" example:"the edited at generated here"`
+	ID              string     `json:"id" example:"here is the generated user id"`
+	Email           string     `json:"email" example:"newjohndoe@example.com"`
+	Username        string     `json:"username" example:"newjohndoe"`
+	DisplayName     string     `json:"display_name" example:"John Doe"`
+	Bio             string     `json:"bio" example:"Taking pictures of cats"`
+	ProfileImageUrl string     `json:"profile_image_url,omitempty" example:"the signed link of the avatar"`
+	Private         bool       `json:"private" example:"false"`
+	EmailVerified   bool       `json:"email_verified" example:"false"`
+	Age             uint       `json:"age" example:"8"`
+	UpdatedAt       *time.Time `json:"updated_at" example:"the edited at generated here"`
 }
 
 type ResponseDataEditedUser struct {
@@ -53,6 +190,31 @@ type ResponseDataEditedUser struct {
 	Data   EditedUser `json:"data"`
 }
 
+type SocialMedia struct {
+	ID             string `json:"id" example:"socialmedia-123"`
+	Name           string `json:"name" example:"Instagram"`
+	SocialMediaUrl string `json:"social_media_url" example:"https://www.instagram.com/johndoe"`
+}
+
+type Profile struct {
+	ID              string         `json:"id" example:"user-123"`
+	Username        string         `json:"username" example:"johndoe"`
+	DisplayName     string         `json:"display_name" example:"John Doe"`
+	Bio             string         `json:"bio" example:"Taking pictures of cats"`
+	ProfileImageUrl string         `json:"profile_image_url,omitempty" example:"the signed link of the avatar"`
+	Private         bool           `json:"private" example:"false"`
+	ImageCount      int            `json:"image_count" example:"1"`
+	FollowerCount   int            `json:"follower_count" example:"1"`
+	FollowingCount  int            `json:"following_count" example:"1"`
+	SocialMedias    []*SocialMedia `json:"social_medias"`
+	CreatedAt       *time.Time     `json:"created_at" example:"the created at generated here"`
+}
+
+type ResponseDataProfile struct {
+	Status string  `json:"status" example:"success"`
+	Data   Profile `json:"data"`
+}
+
 type ResponseMessageDeletedUser struct {
 	Status  string `json:"status" example:"success"`
 	Message string `json:"message" example:"your account has been successfully deleted"`
@@ -62,3 +224,8 @@ type ResponseMessage struct {
 	Status string `json:"status" example:"fail"`
 	Data   string `json:"data" example:"the error explained here"`
 }
+
+// JWKS is the JSON Web Key Set access tokens can be verified with.
+type JWKS struct {
+	Keys []helpers.JWK `json:"keys"`
+}